@@ -1,13 +1,28 @@
 package config
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v2"
 )
 
+// ErrInvalidJSONConfig is returned if JSON config is malformed.
+var ErrInvalidJSONConfig = errors.New("invalid JSON config")
+
+const (
+	// DefaultSentrySampleRate is used if sentry_sample_rate is not set: all events are sent.
+	DefaultSentrySampleRate = 1.0
+	// DefaultSentryTracesSampleRate is used if sentry_traces_sample_rate is not set: tracing is disabled.
+	DefaultSentryTracesSampleRate = 0.0
+)
+
 // Configuration settings data structure.
 type Configuration interface {
 	GetVersion() string
@@ -23,6 +38,28 @@ type Configuration interface {
 	IsDebug() bool
 }
 
+// Optional configuration interfaces. They are not a part of Configuration to keep custom implementations
+// compatible, Engine checks them via type assertion. Config implements all of them.
+type (
+	// SentryScrubConfigProvider provides fields which must be scrubbed from the Sentry events.
+	SentryScrubConfigProvider interface {
+		GetSentryScrubConfig() SentryScrubConfig
+	}
+	// SentryEnvironmentProvider provides Sentry environment name.
+	SentryEnvironmentProvider interface {
+		GetSentryEnvironment() string
+	}
+	// SentrySampleRateProvider provides Sentry error and traces sample rates.
+	SentrySampleRateProvider interface {
+		GetSentrySampleRate() float64
+		GetSentryTracesSampleRate() float64
+	}
+	// TrustedProxiesProvider provides trusted proxies list for the HTTP server.
+	TrustedProxiesProvider interface {
+		GetTrustedProxies() []string
+	}
+)
+
 // InfoInterface transport settings data structure.
 type InfoInterface interface {
 	GetName() string
@@ -33,69 +70,88 @@ type InfoInterface interface {
 
 // Config struct.
 type Config struct {
-	HTTPClientConfig *HTTPClientConfig `yaml:"http_client"`
-	ConfigAWS        AWS               `yaml:"config_aws"`
-	TransportInfo    Info              `yaml:"transport_info"`
-	HTTPServer       HTTPServerConfig  `yaml:"http_server"`
-	ZabbixConfig     ZabbixConfig      `yaml:"zabbix"`
-	Version          string            `yaml:"version"`
-	SentryDSN        string            `yaml:"sentry_dsn"`
-	Database         DatabaseConfig    `yaml:"database"`
-	UpdateInterval   int               `yaml:"update_interval"`
-	LogFormat        string            `yaml:"log_format"`
-	Debug            bool              `yaml:"debug"`
+	HTTPClientConfig       *HTTPClientConfig `yaml:"http_client" json:"http_client"`
+	SentrySampleRate       *float64          `yaml:"sentry_sample_rate" json:"sentry_sample_rate"`
+	SentryTracesSampleRate *float64          `yaml:"sentry_traces_sample_rate" json:"sentry_traces_sample_rate"`
+	ConfigAWS              AWS               `yaml:"config_aws" json:"config_aws"`
+	TransportInfo          Info              `yaml:"transport_info" json:"transport_info"`
+	HTTPServer             HTTPServerConfig  `yaml:"http_server" json:"http_server"`
+	ZabbixConfig           ZabbixConfig      `yaml:"zabbix" json:"zabbix"`
+	Version                string            `yaml:"version" json:"version"`
+	SentryDSN              string            `yaml:"sentry_dsn" json:"sentry_dsn"`
+	SentryScrub            SentryScrubConfig `yaml:"sentry_scrub" json:"sentry_scrub"`
+	SentryEnvironment      string            `yaml:"sentry_environment" json:"sentry_environment"`
+	TrustedProxies         []string          `yaml:"trusted_proxies" json:"trusted_proxies"`
+	Database               DatabaseConfig    `yaml:"database" json:"database"`
+	UpdateInterval         int               `yaml:"update_interval" json:"update_interval"`
+	LogFormat              string            `yaml:"log_format" json:"log_format"`
+	Debug                  bool              `yaml:"debug" json:"debug"`
 }
 
 // Info struct.
 type Info struct {
-	Name     string `yaml:"name"`
-	Code     string `yaml:"code"`
-	LogoPath string `yaml:"logo_path"`
-	Secret   string `yaml:"secret"`
+	Name     string `yaml:"name" json:"name"`
+	Code     string `yaml:"code" json:"code"`
+	LogoPath string `yaml:"logo_path" json:"logo_path"`
+	Secret   string `yaml:"secret" json:"secret"`
+}
+
+// SentryScrubConfig configures which request data will be removed from the Sentry events.
+type SentryScrubConfig struct {
+	// Fields are removed from the request body, headers, query string and event contexts. Case-insensitive.
+	Fields []string `yaml:"fields" json:"fields"`
+	// DropRequestBody removes request body from the events entirely.
+	DropRequestBody bool `yaml:"drop_request_body" json:"drop_request_body"`
 }
 
 // AWS struct.
 type AWS struct {
-	AccessKeyID     string `yaml:"access_key_id"`
-	SecretAccessKey string `yaml:"secret_access_key"`
-	Endpoint        string `yaml:"endpoint"`
-	Region          string `yaml:"region"`
-	Bucket          string `yaml:"bucket"`
-	FolderName      string `yaml:"folder_name"`
-	ContentType     string `yaml:"content_type"`
+	AccessKeyID     string `yaml:"access_key_id" json:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key" json:"secret_access_key"`
+	Endpoint        string `yaml:"endpoint" json:"endpoint"`
+	Region          string `yaml:"region" json:"region"`
+	Bucket          string `yaml:"bucket" json:"bucket"`
+	FolderName      string `yaml:"folder_name" json:"folder_name"`
+	ContentType     string `yaml:"content_type" json:"content_type"`
 }
 
 // DatabaseConfig struct.
 type DatabaseConfig struct {
-	Connection         interface{} `yaml:"connection"`
-	TablePrefix        string      `yaml:"table_prefix"`
-	MaxOpenConnections int         `yaml:"max_open_connections"`
-	MaxIdleConnections int         `yaml:"max_idle_connections"`
-	ConnectionLifetime int         `yaml:"connection_lifetime"`
-	Logging            bool        `yaml:"logging"`
+	Connection         interface{} `yaml:"connection" json:"connection"`
+	TablePrefix        string      `yaml:"table_prefix" json:"table_prefix"`
+	MaxOpenConnections int         `yaml:"max_open_connections" json:"max_open_connections"`
+	MaxIdleConnections int         `yaml:"max_idle_connections" json:"max_idle_connections"`
+	ConnectionLifetime int         `yaml:"connection_lifetime" json:"connection_lifetime"`
+	PingInterval       int         `yaml:"ping_interval" json:"ping_interval"`
+	Logging            bool        `yaml:"logging" json:"logging"`
 }
 
 // HTTPClientConfig struct.
+// MinTLSVersion is a minimal supported TLS version: "1.0", "1.1", "1.2" or "1.3" (TLS 1.2 is used if empty).
+// DisableHTTP2 forces HTTP/1.1 for the proxies which don't support HTTP/2.
 type HTTPClientConfig struct {
-	SSLVerification *bool         `yaml:"ssl_verification"`
-	MockAddress     string        `yaml:"mock_address"`
-	MockedDomains   []string      `yaml:"mocked_domains"`
-	Timeout         time.Duration `yaml:"timeout"`
+	SSLVerification *bool         `yaml:"ssl_verification" json:"ssl_verification"`
+	MockAddress     string        `yaml:"mock_address" json:"mock_address"`
+	MinTLSVersion   string        `yaml:"min_tls_version" json:"min_tls_version"`
+	MockedDomains   []string      `yaml:"mocked_domains" json:"mocked_domains"`
+	MockedPaths     []string      `yaml:"mocked_paths" json:"mocked_paths"`
+	Timeout         time.Duration `yaml:"timeout" json:"timeout"`
+	DisableHTTP2    bool          `yaml:"disable_http2" json:"disable_http2"`
 }
 
 // HTTPServerConfig struct.
 type HTTPServerConfig struct {
-	Host   string `yaml:"host"`
-	Listen string `yaml:"listen"`
+	Host   string `yaml:"host" json:"host"`
+	Listen string `yaml:"listen" json:"listen"`
 }
 
 // ZabbixConfig contains information about Zabbix connection.
 type ZabbixConfig struct {
-	ServerHost   string `yaml:"server_host"`
-	Host         string `yaml:"host"`
-	ServerPort   int    `yaml:"server_port"`
-	MetricPrefix string `yaml:"metric_prefix"`
-	Interval     uint64 `yaml:"interval"`
+	ServerHost   string `yaml:"server_host" json:"server_host"`
+	Host         string `yaml:"host" json:"host"`
+	ServerPort   int    `yaml:"server_port" json:"server_port"`
+	MetricPrefix string `yaml:"metric_prefix" json:"metric_prefix"`
+	Interval     uint64 `yaml:"interval" json:"interval"`
 }
 
 // NewConfig reads configuration file and returns config instance
@@ -106,6 +162,93 @@ func NewConfig(path string) *Config {
 	return (&Config{}).LoadConfig(path)
 }
 
+// NewConfigFromJSON reads JSON configuration file and returns config instance. JSON config has the same
+// structure and keys as the YAML one, but it's decoded by the encoding/json.
+// Usage:
+//
+//	NewConfigFromJSON("config.json")
+func NewConfigFromJSON(path string) *Config {
+	c := &Config{}
+	return c.LoadConfigFromJSONData(c.GetConfigData(path))
+}
+
+// LoadConfig reads configuration file and detects its format by the extension: files with ".json" extension
+// are parsed as JSON, all other files are parsed as YAML.
+// Usage:
+//
+//	LoadConfig(os.Getenv("CONFIG_PATH"))
+func LoadConfig(path string) *Config {
+	if isJSONConfig(path) {
+		return NewConfigFromJSON(path)
+	}
+
+	return NewConfig(path)
+}
+
+// LoadConfigStrict works like LoadConfig, but returns an error instead of panic and rejects unknown keys.
+// Use it to catch typos in the config (e.g. "sentry_dns" instead of "sentry_dsn") at load time.
+// Usage:
+//
+//	cfg, err := LoadConfigStrict("config.yml")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func LoadConfigStrict(path string) (*Config, error) {
+	c := &Config{}
+	if err := LoadConfigStrictInto(path, c); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// LoadConfigStrictInto works like LoadConfigStrict, but loads config into the provided target. Use it if transport
+// config embeds Config and contains additional keys, otherwise these keys will be rejected.
+// JSON config is decoded by the encoding/json, so additional fields must have json tags too.
+// Usage:
+//
+//	type TransportConfig struct {
+//		config.Config `yaml:",inline"`
+//		BotToken      string `yaml:"bot_token" json:"bot_token"`
+//	}
+//
+//	var cfg TransportConfig
+//	if err := LoadConfigStrictInto("config.yml", &cfg); err != nil {
+//		log.Fatal(err)
+//	}
+func LoadConfigStrictInto(path string, target interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if isJSONConfig(path) {
+		err = unmarshalJSONStrict(data, target)
+	} else {
+		err = yaml.UnmarshalStrict(data, target)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot load config %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// unmarshalJSONStrict decodes JSON data into the target and rejects unknown fields.
+func unmarshalJSONStrict(data []byte, target interface{}) error {
+	if !json.Valid(data) {
+		return ErrInvalidJSONConfig
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(target)
+}
+
+func isJSONConfig(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json")
+}
+
 // LoadConfig read & load configuration file.
 func (c *Config) LoadConfig(path string) *Config {
 	return c.LoadConfigFromData(c.GetConfigData(path))
@@ -120,6 +263,32 @@ func (c *Config) LoadConfigFromData(data []byte) *Config {
 	return c
 }
 
+// LoadConfigFromJSONData loads config from JSON byte sequence. JSON keys are the same as the YAML ones.
+// JSON is decoded by the encoding/json because yaml.v2 doesn't support all JSON escape sequences (e.g. "\/").
+func (c *Config) LoadConfigFromJSONData(data []byte) *Config {
+	if !json.Valid(data) {
+		panic(ErrInvalidJSONConfig)
+	}
+
+	if err := json.Unmarshal(data, c); err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+// LoadConfigFromDataStrict loads config from byte sequence. Unlike LoadConfigFromData it returns an error
+// if data contains keys which are not present in the config.
+func (c *Config) LoadConfigFromDataStrict(data []byte) error {
+	return yaml.UnmarshalStrict(data, c)
+}
+
+// LoadConfigFromJSONDataStrict loads config from JSON byte sequence. Unlike LoadConfigFromJSONData it returns
+// an error if data contains keys which are not present in the config.
+func (c *Config) LoadConfigFromJSONDataStrict(data []byte) error {
+	return unmarshalJSONStrict(data, c)
+}
+
 // GetConfigData returns config file data in form of byte sequence.
 func (c *Config) GetConfigData(path string) []byte {
 	var err error
@@ -142,6 +311,41 @@ func (c Config) GetSentryDSN() string {
 	return c.SentryDSN
 }
 
+// GetSentryScrubConfig returns configuration for the Sentry events scrubbing.
+func (c Config) GetSentryScrubConfig() SentryScrubConfig {
+	return c.SentryScrub
+}
+
+// GetSentryEnvironment returns environment for the Sentry events (e.g. "staging").
+func (c Config) GetSentryEnvironment() string {
+	return c.SentryEnvironment
+}
+
+// GetSentrySampleRate returns the rate of the error events which will be sent to Sentry (from 0.0 to 1.0).
+// DefaultSentrySampleRate is used if it's not set. Note that sentry-go treats 0.0 as 1.0, leave sentry_dsn
+// empty to disable Sentry.
+func (c Config) GetSentrySampleRate() float64 {
+	if c.SentrySampleRate == nil {
+		return DefaultSentrySampleRate
+	}
+	return *c.SentrySampleRate
+}
+
+// GetSentryTracesSampleRate returns the rate of the transactions which will be sent to Sentry (from 0.0 to 1.0).
+// DefaultSentryTracesSampleRate is used if it's not set.
+func (c Config) GetSentryTracesSampleRate() float64 {
+	if c.SentryTracesSampleRate == nil {
+		return DefaultSentryTracesSampleRate
+	}
+	return *c.SentryTracesSampleRate
+}
+
+// GetTrustedProxies returns networks or IPs of the proxies which are trusted to set the client IP headers.
+// gin defaults are used if it's empty.
+func (c Config) GetTrustedProxies() []string {
+	return c.TrustedProxies
+}
+
 // GetVersion transport version.
 func (c Config) GetVersion() string {
 	return c.Version
@@ -0,0 +1,51 @@
+package core
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/db/models"
+	"github.com/retailcrm/mg-transport-core/v2/core/logger"
+)
+
+// DefaultCredentialRefreshInterval is an interval of the job returned by NewCredentialRefreshJob.
+const DefaultCredentialRefreshInterval = time.Hour
+
+// NewCredentialRefreshJob returns regular job which verifies credentials of every connection from the provider.
+// onInvalid is called for every connection which failed verification, it can be used to deactivate the connection.
+// Job runs every DefaultCredentialRefreshInterval, Interval can be changed before the job registration.
+// Usage:
+//
+//	job := core.NewCredentialRefreshJob(repo.ActiveConnections, func(conn models.Connection) error {
+//		_, _, err := app.GetAPIClient(conn.URL, conn.Key, scopes)
+//		return err
+//	}, func(conn models.Connection, err error) {
+//		repo.Deactivate(conn.ID)
+//	})
+//	_ = app.JobManager().RegisterJob("refreshCredentials", job)
+func NewCredentialRefreshJob(
+	provider func() []models.Connection,
+	verify func(models.Connection) error,
+	onInvalid func(models.Connection, error),
+) *Job {
+	return &Job{
+		Command: func(log logger.Logger) error {
+			var invalid int
+			for _, conn := range provider() {
+				if err := verify(conn); err != nil {
+					invalid++
+					log.Warn("connection credentials are invalid",
+						zap.Int("connectionID", conn.ID), logger.Err(err))
+					onInvalid(conn, err)
+				}
+			}
+			log.Debug("connection credentials were verified", zap.Int("invalid", invalid))
+			return nil
+		},
+		ErrorHandler: DefaultJobErrorHandler(),
+		PanicHandler: DefaultJobPanicHandler(),
+		Interval:     DefaultCredentialRefreshInterval,
+		Regular:      true,
+	}
+}
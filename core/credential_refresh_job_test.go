@@ -0,0 +1,59 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/db/models"
+	"github.com/retailcrm/mg-transport-core/v2/core/util/testutil"
+)
+
+func TestNewCredentialRefreshJob(t *testing.T) {
+	errInvalid := errors.New("invalid api key")
+	var verified []int
+	invalid := map[int]error{}
+	job := NewCredentialRefreshJob(func() []models.Connection {
+		return []models.Connection{{ID: 1}, {ID: 2}, {ID: 3}}
+	}, func(conn models.Connection) error {
+		verified = append(verified, conn.ID)
+		if conn.ID == 2 {
+			return errInvalid
+		}
+		return nil
+	}, func(conn models.Connection, err error) {
+		invalid[conn.ID] = err
+	})
+
+	assert.True(t, job.Regular)
+	assert.Equal(t, DefaultCredentialRefreshInterval, job.Interval)
+
+	log := testutil.NewBufferedLoggerSilent()
+	require.NoError(t, job.Command(log))
+	assert.Equal(t, []int{1, 2, 3}, verified)
+	assert.Equal(t, map[int]error{2: errInvalid}, invalid)
+
+	items, err := testutil.NewJSONRecordScanner(log).ScanAll()
+	require.NoError(t, err)
+	require.NotEmpty(t, items)
+	assert.Equal(t, "connection credentials are invalid", items[0].Message)
+	assert.Equal(t, "invalid api key", items[0].Context["error"])
+}
+
+func TestNewCredentialRefreshJob_JobManager(t *testing.T) {
+	done := make(chan struct{})
+	job := NewCredentialRefreshJob(func() []models.Connection {
+		return []models.Connection{{ID: 1}}
+	}, func(models.Connection) error {
+		return errors.New("invalid")
+	}, func(models.Connection, error) {
+		close(done)
+	})
+
+	manager := NewJobManager()
+	require.NoError(t, manager.RegisterJob("refreshCredentials", job))
+	require.NoError(t, manager.RunJobOnce("refreshCredentials"))
+	<-done
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/jinzhu/gorm"
 	"gopkg.in/gormigrate.v1"
@@ -22,9 +23,28 @@ type Migrate struct {
 	prepared   bool
 }
 
+const (
+	// appliedAtColumn is the column for migration applying time. It's added to the existing migrations table
+	// before migrating.
+	appliedAtColumn = "applied_at"
+	// appliedAtDefault is the applied_at column default. The column is filled by the INSERT which registers
+	// the migration, so the timestamp is written in the migration transaction. clock_timestamp() is used instead
+	// of now() because the latter returns the same value for all migrations applied in one transaction.
+	appliedAtDefault = "(clock_timestamp() AT TIME ZONE 'UTC')"
+)
+
 // MigrationInfo with migration info.
 type MigrationInfo struct {
-	ID string `gorm:"column:id; type:varchar(255)"`
+	ID        string     `gorm:"column:id; type:varchar(255)"`
+	AppliedAt *time.Time `gorm:"column:applied_at; type:timestamp"`
+}
+
+// MigrationStatus describes registered migration state.
+type MigrationStatus struct {
+	ID      string
+	Applied bool
+	// AppliedAt will be nil for the migrations applied before the applied_at column was added.
+	AppliedAt *time.Time
 }
 
 // TableName for MigrationInfo.
@@ -67,6 +87,9 @@ func (m *Migrate) Migrate() error {
 	}
 
 	if len(m.migrations) > 0 {
+		if err := m.initSchema(); err != nil {
+			return err
+		}
 		return m.GORMigrate.Migrate()
 	}
 
@@ -100,6 +123,10 @@ func (m *Migrate) MigrateTo(version string) error {
 		return err
 	}
 
+	if err := m.initSchema(); err != nil {
+		return err
+	}
+
 	current := m.Current()
 	switch {
 	case current > version:
@@ -118,6 +145,10 @@ func (m *Migrate) MigrateNextTo(version string) error {
 	}
 
 	if next, err := m.NextFrom(version); err == nil {
+		if err := m.initSchema(); err != nil {
+			return err
+		}
+
 		current := m.Current()
 		switch {
 		case current < next:
@@ -174,7 +205,7 @@ func (m *Migrate) Current() string {
 	}
 
 	if !m.db.HasTable(MigrationInfo{}) {
-		if err := m.db.CreateTable(MigrationInfo{}).Error; err == nil {
+		if err := m.createMigrationsTable(); err == nil {
 			fmt.Println("info => created migrations table")
 		} else {
 			panic(err.Error())
@@ -191,6 +222,48 @@ func (m *Migrate) Current() string {
 	return migrationInfo.ID
 }
 
+// Status returns state of all registered migrations sorted by ID.
+func (m *Migrate) Status() ([]MigrationStatus, error) {
+	if err := m.prepareMigrations(); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.versions))
+	for _, id := range m.versions {
+		info, ok := applied[id]
+		statuses = append(statuses, MigrationStatus{ID: id, Applied: ok, AppliedAt: info.AppliedAt})
+	}
+
+	return statuses, nil
+}
+
+// HasPending returns true and the sorted IDs of registered migrations which weren't applied yet.
+// It doesn't execute or create anything, so it can be used to check the database before the deploy.
+func (m *Migrate) HasPending() (bool, []string, error) {
+	if err := m.prepareMigrations(); err != nil {
+		return false, nil, err
+	}
+
+	applied, err := m.appliedMigrations()
+	if err != nil {
+		return false, nil, err
+	}
+
+	var pending []string
+	for _, id := range m.versions {
+		if _, ok := applied[id]; !ok {
+			pending = append(pending, id)
+		}
+	}
+
+	return len(pending) > 0, pending, nil
+}
+
 // NextFrom returns next version from passed version.
 func (m *Migrate) NextFrom(version string) (string, error) {
 	for key, ver := range m.versions {
@@ -226,6 +299,66 @@ func (m *Migrate) Close() error {
 	return m.db.Close()
 }
 
+// appliedMigrations returns migrations from the migrations table. Nothing is created or altered here.
+func (m *Migrate) appliedMigrations() (map[string]MigrationInfo, error) {
+	applied := map[string]MigrationInfo{}
+	if !m.db.HasTable(MigrationInfo{}) {
+		return applied, nil
+	}
+
+	var infos []MigrationInfo
+	query := m.db.Model(MigrationInfo{})
+	if !m.db.Dialect().HasColumn(MigrationInfo{}.TableName(), appliedAtColumn) {
+		query = query.Select("id")
+	}
+	if err := query.Find(&infos).Error; err != nil {
+		return nil, fmt.Errorf("cannot fetch applied migrations: %w", err)
+	}
+
+	for _, info := range infos {
+		applied[info.ID] = info
+	}
+
+	return applied, nil
+}
+
+// initSchema creates the migrations table or adds the applied_at column to the existing table.
+// Migrations applied before the column was added will have NULL applying time.
+func (m *Migrate) initSchema() error {
+	table := MigrationInfo{}.TableName()
+	if !m.db.HasTable(table) {
+		return m.createMigrationsTable()
+	}
+
+	if m.db.Dialect().HasColumn(table, appliedAtColumn) {
+		return nil
+	}
+
+	// Default is set separately, otherwise existing rows will be filled with the current time.
+	tx := m.db.Begin()
+	err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s TIMESTAMP", table, appliedAtColumn)).Error
+	if err == nil {
+		err = tx.Exec(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s",
+			table, appliedAtColumn, appliedAtDefault)).Error
+	}
+	if err == nil {
+		err = tx.Commit().Error
+	} else {
+		tx.Rollback()
+	}
+	if err != nil {
+		return fmt.Errorf("cannot add %s column: %w", appliedAtColumn, err)
+	}
+
+	return nil
+}
+
+// createMigrationsTable creates the migrations table with the applied_at column.
+func (m *Migrate) createMigrationsTable() error {
+	return m.db.Exec(fmt.Sprintf("CREATE TABLE %s (id VARCHAR(255) PRIMARY KEY, %s TIMESTAMP DEFAULT %s)",
+		MigrationInfo{}.TableName(), appliedAtColumn, appliedAtDefault)).Error
+}
+
 // prepareMigrations prepare migrate.
 func (m *Migrate) prepareMigrations() error {
 	var (
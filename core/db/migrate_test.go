@@ -2,8 +2,10 @@ package db
 
 import (
 	"database/sql"
+	"errors"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jinzhu/gorm"
@@ -54,6 +56,57 @@ func (m *MigrateTest) RefreshMigrate() {
 	}
 }
 
+// expectInitSchema expects migrations table to be created or applied_at column to be added to it.
+func (m *MigrateTest) expectInitSchema(hasTable, hasColumn bool) {
+	m.expectHasTable(hasTable)
+	if !hasTable {
+		m.mock.
+			ExpectExec(regexp.QuoteMeta(`CREATE TABLE migrations (id VARCHAR(255) PRIMARY KEY, ` +
+				`applied_at TIMESTAMP DEFAULT (clock_timestamp() AT TIME ZONE 'UTC'))`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		return
+	}
+
+	count := 0
+	if hasColumn {
+		count = 1
+	}
+	m.mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM INFORMATION_SCHEMA.columns WHERE table_name = $1 AND column_name = $2`)).
+		WithArgs("migrations", "applied_at").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+	if !hasColumn {
+		m.mock.ExpectBegin()
+		m.mock.
+			ExpectExec(regexp.QuoteMeta(`ALTER TABLE migrations ADD COLUMN applied_at TIMESTAMP`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		m.mock.
+			ExpectExec(regexp.QuoteMeta(`ALTER TABLE migrations ALTER COLUMN applied_at ` +
+				`SET DEFAULT (clock_timestamp() AT TIME ZONE 'UTC')`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		m.mock.ExpectCommit()
+	}
+}
+
+func (m *MigrateTest) expectHasTable(hasTable bool) {
+	count := 0
+	if hasTable {
+		count = 1
+	}
+	m.mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM INFORMATION_SCHEMA.tables WHERE table_name = $1`)).
+		WithArgs("migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+}
+
+// expectCurrent expects the current version to be fetched from the empty migrations table.
+func (m *MigrateTest) expectCurrent() {
+	m.expectHasTable(true)
+	m.mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "migrations"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "applied_at"}))
+}
+
 func (m *MigrateTest) MigrationTestModelFirst() *gormigrate.Migration {
 	return &gormigrate.Migration{
 		ID: "1",
@@ -136,10 +189,9 @@ func (m *MigrateTest) Test_Migrate_Success_NoMigrations() {
 	m.RefreshMigrate()
 	m.Migrate.Add(m.MigrationTestModelFirst())
 
+	m.expectInitSchema(false, false)
 	m.mock.ExpectBegin()
-	m.mock.
-		ExpectExec(regexp.QuoteMeta(`CREATE TABLE migrations (id VARCHAR(255) PRIMARY KEY)`)).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	m.expectHasTable(true)
 	m.mock.
 		ExpectQuery(regexp.QuoteMeta(`SELECT id FROM migrations`)).
 		WillReturnRows(sqlmock.NewRows([]string{"1"}))
@@ -159,10 +211,38 @@ func (m *MigrateTest) Test_Migrate_Success() {
 	m.RefreshMigrate()
 	m.Migrate.Add(m.MigrationTestModelFirst())
 
+	m.expectInitSchema(false, false)
 	m.mock.ExpectBegin()
+	m.expectHasTable(true)
 	m.mock.
-		ExpectExec(regexp.QuoteMeta(`CREATE TABLE migrations (id VARCHAR(255) PRIMARY KEY)`)).
+		ExpectQuery(regexp.QuoteMeta(`SELECT id FROM migrations`)).
+		WillReturnRows(sqlmock.NewRows([]string{"1"}))
+	m.mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "migrations"  WHERE (id = $1)`)).
+		WithArgs("1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	m.mock.
+		ExpectExec(regexp.QuoteMeta(`CREATE TABLE "test_model" ("name" varchar(70) )`)).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	m.mock.
+		ExpectExec(regexp.QuoteMeta(`INSERT INTO migrations (id) VALUES ($1)`)).
+		WithArgs("1").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	m.mock.ExpectCommit()
+
+	err := m.Migrate.Migrate()
+
+	assert.NoError(m.T(), err)
+	assert.NoError(m.T(), m.mock.ExpectationsWereMet())
+}
+
+func (m *MigrateTest) Test_Migrate_AddsAppliedAtColumn() {
+	m.RefreshMigrate()
+	m.Migrate.Add(m.MigrationTestModelFirst())
+
+	m.expectInitSchema(true, false)
+	m.mock.ExpectBegin()
+	m.expectHasTable(true)
 	m.mock.
 		ExpectQuery(regexp.QuoteMeta(`SELECT id FROM migrations`)).
 		WillReturnRows(sqlmock.NewRows([]string{"1"}))
@@ -185,6 +265,125 @@ func (m *MigrateTest) Test_Migrate_Success() {
 	assert.NoError(m.T(), m.mock.ExpectationsWereMet())
 }
 
+func (m *MigrateTest) Test_Migrate_AddAppliedAtColumnError() {
+	m.RefreshMigrate()
+	m.Migrate.Add(m.MigrationTestModelFirst())
+
+	m.expectHasTable(true)
+	m.mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM INFORMATION_SCHEMA.columns WHERE table_name = $1 AND column_name = $2`)).
+		WithArgs("migrations", "applied_at").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	m.mock.ExpectBegin()
+	m.mock.
+		ExpectExec(regexp.QuoteMeta(`ALTER TABLE migrations ADD COLUMN applied_at TIMESTAMP`)).
+		WillReturnError(errors.New("permission denied"))
+	m.mock.ExpectRollback()
+
+	err := m.Migrate.Migrate()
+
+	require.Error(m.T(), err)
+	assert.Equal(m.T(), "cannot add applied_at column: permission denied", err.Error())
+	assert.NoError(m.T(), m.mock.ExpectationsWereMet())
+}
+
+func (m *MigrateTest) Test_Status() {
+	m.RefreshMigrate()
+	m.Migrate.Add(m.MigrationTestModelFirst())
+	m.Migrate.Add(m.MigrationTestModelSecond())
+	appliedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	m.mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM INFORMATION_SCHEMA.tables WHERE table_name = $1`)).
+		WithArgs("migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	m.mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM INFORMATION_SCHEMA.columns WHERE table_name = $1 AND column_name = $2`)).
+		WithArgs("migrations", "applied_at").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	m.mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "migrations"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "applied_at"}).AddRow("1", appliedAt))
+
+	statuses, err := m.Migrate.Status()
+
+	require.NoError(m.T(), err)
+	require.Len(m.T(), statuses, 2)
+	assert.Equal(m.T(), "1", statuses[0].ID)
+	assert.True(m.T(), statuses[0].Applied)
+	require.NotNil(m.T(), statuses[0].AppliedAt)
+	assert.Equal(m.T(), appliedAt, *statuses[0].AppliedAt)
+	assert.Equal(m.T(), MigrationStatus{ID: "2"}, statuses[1])
+	assert.NoError(m.T(), m.mock.ExpectationsWereMet())
+}
+
+func (m *MigrateTest) Test_HasPending() {
+	m.RefreshMigrate()
+	m.Migrate.Add(m.MigrationTestModelFirst())
+	m.Migrate.Add(m.MigrationTestModelSecond())
+	m.Migrate.Add(&gormigrate.Migration{ID: "3"})
+
+	m.mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM INFORMATION_SCHEMA.tables WHERE table_name = $1`)).
+		WithArgs("migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	m.mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM INFORMATION_SCHEMA.columns WHERE table_name = $1 AND column_name = $2`)).
+		WithArgs("migrations", "applied_at").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	m.mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT id FROM "migrations"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("2"))
+
+	hasPending, pending, err := m.Migrate.HasPending()
+
+	require.NoError(m.T(), err)
+	assert.True(m.T(), hasPending)
+	assert.Equal(m.T(), []string{"1", "3"}, pending)
+	assert.NoError(m.T(), m.mock.ExpectationsWereMet())
+}
+
+func (m *MigrateTest) Test_HasPending_NoTable() {
+	m.RefreshMigrate()
+	m.Migrate.Add(m.MigrationTestModelFirst())
+
+	m.mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM INFORMATION_SCHEMA.tables WHERE table_name = $1`)).
+		WithArgs("migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	hasPending, pending, err := m.Migrate.HasPending()
+
+	require.NoError(m.T(), err)
+	assert.True(m.T(), hasPending)
+	assert.Equal(m.T(), []string{"1"}, pending)
+	assert.NoError(m.T(), m.mock.ExpectationsWereMet())
+}
+
+func (m *MigrateTest) Test_HasPending_AllApplied() {
+	m.RefreshMigrate()
+	m.Migrate.Add(m.MigrationTestModelFirst())
+
+	m.mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM INFORMATION_SCHEMA.tables WHERE table_name = $1`)).
+		WithArgs("migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	m.mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM INFORMATION_SCHEMA.columns WHERE table_name = $1 AND column_name = $2`)).
+		WithArgs("migrations", "applied_at").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	m.mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "migrations"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "applied_at"}).AddRow("1", nil))
+
+	hasPending, pending, err := m.Migrate.HasPending()
+
+	require.NoError(m.T(), err)
+	assert.False(m.T(), hasPending)
+	assert.Empty(m.T(), pending)
+	assert.NoError(m.T(), m.mock.ExpectationsWereMet())
+}
+
 func (m *MigrateTest) Test_Rollback_Fail_NilDB() {
 	m.RefreshMigrate()
 	m.Migrate.SetDB(nil)
@@ -231,13 +430,10 @@ func (m *MigrateTest) Test_MigrateTo_DoNothing() {
 	m.RefreshMigrate()
 	m.Migrate.Add(m.MigrationTestModelFirst())
 
-	m.mock.
-		ExpectExec(regexp.QuoteMeta(`CREATE TABLE "migrations" ("id" varchar(255) , PRIMARY KEY ("id"))`)).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	m.expectInitSchema(false, false)
+	m.expectCurrent()
 	m.mock.ExpectBegin()
-	m.mock.
-		ExpectExec(regexp.QuoteMeta(`CREATE TABLE migrations (id VARCHAR(255) PRIMARY KEY)`)).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	m.expectHasTable(true)
 	m.mock.
 		ExpectQuery(regexp.QuoteMeta(`SELECT id FROM migrations`)).
 		WillReturnRows(sqlmock.NewRows([]string{"1"}))
@@ -257,13 +453,10 @@ func (m *MigrateTest) Test_MigrateTo() {
 	m.RefreshMigrate()
 	m.Migrate.Add(m.MigrationTestModelFirst())
 
-	m.mock.
-		ExpectExec(regexp.QuoteMeta(`CREATE TABLE "migrations" ("id" varchar(255) , PRIMARY KEY ("id"))`)).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	m.expectInitSchema(false, false)
+	m.expectCurrent()
 	m.mock.ExpectBegin()
-	m.mock.
-		ExpectExec(regexp.QuoteMeta(`CREATE TABLE migrations (id VARCHAR(255) PRIMARY KEY)`)).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	m.expectHasTable(true)
 	m.mock.
 		ExpectQuery(regexp.QuoteMeta(`SELECT id FROM migrations`)).
 		WillReturnRows(sqlmock.NewRows([]string{"1"}))
@@ -305,13 +498,10 @@ func (m *MigrateTest) Test_MigrateNextTo() {
 	m.Migrate.Add(m.MigrationTestModelFirst())
 	m.Migrate.Add(m.MigrationTestModelSecond())
 
-	m.mock.
-		ExpectExec(regexp.QuoteMeta(`CREATE TABLE "migrations" ("id" varchar(255) , PRIMARY KEY ("id"))`)).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	m.expectInitSchema(false, false)
+	m.expectCurrent()
 	m.mock.ExpectBegin()
-	m.mock.
-		ExpectExec(regexp.QuoteMeta(`CREATE TABLE migrations (id VARCHAR(255) PRIMARY KEY)`)).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	m.expectHasTable(true)
 	m.mock.
 		ExpectQuery(regexp.QuoteMeta(`SELECT id FROM migrations`)).
 		WillReturnRows(sqlmock.NewRows([]string{"1"}))
@@ -343,9 +533,7 @@ func (m *MigrateTest) Test_MigratePreviousTo() {
 	m.Migrate.Add(m.MigrationTestModelFirst())
 	m.Migrate.Add(m.MigrationTestModelSecond())
 
-	m.mock.
-		ExpectExec(regexp.QuoteMeta(`CREATE TABLE "migrations" ("id" varchar(255) , PRIMARY KEY ("id"))`)).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	m.expectInitSchema(false, false)
 
 	err := m.Migrate.MigratePreviousTo(m.MigrationTestModelSecond().ID)
 
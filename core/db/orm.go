@@ -1,6 +1,8 @@
 package db
 
 import (
+	"database/sql"
+	"errors"
 	"time"
 
 	"github.com/jinzhu/gorm"
@@ -13,7 +15,8 @@ import (
 
 // ORM struct.
 type ORM struct {
-	DB *gorm.DB
+	DB           *gorm.DB
+	maxIdleConns int
 }
 
 // NewORM will init new database connection.
@@ -30,6 +33,27 @@ func (orm *ORM) CreateDB(config config.DatabaseConfig) {
 		panic(err)
 	}
 
+	orm.DB = configureDB(db, config)
+	orm.maxIdleConns = config.MaxIdleConnections
+}
+
+// OpenFromSQL wraps an existing *sql.DB into *gorm.DB using provided dialect and applies
+// pool and logging settings from the config. It can be used to inject mock or custom connections.
+// Connection field of the config is ignored.
+func OpenFromSQL(sqlDB *sql.DB, dialect string, cfg config.DatabaseConfig) (*gorm.DB, error) {
+	if sqlDB == nil {
+		return nil, errors.New("sql.DB must not be nil")
+	}
+
+	db, err := gorm.Open(dialect, sqlDB)
+	if err != nil {
+		return nil, err
+	}
+
+	return configureDB(db, cfg), nil
+}
+
+func configureDB(db *gorm.DB, config config.DatabaseConfig) *gorm.DB {
 	db.DB().SetConnMaxLifetime(time.Duration(config.ConnectionLifetime) * time.Second)
 	db.DB().SetMaxOpenConns(config.MaxOpenConnections)
 	db.DB().SetMaxIdleConns(config.MaxIdleConnections)
@@ -37,7 +61,13 @@ func (orm *ORM) CreateDB(config config.DatabaseConfig) {
 	db.SingularTable(true)
 	db.LogMode(config.Logging)
 
-	orm.DB = db
+	return db
+}
+
+// resetIdleConnections closes all idle connections in the pool and restores the configured pool size.
+func (orm *ORM) resetIdleConnections() {
+	orm.DB.DB().SetMaxIdleConns(0)
+	orm.DB.DB().SetMaxIdleConns(orm.maxIdleConns)
 }
 
 // CloseDB close database connection.
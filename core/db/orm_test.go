@@ -73,3 +73,31 @@ func TestORM_CloseDB(t *testing.T) {
 
 	assert.NoError(t, dbMock.ExpectationsWereMet())
 }
+
+func TestOpenFromSQL(t *testing.T) {
+	sqlDB, dbMock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	db, err := OpenFromSQL(sqlDB, "postgres", config.DatabaseConfig{
+		MaxOpenConnections: 5,
+		MaxIdleConnections: 5,
+		ConnectionLifetime: 100,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, db)
+	assert.Equal(t, 5, db.DB().Stats().MaxOpenConnections)
+
+	dbMock.ExpectExec("UPDATE test SET name").WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, db.Exec("UPDATE test SET name = ?", "value").Error)
+
+	dbMock.ExpectClose()
+	require.NoError(t, db.Close())
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestOpenFromSQL_NilDB(t *testing.T) {
+	db, err := OpenFromSQL(nil, "postgres", config.DatabaseConfig{})
+
+	assert.Error(t, err)
+	assert.Nil(t, db)
+}
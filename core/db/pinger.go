@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/logger"
+)
+
+// Pinger checks the database connection on the interval. Idle connections are dropped after a failed ping,
+// so the connection pool will open new connections instead of reusing the dead ones (e.g. after the failover).
+type Pinger struct {
+	orm      *ORM
+	logger   logger.Logger
+	done     chan struct{}
+	interval time.Duration
+	mu       sync.Mutex
+	healthy  atomic.Bool
+}
+
+// NewPinger constructs new Pinger for the provided ORM. Logger can be nil.
+func NewPinger(orm *ORM, interval time.Duration, log logger.Logger) *Pinger {
+	if log == nil {
+		log = logger.NewNil()
+	}
+	p := &Pinger{
+		orm:      orm,
+		logger:   log,
+		interval: interval,
+	}
+	p.healthy.Store(true)
+	return p
+}
+
+// StartPinger creates Pinger and runs it in the background. Use Pinger.Stop to stop it.
+func (orm *ORM) StartPinger(interval time.Duration, log logger.Logger) *Pinger {
+	p := NewPinger(orm, interval, log)
+	done := make(chan struct{})
+	p.done = done
+	go p.run(done)
+	return p
+}
+
+// Ping verifies that the database connection is still alive.
+func (orm *ORM) Ping(ctx context.Context) error {
+	return orm.DB.DB().PingContext(ctx)
+}
+
+// Run pings the database until Stop is called. Blocks until pinger is stopped.
+func (p *Pinger) Run() {
+	p.mu.Lock()
+	if p.done != nil {
+		p.mu.Unlock()
+		return
+	}
+	p.done = make(chan struct{})
+	done := p.done
+	p.mu.Unlock()
+
+	p.run(done)
+}
+
+// Stop the pinger.
+func (p *Pinger) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.done == nil {
+		return
+	}
+
+	close(p.done)
+	p.done = nil
+}
+
+// Healthy returns false if the last ping has failed.
+func (p *Pinger) Healthy() bool {
+	return p.healthy.Load()
+}
+
+func (p *Pinger) run(done chan struct{}) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			p.ping()
+		}
+	}
+}
+
+func (p *Pinger) ping() {
+	ctx, cancel := context.WithTimeout(context.Background(), p.interval)
+	defer cancel()
+
+	if err := p.orm.Ping(ctx); err != nil {
+		p.healthy.Store(false)
+		p.logger.Error("database ping failed, dropping idle connections", logger.Err(err))
+		p.orm.resetIdleConnections()
+		return
+	}
+
+	if !p.healthy.Swap(true) {
+		p.logger.Info("database connection restored")
+	}
+}
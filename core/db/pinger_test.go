@@ -0,0 +1,102 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/config"
+	"github.com/retailcrm/mg-transport-core/v2/core/util/testutil"
+)
+
+func newPingerTestORM(t *testing.T) (*ORM, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+
+	mock.ExpectPing()
+	orm := NewORM(config.DatabaseConfig{
+		Connection:         db,
+		MaxOpenConnections: 10,
+		MaxIdleConnections: 10,
+	})
+	return orm, mock
+}
+
+func TestORM_Ping(t *testing.T) {
+	orm, mock := newPingerTestORM(t)
+	mock.ExpectPing()
+	mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+
+	assert.NoError(t, orm.Ping(context.Background()))
+	assert.EqualError(t, orm.Ping(context.Background()), "connection refused")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPinger_Pings(t *testing.T) {
+	orm, mock := newPingerTestORM(t)
+	mock.ExpectPing()
+	mock.ExpectPing()
+
+	pinger := orm.StartPinger(time.Millisecond, nil)
+	defer pinger.Stop()
+
+	assert.Eventually(t, func() bool {
+		return mock.ExpectationsWereMet() == nil
+	}, time.Second, time.Millisecond)
+}
+
+func TestPinger_Recovers(t *testing.T) {
+	orm, mock := newPingerTestORM(t)
+	mock.ExpectPing()
+
+	log := testutil.NewBufferedLogger()
+	pinger := NewPinger(orm, time.Millisecond, log)
+	pinger.healthy.Store(false)
+	pinger.ping()
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.True(t, pinger.Healthy())
+	assert.Contains(t, log.String(), "database connection restored")
+}
+
+func TestPinger_LogsFailure(t *testing.T) {
+	orm, mock := newPingerTestORM(t)
+	mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+
+	log := testutil.NewBufferedLogger()
+	pinger := NewPinger(orm, time.Millisecond, log)
+	pinger.ping()
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.False(t, pinger.Healthy())
+	assert.Contains(t, log.String(), "database ping failed")
+	assert.Contains(t, log.String(), "connection refused")
+}
+
+func TestPinger_Stop(t *testing.T) {
+	orm, _ := newPingerTestORM(t)
+	pinger := NewPinger(orm, time.Hour, nil)
+
+	done := make(chan struct{})
+	go func() {
+		pinger.Run()
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		pinger.mu.Lock()
+		defer pinger.mu.Unlock()
+		return pinger.done != nil
+	}, time.Second, time.Millisecond)
+	pinger.Stop()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("pinger was not stopped")
+	}
+}
@@ -0,0 +1,355 @@
+package db
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/config"
+)
+
+const hasTableQuery = `SELECT count(*) FROM information_schema.tables WHERE table_schema = CURRENT_SCHEMA() AND table_name = $1 AND table_type = $2` // nolint:lll
+
+type TestModel struct {
+	Name string `gorm:"column:name;type:varchar(70)"`
+}
+
+func (TestModel) TableName() string {
+	return "test_model"
+}
+
+type MigrateTest struct {
+	suite.Suite
+	DB      *gorm.DB
+	Migrate *Migrate
+	mock    sqlmock.Sqlmock
+}
+
+func (m *MigrateTest) SetupSuite() {
+	require.NotEmpty(m.T(), (MigrationInfo{}).TableName())
+	m.RefreshMigrate()
+}
+
+func (m *MigrateTest) RefreshMigrate() {
+	var (
+		db  *sql.DB
+		err error
+	)
+
+	db, m.mock, err = sqlmock.New()
+	require.NoError(m.T(), err)
+
+	m.DB = NewORM(config.DatabaseConfig{Connection: db, Logging: true, MaxIdleConnections: 10}).DB
+	m.Migrate = &Migrate{
+		db:         m.DB,
+		prepared:   false,
+		migrations: map[string]*gormigrate.Migration{},
+	}
+}
+
+func (m *MigrateTest) MigrationTestModelFirst() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "1",
+		Migrate: func(db *gorm.DB) error {
+			return db.Migrator().CreateTable(&TestModel{})
+		},
+		Rollback: func(db *gorm.DB) error {
+			return db.Migrator().DropTable(&TestModel{})
+		},
+	}
+}
+
+func (m *MigrateTest) MigrationTestModelSecond() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "2",
+		Migrate: func(db *gorm.DB) error {
+			return db.Exec(`ALTER TABLE "test_model" ALTER COLUMN "name" TYPE varchar(100)`).Error
+		},
+		Rollback: func(db *gorm.DB) error {
+			return db.Exec(`ALTER TABLE "test_model" ALTER COLUMN "name" TYPE varchar(70)`).Error
+		},
+	}
+}
+
+// expectInitSchema sets expectations for the migrations table creation before migrating.
+func (m *MigrateTest) expectInitSchema() {
+	m.mock.ExpectQuery(regexp.QuoteMeta(hasTableQuery)).
+		WithArgs("migrations", "BASE TABLE").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	m.mock.
+		ExpectExec(regexp.QuoteMeta(`CREATE TABLE migrations (id VARCHAR(255) PRIMARY KEY, ` +
+			`applied_at TIMESTAMP DEFAULT (clock_timestamp() AT TIME ZONE 'UTC'))`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+// expectMigrationsTable sets expectations for the migrations table check in the migration transaction.
+func (m *MigrateTest) expectMigrationsTable() {
+	m.mock.ExpectQuery(regexp.QuoteMeta(hasTableQuery)).
+		WithArgs("migrations", "BASE TABLE").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+}
+
+func (m *MigrateTest) Test_Add() {
+	m.RefreshMigrate()
+	m.Migrate.Add(nil)
+	m.Migrate.Add(m.MigrationTestModelFirst())
+
+	assert.Equal(m.T(), 1, len(m.Migrate.migrations))
+	i, ok := m.Migrate.migrations["1"]
+	require.True(m.T(), ok)
+	assert.Equal(m.T(), "1", i.ID)
+	assert.NoError(m.T(), m.mock.ExpectationsWereMet())
+}
+
+func (m *MigrateTest) Test_prepareMigrations_NilDB() {
+	m.RefreshMigrate()
+	m.Migrate.db = nil
+	err := m.Migrate.prepareMigrations()
+
+	require.Error(m.T(), err)
+	assert.Equal(m.T(), "db must not be nil", err.Error())
+	assert.NoError(m.T(), m.mock.ExpectationsWereMet())
+}
+
+func (m *MigrateTest) Test_prepareMigrations_OK() {
+	m.RefreshMigrate()
+	m.Migrate.Add(m.MigrationTestModelFirst())
+	err := m.Migrate.prepareMigrations()
+
+	require.NoError(m.T(), err)
+	assert.True(m.T(), m.Migrate.prepared)
+	assert.NotNil(m.T(), m.Migrate.GORMigrate)
+	assert.NoError(m.T(), m.mock.ExpectationsWereMet())
+}
+
+func (m *MigrateTest) Test_Migrate_Fail_NilDB() {
+	m.RefreshMigrate()
+	m.Migrate.SetDB(nil)
+	m.Migrate.Add(m.MigrationTestModelFirst())
+
+	err := m.Migrate.Migrate()
+
+	assert.Error(m.T(), err)
+	assert.NoError(m.T(), m.mock.ExpectationsWereMet())
+}
+
+func (m *MigrateTest) Test_Migrate_Success_NoMigrations() {
+	m.RefreshMigrate()
+	m.Migrate.Add(m.MigrationTestModelFirst())
+
+	m.expectInitSchema()
+	m.mock.ExpectBegin()
+	m.expectMigrationsTable()
+	m.mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT id FROM "migrations"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+	m.mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "migrations" WHERE id = $1`)).
+		WithArgs("1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	m.mock.ExpectCommit()
+
+	err := m.Migrate.Migrate()
+
+	assert.NoError(m.T(), err)
+	assert.NoError(m.T(), m.mock.ExpectationsWereMet())
+}
+
+func (m *MigrateTest) Test_Migrate_Success() {
+	m.RefreshMigrate()
+	m.Migrate.Add(m.MigrationTestModelFirst())
+
+	m.expectInitSchema()
+	m.mock.ExpectBegin()
+	m.expectMigrationsTable()
+	m.mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT id FROM "migrations"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	m.mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "migrations" WHERE id = $1`)).
+		WithArgs("1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	m.mock.
+		ExpectExec(regexp.QuoteMeta(`CREATE TABLE "test_model" ("name" varchar(70))`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	m.mock.
+		ExpectExec(regexp.QuoteMeta(`INSERT INTO "migrations" ("id") VALUES ($1)`)).
+		WithArgs("1").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	m.mock.ExpectCommit()
+
+	err := m.Migrate.Migrate()
+
+	assert.NoError(m.T(), err)
+	assert.NoError(m.T(), m.mock.ExpectationsWereMet())
+}
+
+func (m *MigrateTest) Test_Migrate_AddsAppliedAtColumn() {
+	m.RefreshMigrate()
+	m.Migrate.Add(m.MigrationTestModelFirst())
+
+	m.mock.ExpectQuery(regexp.QuoteMeta(hasTableQuery)).
+		WithArgs("migrations", "BASE TABLE").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	m.mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM INFORMATION_SCHEMA.columns WHERE`)).
+		WithArgs("migrations", "applied_at").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	m.mock.ExpectBegin()
+	m.mock.
+		ExpectExec(regexp.QuoteMeta(`ALTER TABLE migrations ADD COLUMN applied_at TIMESTAMP`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.mock.
+		ExpectExec(regexp.QuoteMeta(`ALTER TABLE migrations ALTER COLUMN applied_at ` +
+			`SET DEFAULT (clock_timestamp() AT TIME ZONE 'UTC')`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.mock.ExpectCommit()
+	m.mock.ExpectBegin()
+	m.expectMigrationsTable()
+	m.mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT id FROM "migrations"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	m.mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "migrations" WHERE id = $1`)).
+		WithArgs("1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	m.mock.
+		ExpectExec(regexp.QuoteMeta(`CREATE TABLE "test_model" ("name" varchar(70))`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	m.mock.
+		ExpectExec(regexp.QuoteMeta(`INSERT INTO "migrations" ("id") VALUES ($1)`)).
+		WithArgs("1").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	m.mock.ExpectCommit()
+
+	err := m.Migrate.Migrate()
+
+	assert.NoError(m.T(), err)
+	assert.NoError(m.T(), m.mock.ExpectationsWereMet())
+}
+
+func (m *MigrateTest) Test_Rollback_Fail_NilDB() {
+	m.RefreshMigrate()
+	m.Migrate.SetDB(nil)
+	m.Migrate.Add(m.MigrationTestModelFirst())
+
+	err := m.Migrate.Rollback()
+
+	assert.Error(m.T(), err)
+	assert.NoError(m.T(), m.mock.ExpectationsWereMet())
+}
+
+func (m *MigrateTest) Test_Rollback_Fail_NoFirstMigration() {
+	m.RefreshMigrate()
+	m.Migrate.Add(m.MigrationTestModelFirst())
+	m.Migrate.first = nil
+
+	err := m.Migrate.Rollback()
+
+	assert.Error(m.T(), err)
+	assert.NoError(m.T(), m.mock.ExpectationsWereMet())
+}
+
+func (m *MigrateTest) Test_Rollback() {
+	m.RefreshMigrate()
+	m.Migrate.Add(m.MigrationTestModelFirst())
+	m.Migrate.Add(m.MigrationTestModelSecond())
+
+	m.mock.ExpectBegin()
+	m.mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "migrations" WHERE id = $1`)).
+		WithArgs("2").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	m.mock.
+		ExpectExec(regexp.QuoteMeta(`ALTER TABLE "test_model" ALTER COLUMN "name" TYPE varchar(70)`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	m.mock.
+		ExpectExec(regexp.QuoteMeta(`DELETE FROM "migrations" WHERE id = $1`)).
+		WithArgs("2").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	m.mock.ExpectCommit()
+	m.mock.ExpectBegin()
+	m.mock.
+		ExpectExec(regexp.QuoteMeta(`DROP TABLE IF EXISTS "test_model" CASCADE`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	m.mock.
+		ExpectExec(regexp.QuoteMeta(`DELETE FROM "migrations" WHERE id = $1`)).
+		WithArgs("1").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	m.mock.ExpectCommit()
+
+	err := m.Migrate.Rollback()
+
+	assert.NoError(m.T(), err)
+	assert.NoError(m.T(), m.mock.ExpectationsWereMet())
+}
+
+func (m *MigrateTest) Test_RollbackTo() {
+	m.RefreshMigrate()
+	m.Migrate.Add(m.MigrationTestModelFirst())
+	m.Migrate.Add(m.MigrationTestModelSecond())
+
+	m.mock.ExpectBegin()
+	m.mock.ExpectCommit()
+
+	err := m.Migrate.RollbackTo(m.MigrationTestModelSecond().ID)
+
+	assert.NoError(m.T(), err)
+	assert.NoError(m.T(), m.mock.ExpectationsWereMet())
+}
+
+func (m *MigrateTest) Test_Current() {
+	m.RefreshMigrate()
+
+	m.mock.ExpectQuery(regexp.QuoteMeta(hasTableQuery)).
+		WithArgs("migrations", "BASE TABLE").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	m.mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "migrations" ORDER BY "migrations"."id" DESC LIMIT $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("2"))
+
+	assert.Equal(m.T(), "2", m.Migrate.Current())
+	assert.NoError(m.T(), m.mock.ExpectationsWereMet())
+}
+
+func (m *MigrateTest) Test_NextFrom_PreviousFrom() {
+	m.RefreshMigrate()
+	m.Migrate.Add(m.MigrationTestModelFirst())
+	m.Migrate.Add(m.MigrationTestModelSecond())
+	require.NoError(m.T(), m.Migrate.prepareMigrations())
+
+	next, err := m.Migrate.NextFrom("1")
+	require.NoError(m.T(), err)
+	assert.Equal(m.T(), "2", next)
+
+	prev, err := m.Migrate.PreviousFrom("1")
+	require.NoError(m.T(), err)
+	assert.Equal(m.T(), "0", prev)
+
+	_, err = m.Migrate.NextFrom("2")
+	assert.Error(m.T(), err)
+}
+
+func (m *MigrateTest) Test_Close() {
+	m.RefreshMigrate()
+	m.mock.ExpectClose()
+	err := m.Migrate.Close()
+
+	assert.NoError(m.T(), err)
+	assert.NoError(m.T(), m.mock.ExpectationsWereMet())
+}
+
+func TestMigrate_Migrate(t *testing.T) {
+	assert.NotNil(t, Migrations())
+}
+
+func TestMigrate_Suite(t *testing.T) {
+	suite.Run(t, new(MigrateTest))
+}
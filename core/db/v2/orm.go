@@ -0,0 +1,82 @@
+// Package db provides GORM v2 (gorm.io/gorm) counterparts for the github.com/retailcrm/mg-transport-core/v2/core/db
+// package. It can be used by the transports which want to use GORM v2 instead of the legacy github.com/jinzhu/gorm.
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/config"
+)
+
+// ORM struct.
+type ORM struct {
+	DB *gorm.DB
+}
+
+// NewORM will init new database connection.
+func NewORM(config config.DatabaseConfig) *ORM {
+	orm := &ORM{}
+	orm.CreateDB(config)
+	return orm
+}
+
+// CreateDB connection using provided config. Connection can be either DSN string or an existing connection
+// (for example, *sql.DB).
+func (orm *ORM) CreateDB(config config.DatabaseConfig) {
+	dialector, err := postgresDialector(config.Connection)
+	if err != nil {
+		panic(err)
+	}
+
+	logLevel := gormLogger.Silent
+	if config.Logging {
+		logLevel = gormLogger.Info
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{
+		NamingStrategy: schema.NamingStrategy{
+			TablePrefix:   config.TablePrefix,
+			SingularTable: true,
+		},
+		Logger: gormLogger.Default.LogMode(logLevel),
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		panic(err)
+	}
+
+	sqlDB.SetConnMaxLifetime(time.Duration(config.ConnectionLifetime) * time.Second)
+	sqlDB.SetMaxOpenConns(config.MaxOpenConnections)
+	sqlDB.SetMaxIdleConns(config.MaxIdleConnections)
+
+	orm.DB = db
+}
+
+// CloseDB close database connection.
+func (orm *ORM) CloseDB() {
+	if sqlDB, err := orm.DB.DB(); err == nil {
+		_ = sqlDB.Close()
+	}
+}
+
+// postgresDialector returns PostgreSQL dialector for provided DSN or connection.
+func postgresDialector(conn interface{}) (gorm.Dialector, error) {
+	switch item := conn.(type) {
+	case string:
+		return postgres.Open(item), nil
+	case gorm.ConnPool:
+		return postgres.New(postgres.Config{Conn: item}), nil
+	default:
+		return nil, fmt.Errorf("unsupported connection type: %T", conn)
+	}
+}
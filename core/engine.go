@@ -1,17 +1,22 @@
 package core
 
 import (
+	"context"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"html/template"
 	"io/fs"
+	"net"
 	"net/http"
+	"os"
 	"sync"
 	"time"
 
 	"github.com/blacked/go-zabbix"
 	"github.com/getsentry/sentry-go"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
 	"github.com/gorilla/securecookie"
 	"github.com/gorilla/sessions"
 	metrics "github.com/retailcrm/zabbix-metrics-collector"
@@ -32,6 +37,19 @@ const (
 	AppContextKey                          = "app"
 )
 
+// UnixSocketFileMode is set for the socket created by Engine.RunUnix. It allows access for the owner and the group,
+// so the sidecar should share the group with the application.
+const UnixSocketFileMode fs.FileMode = 0660
+
+const unixSocketReadHeaderTimeout = 10 * time.Second
+
+const (
+	// SentryEnvironmentProduction is used as Sentry environment if it wasn't configured and debug mode is disabled.
+	SentryEnvironmentProduction = "production"
+	// SentryEnvironmentDevelopment is used as Sentry environment if it wasn't configured and debug mode is enabled.
+	SentryEnvironmentDevelopment = "development"
+)
+
 var boolTrue = true
 
 // DefaultHTTPClientConfig is a default config for HTTP client. It will be used by Engine for building HTTP client
@@ -66,24 +84,48 @@ func (a AppInfo) Release() string {
 	return fmt.Sprintf("%s (%s, built %s, commit \"%s\")", a.Version, a.Build, a.BuildDate, a.Commit)
 }
 
+// MiddlewarePosition determines where custom middleware is placed in the handler chain built by the Engine.
+type MiddlewarePosition uint8
+
+const (
+	// BeforeSentry places middleware right after the middleware which sets Engine into the context.
+	// Panics in such middleware will not be recovered by Sentry.
+	BeforeSentry MiddlewarePosition = iota
+	// AfterSentry places middleware after Sentry middlewares but before localization middleware.
+	AfterSentry
+	// AfterLocalization places middleware at the end of the chain after localization middleware.
+	AfterLocalization
+)
+
 // Engine struct.
 type Engine struct {
-	logger     logger.Logger
-	AppInfo    AppInfo
-	Sessions   sessions.Store
-	Config     config.Configuration
-	Zabbix     metrics.Transport
-	ginEngine  *gin.Engine
-	csrf       *middleware.CSRF
-	httpClient *http.Client
-	jobManager *JobManager
+	logger        logger.Logger
+	AppInfo       AppInfo
+	Sessions      sessions.Store
+	Config        config.Configuration
+	Zabbix        metrics.Transport
+	ginEngine     *gin.Engine
+	csrf          *middleware.CSRF
+	httpClient    *http.Client
+	jobManager    *JobManager
+	dbPinger      *db.Pinger
+	shutdownHooks []ShutdownHook
+	htmlRenderer  *swappableHTMLRender
+	middleware    map[MiddlewarePosition][]gin.HandlerFunc
 	db.ORM
 	Localizer
 	util.Utils
 	PreloadLanguages []language.Tag
+	// RequiredMessages are checked by Prepare in every loaded language, Prepare panics if some of them are missing.
+	RequiredMessages []string
+	// TemplateRenderer is preloaded by Prepare if it's set, Prepare panics if some of the templates are invalid.
+	// See Renderer.Register and Renderer.Preload.
+	TemplateRenderer *Renderer
 	Sentry
 	mutex    sync.RWMutex
 	prepared bool
+	// noLocalizationMiddleware disables LocalizationMiddleware in the handler chain.
+	noLocalizationMiddleware bool
 }
 
 // New Engine instance (must be configured manually, gin can be accessed via engine.Router() directly or
@@ -113,17 +155,59 @@ func (e *Engine) initGin() {
 	}
 
 	r := gin.New()
+	if provider, ok := e.Config.(config.TrustedProxiesProvider); ok && len(provider.GetTrustedProxies()) > 0 {
+		if err := r.SetTrustedProxies(provider.GetTrustedProxies()); err != nil {
+			panic(err)
+		}
+	}
 	r.Use(func(c *gin.Context) {
 		c.Set(AppContextKey, e)
 	})
+	r.Use(e.middleware[BeforeSentry]...)
 
 	e.buildSentryConfig()
 	e.InitSentrySDK()
 	r.Use(e.SentryMiddlewares()...)
-	r.Use(e.LocalizationMiddleware())
+	r.Use(e.middleware[AfterSentry]...)
+	if !e.noLocalizationMiddleware {
+		r.Use(e.LocalizationMiddleware())
+	}
+	r.Use(e.middleware[AfterLocalization]...)
 	e.ginEngine = r
 }
 
+// DisableLocalizationMiddleware removes LocalizationMiddleware from the handler chain. It's useful for the apps
+// without localized user-facing content because the middleware clones localizer for every request.
+// Localizer is still available for programmatic use. It must be called before the first Router call,
+// it will panic otherwise.
+func (e *Engine) DisableLocalizationMiddleware() *Engine {
+	if e.ginEngine != nil {
+		panic("localization middleware must be disabled before router initialization")
+	}
+
+	e.noLocalizationMiddleware = true
+	return e
+}
+
+// WithMiddleware adds middleware which will be placed at the provided position of the handler chain.
+// Middleware for the same position are added in the order of calls. It must be called before the first
+// Router call, it will panic otherwise.
+// Usage:
+//
+//	engine.WithMiddleware(core.AfterSentry, authMiddleware).
+//		WithMiddleware(core.AfterLocalization, logger.GinMiddleware(engine.Logger()))
+func (e *Engine) WithMiddleware(pos MiddlewarePosition, m ...gin.HandlerFunc) *Engine {
+	if e.ginEngine != nil {
+		panic("middleware must be added before router initialization")
+	}
+	if e.middleware == nil {
+		e.middleware = make(map[MiddlewarePosition][]gin.HandlerFunc)
+	}
+
+	e.middleware[pos] = append(e.middleware[pos], m...)
+	return e
+}
+
 // Prepare engine for start.
 func (e *Engine) Prepare() *Engine {
 	if e.prepared {
@@ -149,6 +233,11 @@ func (e *Engine) Prepare() *Engine {
 	if len(e.PreloadLanguages) > 0 {
 		e.Localizer.Preload(e.PreloadLanguages)
 	}
+	if len(e.RequiredMessages) > 0 {
+		if err := e.Localizer.RequireMessages(e.RequiredMessages); err != nil {
+			panic(err)
+		}
+	}
 
 	logFormat := "json"
 	if format := e.Config.GetLogFormat(); format != "" {
@@ -163,11 +252,36 @@ func (e *Engine) Prepare() *Engine {
 	e.Sentry.Logger = e.Logger()
 	e.buildSentryConfig()
 	e.Sentry.InitSentrySDK()
+	if interval := e.Config.GetDBConfig().PingInterval; interval > 0 {
+		e.dbPinger = e.StartPinger(time.Duration(interval)*time.Second, e.Logger())
+	}
+	if e.TemplateRenderer != nil {
+		if err := e.TemplateRenderer.Preload(); err != nil {
+			panic(err)
+		}
+	}
 	e.prepared = true
+	e.logStartupSummary()
 
 	return e
 }
 
+// logStartupSummary logs which subsystems were enabled by Prepare.
+func (e *Engine) logStartupSummary() {
+	languages := make([]string, 0, len(e.PreloadLanguages))
+	for _, tag := range e.PreloadLanguages {
+		languages = append(languages, tag.String())
+	}
+
+	e.Logger().Info("engine prepared",
+		zap.Bool("dbConfigured", e.DB != nil),
+		zap.Bool("sentryEnabled", e.Config.GetSentryDSN() != ""),
+		zap.Stringer("zabbixInterval", time.Duration(e.Config.GetZabbixConfig().Interval)*time.Second), // nolint:gosec
+		zap.Strings("preloadedLanguages", languages),
+		zap.Bool("debug", e.Config.IsDebug()),
+		zap.Stringer("httpClientTimeout", e.GetHTTPClientConfig().Timeout*time.Second))
+}
+
 func (e *Engine) UseZabbix(collectors []metrics.Collector) *Engine {
 	if e.Config == nil || e.Config.GetZabbixConfig().Interval == 0 {
 		return e
@@ -180,10 +294,21 @@ func (e *Engine) UseZabbix(collectors []metrics.Collector) *Engine {
 	}
 	cfg := e.Config.GetZabbixConfig()
 	sender := zabbix.NewSender(cfg.ServerHost, cfg.ServerPort)
-	e.Zabbix = metrics.NewZabbix(collectors, sender, cfg.Host, cfg.Interval, logger.ZabbixCollectorAdapter(e.Logger()))
+	interval := time.Duration(cfg.Interval) * time.Second // nolint:gosec
+	e.Zabbix = newZabbixTransport(collectors, sender, cfg.Host, interval, e.Logger())
 	return e
 }
 
+// ZabbixHealthy returns true if the last attempt to send metrics to Zabbix was successful.
+// It returns false if Zabbix is not used or if transport cannot report its health.
+func (e *Engine) ZabbixHealthy() bool {
+	if checker, ok := e.Zabbix.(ZabbixHealthChecker); ok {
+		return checker.Healthy()
+	}
+
+	return false
+}
+
 // HijackGinLogs will take control of GIN debug logs and will convert them into structured logs.
 // It will also affect default logging middleware. Use logger.GinMiddleware to circumvent this.
 func (e *Engine) HijackGinLogs() *Engine {
@@ -202,16 +327,18 @@ func (e *Engine) HijackGinLogs() *Engine {
 	return e
 }
 
-// TemplateFuncMap combines func map for templates.
+// TemplateFuncMap combines func map for templates. Provided functions override the localization functions
+// (trans, transTpl, etc.), "version" function can't be overridden. Overrides are logged as warnings.
 func (e *Engine) TemplateFuncMap(functions template.FuncMap) template.FuncMap {
-	funcMap := e.LocalizationFuncMap()
-
-	for name, fn := range functions {
-		funcMap[name] = fn
-	}
+	funcMap, err := util.MergeFuncMaps(e.LocalizationFuncMap(), functions, template.FuncMap{
+		"version": func() string {
+			return e.Config.GetVersion()
+		},
+	})
 
-	funcMap["version"] = func() string {
-		return e.Config.GetVersion()
+	var collision *util.FuncMapCollisionError
+	if errors.As(err, &collision) && e.Logger() != nil {
+		e.Logger().Warn("template functions override reserved names", zap.Strings("names", collision.Names))
 	}
 
 	return funcMap
@@ -236,6 +363,27 @@ func (e *Engine) CreateRendererFS(
 	return renderer
 }
 
+// SetHTMLRenderer replaces the router HTML renderer. Unlike setting gin.Engine.HTMLRender directly, it's safe
+// to call it while the server is running, so it can be used to reload the templates in the debug mode.
+// In-flight requests will finish rendering using the previous renderer.
+// Usage:
+//
+//	engine.SetHTMLRenderer(engine.CreateRenderer(func(r *core.Renderer) {
+//		r.Push("home", "templates/layout.html", "templates/home.html")
+//	}, template.FuncMap{}))
+func (e *Engine) SetHTMLRenderer(renderer render.HTMLRender) *Engine {
+	router := e.Router()
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if e.htmlRenderer == nil {
+		e.htmlRenderer = &swappableHTMLRender{}
+		router.HTMLRender = e.htmlRenderer
+	}
+	e.htmlRenderer.set(renderer)
+	return e
+}
+
 // Router will return current gin.Engine or panic if it's not present.
 func (e *Engine) Router() *gin.Engine {
 	if !e.prepared {
@@ -248,10 +396,12 @@ func (e *Engine) Router() *gin.Engine {
 	return e.ginEngine
 }
 
-// JobManager will return singleton JobManager from Engine.
+// JobManager will return singleton JobManager from Engine. It can be obtained before Prepare: logging will be
+// disabled if Config is not loaded yet, logger will be set only if it was provided via SetLogger before the call.
+// Use JobManager.SetLogger and JobManager.SetLogging to configure the manager after Prepare in that case.
 func (e *Engine) JobManager() *JobManager {
 	if e.jobManager == nil {
-		e.jobManager = NewJobManager().SetLogger(e.Logger()).SetLogging(e.Config.IsDebug())
+		e.jobManager = NewJobManager().SetLogger(e.Logger()).SetLogging(e.Config != nil && e.Config.IsDebug())
 	}
 
 	return e.jobManager
@@ -321,6 +471,30 @@ func (e *Engine) HTTPClient() *http.Client {
 	return e.httpClient
 }
 
+// CloseIdleConnections closes idle connections of the HTTP client. Connections which are in use are not interrupted.
+// It can be used to drop connections to the old upstream addresses after DNS change, see CloseIdleConnectionsJob.
+func (e *Engine) CloseIdleConnections() {
+	e.HTTPClient().CloseIdleConnections()
+}
+
+// CloseIdleConnectionsJob returns regular job which calls CloseIdleConnections every interval.
+// Usage:
+//
+//	_ = app.JobManager().RegisterJob("closeIdleConnections", app.CloseIdleConnectionsJob(time.Hour))
+//	_ = app.JobManager().RunJob("closeIdleConnections")
+func (e *Engine) CloseIdleConnectionsJob(interval time.Duration) *Job {
+	return &Job{
+		Command: func(log logger.Logger) error {
+			e.CloseIdleConnections()
+			return nil
+		},
+		ErrorHandler: DefaultJobErrorHandler(),
+		PanicHandler: DefaultJobPanicHandler(),
+		Interval:     interval,
+		Regular:      true,
+	}
+}
+
 // WithCookieSessions generates new CookieStore with optional key length.
 // Default key length is 32 bytes.
 func (e *Engine) WithCookieSessions(keyLength ...int) *Engine {
@@ -407,17 +581,87 @@ func (e *Engine) Run() error {
 	return e.Router().Run(e.Config.GetHTTPConfig().Listen)
 }
 
+// RunUnix serves gin.Engine over the Unix domain socket instead of TCP. Stale socket file is removed before
+// listening, the new one gets UnixSocketFileMode permissions. The server is stopped by Engine.Shutdown, socket file
+// is removed when the server stops. It returns nil if the server was stopped by Engine.Shutdown.
+func (e *Engine) RunUnix(socketPath string) error {
+	if err := removeStaleSocket(socketPath); err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(socketPath, UnixSocketFileMode); err != nil {
+		_ = listener.Close()
+		return fmt.Errorf("cannot set socket permissions: %w", err)
+	}
+
+	server := &http.Server{Handler: e.Router(), ReadHeaderTimeout: unixSocketReadHeaderTimeout}
+	e.OnShutdown(func(ctx context.Context) error {
+		return server.Shutdown(ctx)
+	})
+
+	if e.Zabbix != nil {
+		go e.Zabbix.Run()
+	}
+	if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// removeStaleSocket removes socket file which was left by the previous run. It won't remove regular files.
+func removeStaleSocket(socketPath string) error {
+	info, err := os.Stat(socketPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&fs.ModeSocket == 0 {
+		return fmt.Errorf("cannot listen on %s: file exists and it's not a socket", socketPath)
+	}
+	return os.Remove(socketPath)
+}
+
 // buildSentryConfig from app configuration.
 func (e *Engine) buildSentryConfig() {
 	if e.AppInfo.Version == "" {
 		e.AppInfo.Version = e.Config.GetVersion()
 	}
+	if provider, ok := e.Config.(config.SentryScrubConfigProvider); ok {
+		if scrub := provider.GetSentryScrubConfig(); len(scrub.Fields) > 0 || scrub.DropRequestBody {
+			e.Sentry.ScrubFields = scrub.Fields
+			e.Sentry.DropRequestBody = scrub.DropRequestBody
+		}
+	}
+	var environment string
+	if provider, ok := e.Config.(config.SentryEnvironmentProvider); ok {
+		environment = provider.GetSentryEnvironment()
+	}
+	if environment == "" {
+		environment = SentryEnvironmentProduction
+		if e.Config.IsDebug() {
+			environment = SentryEnvironmentDevelopment
+		}
+	}
+	sampleRate, tracesSampleRate := config.DefaultSentrySampleRate, config.DefaultSentryTracesSampleRate
+	if provider, ok := e.Config.(config.SentrySampleRateProvider); ok {
+		sampleRate, tracesSampleRate = provider.GetSentrySampleRate(), provider.GetSentryTracesSampleRate()
+	}
 	e.SentryConfig = sentry.ClientOptions{
 		Dsn:              e.Config.GetSentryDSN(),
+		Environment:      environment,
 		ServerName:       e.Config.GetHTTPConfig().Host,
 		Release:          e.AppInfo.Release(),
+		SampleRate:       sampleRate,
+		TracesSampleRate: tracesSampleRate,
 		AttachStacktrace: true,
 		Debug:            e.Config.IsDebug(),
+		BeforeSend:       e.Sentry.beforeSend,
 	}
 }
 
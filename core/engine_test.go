@@ -2,26 +2,36 @@ package core
 
 import (
 	"bytes"
+	"context"
 	"crypto/x509"
 	"database/sql"
 	"fmt"
 	"html/template"
 	"io"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-contrib/multitemplate"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"golang.org/x/text/language"
 
 	"github.com/retailcrm/mg-transport-core/v2/core/config"
 	"github.com/retailcrm/mg-transport-core/v2/core/middleware"
 	"github.com/retailcrm/mg-transport-core/v2/core/util/httputil"
+	"github.com/retailcrm/mg-transport-core/v2/core/util/testutil"
 
 	"github.com/retailcrm/mg-transport-core/v2/core/logger"
 )
@@ -112,6 +122,95 @@ func (e *EngineTest) Test_Prepare_NoConfig() {
 	engine.Prepare()
 }
 
+func (e *EngineTest) Test_Prepare_SentryScrub() {
+	cfg := e.engine.Config.(config.Config)
+	cfg.SentryScrub = config.SentryScrubConfig{Fields: []string{"password"}, DropRequestBody: true}
+	e.engine.Config = cfg
+	e.engine.TranslationsPath = testTranslationsDir
+	e.engine.Prepare()
+
+	assert.Equal(e.T(), []string{"password"}, e.engine.Sentry.ScrubFields)
+	assert.True(e.T(), e.engine.Sentry.DropRequestBody)
+	assert.NotNil(e.T(), e.engine.SentryConfig.BeforeSend)
+}
+
+func (e *EngineTest) Test_Prepare_RequiredMessages() {
+	e.engine.TranslationsPath = testTranslationsDir
+	e.engine.PreloadLanguages = DefaultLanguages
+	e.engine.RequiredMessages = []string{"message", "missing"}
+
+	defer func() {
+		err, ok := recover().(error)
+		e.Require().True(ok)
+		var missingErr *MissingMessagesError
+		e.Require().ErrorAs(err, &missingErr)
+		e.Assert().Len(missingErr.Missing, len(DefaultLanguages))
+		for _, item := range missingErr.Missing {
+			e.Assert().Equal("missing", item.MessageID)
+		}
+	}()
+	e.engine.Prepare()
+}
+
+func (e *EngineTest) Test_Prepare_SentryEnvironment() {
+	cfg := e.engine.Config.(config.Config)
+	cfg.SentryEnvironment = "staging"
+	e.engine.Config = cfg
+	e.engine.TranslationsPath = testTranslationsDir
+	e.engine.Prepare()
+
+	assert.Equal(e.T(), "staging", e.engine.SentryConfig.Environment)
+}
+
+func (e *EngineTest) Test_buildSentryConfig_DefaultEnvironment() {
+	cfg := e.engine.Config.(config.Config)
+	cfg.Debug = false
+	e.engine.Config = cfg
+	e.engine.buildSentryConfig()
+	assert.Equal(e.T(), SentryEnvironmentProduction, e.engine.SentryConfig.Environment)
+
+	cfg.Debug = true
+	e.engine.Config = cfg
+	e.engine.buildSentryConfig()
+	assert.Equal(e.T(), SentryEnvironmentDevelopment, e.engine.SentryConfig.Environment)
+}
+
+func (e *EngineTest) Test_buildSentryConfig_SampleRates() {
+	cfg := e.engine.Config.(config.Config)
+	e.engine.Config = cfg
+	e.engine.buildSentryConfig()
+	assert.Equal(e.T(), config.DefaultSentrySampleRate, e.engine.SentryConfig.SampleRate)
+	assert.Equal(e.T(), config.DefaultSentryTracesSampleRate, e.engine.SentryConfig.TracesSampleRate)
+
+	sampleRate, tracesSampleRate := 0.25, 0.05
+	cfg.SentrySampleRate = &sampleRate
+	cfg.SentryTracesSampleRate = &tracesSampleRate
+	e.engine.Config = cfg
+	e.engine.buildSentryConfig()
+	assert.Equal(e.T(), 0.25, e.engine.SentryConfig.SampleRate)
+	assert.Equal(e.T(), 0.05, e.engine.SentryConfig.TracesSampleRate)
+}
+
+// minimalConfig implements only the config.Configuration methods.
+type minimalConfig struct {
+	config.Configuration
+}
+
+func (e *EngineTest) Test_buildSentryConfig_MinimalConfiguration() {
+	cfg := e.engine.Config.(config.Config)
+	cfg.Debug = true
+	cfg.SentryEnvironment = "staging"
+	cfg.SentryScrub = config.SentryScrubConfig{Fields: []string{"password"}}
+	e.engine.Config = minimalConfig{Configuration: cfg}
+	e.engine.Sentry.ScrubFields = nil
+	e.engine.buildSentryConfig()
+
+	assert.Equal(e.T(), SentryEnvironmentDevelopment, e.engine.SentryConfig.Environment)
+	assert.Equal(e.T(), config.DefaultSentrySampleRate, e.engine.SentryConfig.SampleRate)
+	assert.Equal(e.T(), config.DefaultSentryTracesSampleRate, e.engine.SentryConfig.TracesSampleRate)
+	assert.Empty(e.T(), e.engine.Sentry.ScrubFields)
+}
+
 func (e *EngineTest) Test_Prepare() {
 	defer func() {
 		require.Nil(e.T(), recover())
@@ -132,6 +231,143 @@ func (e *EngineTest) Test_Prepare() {
 	assert.NotNil(e.T(), e.engine.Utils.Logger)
 }
 
+func (e *EngineTest) Test_Prepare_StartupSummary() {
+	log := testutil.NewBufferedLoggerSilent()
+	cfg := e.engine.Config.(config.Config)
+	cfg.ZabbixConfig = config.ZabbixConfig{Interval: 60}
+	cfg.HTTPClientConfig = &config.HTTPClientConfig{Timeout: 15}
+	e.engine.Config = cfg
+	e.engine.PreloadLanguages = []language.Tag{language.English, language.Russian}
+	e.engine.TranslationsPath = testTranslationsDir
+	e.engine.SetLogger(log)
+	e.engine.Prepare()
+
+	records, err := testutil.NewJSONRecordScanner(log).ScanAll()
+	require.NoError(e.T(), err)
+	var summary *testutil.LogRecord
+	for i := range records {
+		if records[i].Message == "engine prepared" {
+			summary = &records[i]
+		}
+	}
+	require.NotNil(e.T(), summary)
+	assert.Equal(e.T(), map[string]interface{}{
+		"dbConfigured":       true,
+		"sentryEnabled":      true,
+		"zabbixInterval":     "1m0s",
+		"preloadedLanguages": []interface{}{"en", "ru"},
+		"debug":              true,
+		"httpClientTimeout":  "15s",
+	}, summary.Context)
+}
+
+func (e *EngineTest) Test_RunUnix() {
+	if runtime.GOOS == "windows" {
+		e.T().Skip("unix sockets are not supported")
+	}
+
+	dir, err := os.MkdirTemp("", "mgt")
+	require.NoError(e.T(), err)
+	defer os.RemoveAll(dir)
+	socketPath := filepath.Join(dir, "app.sock")
+
+	mock := e.prepareShutdownEngine()
+	mock.ExpectClose()
+	e.engine.Router().GET("/ping", func(c *gin.Context) {
+		c.String(http.StatusOK, "pong")
+	})
+
+	result := make(chan error, 1)
+	go func() {
+		result <- e.engine.RunUnix(socketPath)
+	}()
+	require.Eventually(e.T(), func() bool {
+		info, err := os.Stat(socketPath)
+		return err == nil && info.Mode()&os.ModeSocket != 0
+	}, time.Second, time.Millisecond)
+
+	info, err := os.Stat(socketPath)
+	require.NoError(e.T(), err)
+	assert.Equal(e.T(), UnixSocketFileMode, info.Mode().Perm())
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socketPath)
+		},
+	}}
+	resp, err := client.Get("http://unix/ping")
+	require.NoError(e.T(), err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(e.T(), err)
+	_ = resp.Body.Close()
+	assert.Equal(e.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(e.T(), "pong", string(body))
+
+	require.NoError(e.T(), e.engine.Shutdown(context.Background()))
+	select {
+	case err := <-result:
+		assert.NoError(e.T(), err)
+	case <-time.After(time.Second):
+		e.T().Fatal("server was not stopped in time")
+	}
+	_, err = os.Stat(socketPath)
+	assert.True(e.T(), os.IsNotExist(err))
+}
+
+func (e *EngineTest) Test_RunUnix_NotSocket() {
+	file, err := os.CreateTemp("", "mgt")
+	require.NoError(e.T(), err)
+	_ = file.Close()
+	defer os.Remove(file.Name())
+
+	assert.Error(e.T(), e.engine.RunUnix(file.Name()))
+	_, err = os.Stat(file.Name())
+	assert.NoError(e.T(), err)
+}
+
+func (e *EngineTest) Test_initGin_TrustedProxies() {
+	cfg := e.engine.Config.(config.Config)
+	cfg.TrustedProxies = []string{"10.0.0.0/8"}
+	e.engine.Config = cfg
+	e.engine.TranslationsPath = testTranslationsDir
+	e.engine.Prepare()
+
+	var clientIP string
+	e.engine.Router().GET("/ip", func(c *gin.Context) {
+		clientIP = c.ClientIP()
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+	req.RemoteAddr = "10.1.2.3:1234"
+	req.Header.Set("X-Forwarded-For", "203.0.113.1")
+	e.engine.Router().ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(e.T(), "203.0.113.1", clientIP)
+
+	req = httptest.NewRequest(http.MethodGet, "/ip", nil)
+	req.RemoteAddr = "192.168.1.1:1234"
+	req.Header.Set("X-Forwarded-For", "203.0.113.1")
+	e.engine.Router().ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(e.T(), "192.168.1.1", clientIP)
+}
+
+func (e *EngineTest) Test_initGin_InvalidTrustedProxies() {
+	engine := New(e.appInfo())
+	engine.Config = config.Config{TrustedProxies: []string{"invalid"}}
+	assert.Panics(e.T(), engine.initGin)
+}
+
+func (e *EngineTest) Test_Prepare_PreloadTemplates() {
+	renderer := NewStaticRenderer(template.FuncMap{})
+	renderer.TemplatesFS = fstest.MapFS{"broken.html": {Data: []byte(`{{if}}`)}}
+	renderer.Register("broken", "broken.html")
+	e.engine.TemplateRenderer = &renderer
+	e.engine.TranslationsPath = testTranslationsDir
+
+	assert.Panics(e.T(), func() {
+		e.engine.Prepare()
+	})
+}
+
 func (e *EngineTest) Test_initGin_Release() {
 	engine := New(e.appInfo())
 	engine.Config = config.Config{Debug: false}
@@ -139,6 +375,66 @@ func (e *EngineTest) Test_initGin_Release() {
 	assert.NotNil(e.T(), engine.ginEngine)
 }
 
+func (e *EngineTest) Test_WithMiddleware() {
+	before := func(c *gin.Context) {}
+	afterSentry := func(c *gin.Context) {}
+	afterLocalization := func(c *gin.Context) {}
+	afterLocalization2 := func(c *gin.Context) {}
+
+	e.engine.TranslationsPath = testTranslationsDir
+	e.engine.Prepare()
+	e.engine.
+		WithMiddleware(AfterLocalization, afterLocalization).
+		WithMiddleware(BeforeSentry, before).
+		WithMiddleware(AfterSentry, afterSentry).
+		WithMiddleware(AfterLocalization, afterLocalization2)
+
+	handlers := e.engine.Router().Handlers
+	sentryCount := len(e.engine.SentryMiddlewares())
+	require.Len(e.T(), handlers, sentryCount+6)
+
+	pointer := func(h gin.HandlerFunc) uintptr {
+		return reflect.ValueOf(h).Pointer()
+	}
+	assert.Equal(e.T(), pointer(before), pointer(handlers[1]))
+	assert.Equal(e.T(), pointer(afterSentry), pointer(handlers[sentryCount+2]))
+	assert.Equal(e.T(), pointer(afterLocalization), pointer(handlers[sentryCount+4]))
+	assert.Equal(e.T(), pointer(afterLocalization2), pointer(handlers[sentryCount+5]))
+}
+
+func (e *EngineTest) Test_WithMiddleware_AfterInit() {
+	e.engine.TranslationsPath = testTranslationsDir
+	e.engine.Prepare()
+	e.engine.Router()
+
+	assert.Panics(e.T(), func() {
+		e.engine.WithMiddleware(BeforeSentry, func(c *gin.Context) {})
+	})
+}
+
+func (e *EngineTest) Test_DisableLocalizationMiddleware() {
+	e.engine.TranslationsPath = testTranslationsDir
+	e.engine.Prepare()
+	e.engine.DisableLocalizationMiddleware()
+
+	router := e.engine.Router()
+	require.Len(e.T(), router.Handlers, len(e.engine.SentryMiddlewares())+1)
+
+	router.GET("/webhook", func(c *gin.Context) {
+		_, ok := c.Get(LocalizerContextKey)
+		assert.False(e.T(), ok)
+		c.String(http.StatusOK, e.engine.GetLocalizedMessage("message"))
+	})
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/webhook", nil))
+	assert.Equal(e.T(), http.StatusOK, rr.Code)
+	assert.Equal(e.T(), "Test message", rr.Body.String())
+	assert.Panics(e.T(), func() {
+		e.engine.DisableLocalizationMiddleware()
+	})
+}
+
 func (e *EngineTest) Test_TemplateFuncMap() {
 	assert.NotNil(e.T(), e.engine.TemplateFuncMap(template.FuncMap{
 		"test": func() string {
@@ -147,12 +443,55 @@ func (e *EngineTest) Test_TemplateFuncMap() {
 	}))
 }
 
+func (e *EngineTest) Test_TemplateFuncMap_Override() {
+	log := testutil.NewBufferedLoggerSilent()
+	e.engine.SetLogger(log)
+	funcMap := e.engine.TemplateFuncMap(template.FuncMap{
+		"trans": func(string) string {
+			return "custom"
+		},
+		"version": func() string {
+			return "custom"
+		},
+	})
+
+	assert.Equal(e.T(), "custom", funcMap["trans"].(func(string) string)("message"))
+	assert.Equal(e.T(), "1", funcMap["version"].(func() string)())
+	assert.Contains(e.T(), log.String(), "template functions override reserved names")
+	assert.Contains(e.T(), log.String(), `"names":["trans","version"]`)
+}
+
 func (e *EngineTest) Test_CreateRenderer() {
 	e.engine.CreateRenderer(func(r *Renderer) {
 		assert.NotNil(e.T(), r)
 	}, template.FuncMap{})
 }
 
+func (e *EngineTest) Test_SetHTMLRenderer() {
+	e.engine.TranslationsPath = testTranslationsDir
+	e.engine.Prepare()
+	e.engine.Router().GET("/page", func(c *gin.Context) {
+		c.HTML(http.StatusOK, "page", nil)
+	})
+	render := func() string {
+		rr := httptest.NewRecorder()
+		e.engine.Router().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/page", nil))
+		require.Equal(e.T(), http.StatusOK, rr.Code)
+		return rr.Body.String()
+	}
+
+	first := multitemplate.New()
+	first.AddFromString("page", "first")
+	e.engine.SetHTMLRenderer(first)
+	assert.Equal(e.T(), "first", render())
+
+	second := multitemplate.New()
+	second.AddFromString("page", "second")
+	e.engine.SetHTMLRenderer(second)
+	assert.Equal(e.T(), "second", render())
+	assert.Same(e.T(), e.engine.htmlRenderer, e.engine.Router().HTMLRender)
+}
+
 func (e *EngineTest) Test_Router_Fail() {
 	defer func() {
 		r := recover()
@@ -181,6 +520,19 @@ func (e *EngineTest) Test_JobManager() {
 	assert.Equal(e.T(), manager, e.engine.JobManager())
 }
 
+func (e *EngineTest) Test_JobManager_NoConfig() {
+	engine := New(e.appInfo())
+	require.Nil(e.T(), engine.Config)
+
+	var manager *JobManager
+	require.NotPanics(e.T(), func() {
+		manager = engine.JobManager()
+	})
+	require.NotNil(e.T(), manager)
+	assert.False(e.T(), manager.enableLogging)
+	assert.Equal(e.T(), manager.nilLogger, manager.Logger())
+}
+
 func (e *EngineTest) Test_ConfigureRouter() {
 	e.engine.TranslationsPath = testTranslationsDir
 	e.engine.Prepare()
@@ -218,6 +570,32 @@ func (e *EngineTest) Test_BuildHTTPClient_NoConfig() {
 	assert.NotNil(e.T(), transport.TLSClientConfig.RootCAs)
 }
 
+func (e *EngineTest) Test_CloseIdleConnections() {
+	e.engine.httpClient = nil
+	e.Assert().NotPanics(e.engine.CloseIdleConnections)
+
+	e.engine.Config = &config.Config{
+		HTTPClientConfig: &config.HTTPClientConfig{
+			Timeout:       30,
+			MockAddress:   "localhost:3004",
+			MockedDomains: []string{"example.com"},
+			MockedPaths:   []string{"/api"},
+		},
+	}
+	e.engine.BuildHTTPClient(x509.NewCertPool())
+	e.Assert().NotPanics(e.engine.CloseIdleConnections)
+
+	e.engine.SetHTTPClient(&http.Client{Transport: http.DefaultTransport})
+	e.Assert().NotPanics(e.engine.CloseIdleConnections)
+}
+
+func (e *EngineTest) Test_CloseIdleConnectionsJob() {
+	job := e.engine.CloseIdleConnectionsJob(time.Hour)
+	e.Assert().True(job.Regular)
+	e.Assert().Equal(time.Hour, job.Interval)
+	e.Assert().NoError(job.Command(logger.NewNil()))
+}
+
 func (e *EngineTest) Test_GetHTTPClientConfig() {
 	e.engine.Config = &config.Config{}
 	assert.Equal(e.T(), DefaultHTTPClientConfig, e.engine.GetHTTPClientConfig())
@@ -0,0 +1,147 @@
+// Package enginetest contains helpers for the HTTP-level tests of the transports built with core.Engine.
+// It is a separate package because testutil is used by the core tests and cannot import core.
+package enginetest
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/retailcrm/mg-transport-core/v2/core"
+	"github.com/retailcrm/mg-transport-core/v2/core/config"
+	"github.com/retailcrm/mg-transport-core/v2/core/util/testutil"
+)
+
+// DefaultTranslations are written to the temporary translations directory if no translations were provided.
+var DefaultTranslations = map[string]string{
+	"translate.en.yml": "error: Error",
+}
+
+// TestEngine is a prepared core.Engine with mocked database and buffered logger.
+type TestEngine struct {
+	*core.Engine
+	// Mock is used to set expectations for the engine database.
+	Mock sqlmock.Sqlmock
+	// Log contains all messages which were logged by the engine.
+	Log testutil.BufferedLogger
+}
+
+// Option configures the TestEngine.
+type Option func(*options)
+
+type options struct {
+	translations map[string]string
+	configure    []func(*core.Engine)
+	config       []func(*config.Config)
+	appInfo      core.AppInfo
+}
+
+// WithTranslations sets translation files which will be written to the temporary translations directory.
+// Keys are file names (e.g. "translate.en.yml"), values are file contents.
+func WithTranslations(files map[string]string) Option {
+	return func(o *options) {
+		o.translations = files
+	}
+}
+
+// WithConfig modifies engine configuration before the engine is prepared.
+func WithConfig(fn func(*config.Config)) Option {
+	return func(o *options) {
+		o.config = append(o.config, fn)
+	}
+}
+
+// WithEngine modifies the engine before it is prepared. It can be used to add middleware or preload languages.
+func WithEngine(fn func(*core.Engine)) Option {
+	return func(o *options) {
+		o.configure = append(o.configure, fn)
+	}
+}
+
+// WithAppInfo sets application info for the engine.
+func WithAppInfo(info core.AppInfo) Option {
+	return func(o *options) {
+		o.appInfo = info
+	}
+}
+
+// NewTestEngine returns prepared engine with sqlmock database, silent buffered logger, Sentry without DSN
+// and temporary translations directory. Teardown function closes database and removes translations.
+// sqlmock is used instead of the in-memory SQLite to avoid cgo dependency, set expectations via TestEngine.Mock.
+// It panics if the engine cannot be created.
+// Usage:
+//
+//	engine, teardown := enginetest.NewTestEngine()
+//	defer teardown()
+//
+//	engine.Router().GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+//	rr := httptest.NewRecorder()
+//	engine.Router().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/ping", nil))
+func NewTestEngine(opts ...Option) (*TestEngine, func()) {
+	o := &options{
+		translations: DefaultTranslations,
+		appInfo: core.AppInfo{
+			Version:   "test",
+			Commit:    "test",
+			Build:     "test",
+			BuildDate: "test",
+		},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	translationsDir, err := os.MkdirTemp("", "enginetest")
+	if err != nil {
+		panic(err)
+	}
+	for name, data := range o.translations {
+		if err := os.WriteFile(filepath.Join(translationsDir, name), []byte(data), 0600); err != nil {
+			_ = os.RemoveAll(translationsDir)
+			panic(err)
+		}
+	}
+
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		_ = os.RemoveAll(translationsDir)
+		panic(err)
+	}
+
+	cfg := config.Config{
+		Version: "test",
+		Database: config.DatabaseConfig{
+			Connection:         sqlDB,
+			MaxOpenConnections: 10,
+			MaxIdleConnections: 10,
+		},
+		HTTPServer: config.HTTPServerConfig{
+			Host:   "localhost",
+			Listen: ":0",
+		},
+		TransportInfo: config.Info{
+			Name: "Test",
+			Code: "test",
+		},
+		Debug: true,
+	}
+	for _, fn := range o.config {
+		fn(&cfg)
+	}
+
+	log := testutil.NewBufferedLoggerSilent()
+	engine := core.New(o.appInfo)
+	engine.Config = cfg
+	engine.TranslationsPath = translationsDir
+	engine.SetLogger(log)
+	for _, fn := range o.configure {
+		fn(engine)
+	}
+	engine.Prepare()
+
+	return &TestEngine{Engine: engine, Mock: mock, Log: log}, func() {
+		engine.CloseDB()
+		_ = os.RemoveAll(translationsDir)
+	}
+}
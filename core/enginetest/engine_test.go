@@ -0,0 +1,115 @@
+package enginetest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/gormigrate.v1"
+
+	"github.com/retailcrm/mg-transport-core/v2/core"
+	"github.com/retailcrm/mg-transport-core/v2/core/config"
+	"github.com/retailcrm/mg-transport-core/v2/core/db"
+)
+
+type testModel struct {
+	Name string `gorm:"column:name; type:varchar(70)"`
+}
+
+func (testModel) TableName() string {
+	return "test_model"
+}
+
+func TestNewTestEngine_ServesRoute(t *testing.T) {
+	engine, teardown := NewTestEngine(WithTranslations(map[string]string{
+		"translate.en.yml": "pong: Pong!",
+	}))
+	defer teardown()
+
+	engine.Router().GET("/ping", func(c *gin.Context) {
+		engine.Logger().Info("ping")
+		c.String(http.StatusOK, engine.GetLocalizedMessage("pong"))
+	})
+
+	rr := httptest.NewRecorder()
+	engine.Router().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "Pong!", rr.Body.String())
+	assert.Contains(t, engine.Log.String(), `"message":"ping"`)
+}
+
+func TestNewTestEngine_RunsMigration(t *testing.T) {
+	engine, teardown := NewTestEngine()
+	defer teardown()
+
+	engine.Mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM INFORMATION_SCHEMA.tables WHERE table_name = $1`)).
+		WithArgs("migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	engine.Mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM INFORMATION_SCHEMA.columns WHERE table_name = $1`)).
+		WithArgs("migrations", "applied_at").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	engine.Mock.ExpectBegin()
+	engine.Mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM INFORMATION_SCHEMA.tables WHERE table_name = $1`)).
+		WithArgs("migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	engine.Mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT id FROM migrations`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	engine.Mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "migrations"  WHERE (id = $1)`)).
+		WithArgs("1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	engine.Mock.
+		ExpectExec(regexp.QuoteMeta(`CREATE TABLE "test_model" ("name" varchar(70) )`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	engine.Mock.
+		ExpectExec(regexp.QuoteMeta(`INSERT INTO migrations (id) VALUES ($1)`)).
+		WithArgs("1").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	engine.Mock.ExpectCommit()
+
+	migrations := db.Migrations().SetDB(engine.DB)
+	migrations.Add(&gormigrate.Migration{
+		ID: "1",
+		Migrate: func(db *gorm.DB) error {
+			return db.AutoMigrate(testModel{}).Error
+		},
+	})
+	require.NoError(t, migrations.Migrate())
+	assert.NoError(t, engine.Mock.ExpectationsWereMet())
+}
+
+func TestNewTestEngine_Options(t *testing.T) {
+	var configured bool
+	engine, teardown := NewTestEngine(
+		WithAppInfo(core.AppInfo{Version: "1.0.0"}),
+		WithConfig(func(cfg *config.Config) {
+			cfg.TransportInfo.Code = "custom"
+		}),
+		WithEngine(func(e *core.Engine) {
+			configured = true
+			e.DefaultError = "custom_error"
+		}),
+	)
+
+	assert.True(t, configured)
+	assert.Equal(t, "1.0.0", engine.AppInfo.Version)
+	assert.Equal(t, "custom", engine.Config.GetTransportInfo().GetCode())
+	assert.Equal(t, "custom_error", engine.DefaultError)
+
+	dir := engine.TranslationsPath
+	require.DirExists(t, dir)
+	teardown()
+	_, err := os.Stat(dir)
+	assert.True(t, os.IsNotExist(err))
+}
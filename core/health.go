@@ -0,0 +1,84 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/logger"
+)
+
+const (
+	// LivenessPath is the path of the liveness endpoint.
+	LivenessPath = "/healthz"
+	// ReadinessPath is the path of the readiness endpoint.
+	ReadinessPath = "/readyz"
+)
+
+// HealthCheck is a single liveness or readiness check. It should return an error if the check has failed.
+type HealthCheck func(ctx context.Context) error
+
+// ErrNoDatabase is returned by the database health check if the engine has no database connection.
+var ErrNoDatabase = errors.New("database is not initialized")
+
+// healthStatus is returned by the health endpoints.
+type healthStatus struct {
+	Status string `json:"status"`
+}
+
+// RegisterHealthChecks registers liveness and readiness endpoints (LivenessPath and ReadinessPath) in the router.
+// Liveness endpoint returns 200 once the application is serving requests and all provided liveness checks pass.
+// Readiness endpoint runs the database ping (see DBHealthCheck) and the provided readiness checks. It returns 503
+// with the "unavailable" status if any of the checks has failed. Check errors are logged and never returned to
+// the client. Engine must be prepared before calling this method.
+// Usage:
+//
+//	engine.RegisterHealthChecks(nil, []core.HealthCheck{func(ctx context.Context) error {
+//		return cache.Ping(ctx)
+//	}})
+func (e *Engine) RegisterHealthChecks(liveness, readiness []HealthCheck) *Engine {
+	readiness = append([]HealthCheck{e.DBHealthCheck()}, readiness...)
+
+	e.Router().GET(LivenessPath, e.healthHandler(liveness))
+	e.Router().GET(ReadinessPath, e.healthHandler(readiness))
+	return e
+}
+
+// DBHealthCheck returns HealthCheck which pings the engine database.
+func (e *Engine) DBHealthCheck() HealthCheck {
+	return func(ctx context.Context) error {
+		if e.DB == nil {
+			return ErrNoDatabase
+		}
+
+		return e.Ping(ctx)
+	}
+}
+
+// healthHandler returns handler which runs all provided checks and responds with their result.
+// Errors of the failed checks are logged with the check index.
+func (e *Engine) healthHandler(checks []HealthCheck) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		failed := false
+		for i, check := range checks {
+			if check == nil {
+				continue
+			}
+			if err := check(c.Request.Context()); err != nil {
+				failed = true
+				e.Logger().Error("health check failed",
+					zap.String("path", c.FullPath()), zap.Int("check", i), logger.Err(err))
+			}
+		}
+
+		if failed {
+			c.JSON(http.StatusServiceUnavailable, healthStatus{Status: "unavailable"})
+			return
+		}
+
+		c.JSON(http.StatusOK, healthStatus{Status: "ok"})
+	}
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/config"
+	"github.com/retailcrm/mg-transport-core/v2/core/util/testutil"
+)
+
+func (e *EngineTest) prepareHealthEngine() sqlmock.Sqlmock {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(e.T(), err)
+
+	mock.ExpectPing()
+	cfg := e.engine.Config.(config.Config)
+	cfg.Database.Connection = db
+	e.engine.Config = cfg
+	e.engine.TranslationsPath = testTranslationsDir
+	e.engine.Prepare()
+	return mock
+}
+
+func (e *EngineTest) performHealthRequest(path string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest(http.MethodGet, path, nil)
+	require.NoError(e.T(), err)
+
+	rr := httptest.NewRecorder()
+	e.engine.Router().ServeHTTP(rr, req)
+	return rr
+}
+
+func (e *EngineTest) Test_RegisterHealthChecks_Liveness() {
+	e.prepareHealthEngine()
+	e.engine.RegisterHealthChecks(nil, nil)
+
+	rr := e.performHealthRequest(LivenessPath)
+	assert.Equal(e.T(), http.StatusOK, rr.Code)
+	assert.JSONEq(e.T(), `{"status":"ok"}`, rr.Body.String())
+}
+
+func (e *EngineTest) Test_RegisterHealthChecks_ReadinessOK() {
+	mock := e.prepareHealthEngine()
+	mock.ExpectPing()
+	called := false
+	e.engine.RegisterHealthChecks(nil, []HealthCheck{func(ctx context.Context) error {
+		called = true
+		return nil
+	}})
+
+	rr := e.performHealthRequest(ReadinessPath)
+	assert.Equal(e.T(), http.StatusOK, rr.Code)
+	assert.JSONEq(e.T(), `{"status":"ok"}`, rr.Body.String())
+	assert.True(e.T(), called)
+	assert.NoError(e.T(), mock.ExpectationsWereMet())
+}
+
+func (e *EngineTest) Test_RegisterHealthChecks_ReadinessDBFailure() {
+	mock := e.prepareHealthEngine()
+	mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+	e.engine.RegisterHealthChecks(nil, nil)
+
+	rr := e.performHealthRequest(ReadinessPath)
+	require.Equal(e.T(), http.StatusServiceUnavailable, rr.Code)
+	assert.JSONEq(e.T(), `{"status":"unavailable"}`, rr.Body.String())
+	assert.NotContains(e.T(), rr.Body.String(), "connection refused")
+	assert.NoError(e.T(), mock.ExpectationsWereMet())
+
+	rr = e.performHealthRequest(LivenessPath)
+	assert.Equal(e.T(), http.StatusOK, rr.Code)
+}
+
+func (e *EngineTest) Test_RegisterHealthChecks_ReadinessCheckFailure() {
+	mock := e.prepareHealthEngine()
+	mock.ExpectPing()
+	log := testutil.NewBufferedLoggerSilent()
+	e.engine.SetLogger(log)
+	e.engine.RegisterHealthChecks(nil, []HealthCheck{func(ctx context.Context) error {
+		return errors.New("cache is unavailable")
+	}})
+
+	rr := e.performHealthRequest(ReadinessPath)
+	require.Equal(e.T(), http.StatusServiceUnavailable, rr.Code)
+	assert.NotContains(e.T(), rr.Body.String(), "cache is unavailable")
+
+	records, err := testutil.NewJSONRecordScanner(log).ScanAll()
+	require.NoError(e.T(), err)
+	require.Len(e.T(), records, 1)
+	assert.Equal(e.T(), "health check failed", records[0].Message)
+	assert.Equal(e.T(), ReadinessPath, records[0].Context["path"])
+	assert.Equal(e.T(), float64(1), records[0].Context["check"])
+	assert.Equal(e.T(), "cache is unavailable", records[0].Context["error"])
+}
+
+func (e *EngineTest) Test_DBHealthCheck_NoDatabase() {
+	e.engine.DB = nil
+	assert.ErrorIs(e.T(), e.engine.DBHealthCheck()(context.Background()), ErrNoDatabase)
+}
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/retailcrm/mg-transport-core/v2/core/logger"
+	"github.com/retailcrm/mg-transport-core/v2/core/stacktrace"
+	"github.com/retailcrm/mg-transport-core/v2/core/util"
 	"go.uber.org/zap"
 )
 
@@ -29,6 +31,8 @@ type JobErrorHandler func(string, error, logger.Logger)
 type JobPanicHandler func(string, interface{}, logger.Logger)
 
 // Job represents single job. Regular job will be executed every Interval.
+// If PanicAsError is true, panic in the Command is converted to error with stack trace using util.SafeCall
+// and passed to the ErrorHandler instead of the PanicHandler.
 type Job struct {
 	Command      JobFunc
 	ErrorHandler JobErrorHandler
@@ -37,6 +41,7 @@ type Job struct {
 	Interval     time.Duration
 	writeLock    sync.RWMutex
 	Regular      bool
+	PanicAsError bool
 	active       bool
 }
 
@@ -67,15 +72,26 @@ type JobManager struct {
 
 // getWrappedFunc wraps job into function.
 func (j *Job) getWrappedFunc(name string, log logger.Logger) func(callback JobAfterCallback) {
+	return j.wrapFunc(name, log, nil)
+}
+
+// wrapFunc wraps job into function. onPanic (if not nil) receives the recovered panic as *util.PanicError
+// after the PanicHandler.
+func (j *Job) wrapFunc(name string, log logger.Logger, onPanic func(error)) func(callback JobAfterCallback) {
 	return func(callback JobAfterCallback) {
 		defer func() {
-			if r := recover(); r != nil && j.PanicHandler != nil {
-				j.PanicHandler(name, r, log)
+			if r := recover(); r != nil {
+				if j.PanicHandler != nil {
+					j.PanicHandler(name, r, log)
+				}
+				if onPanic != nil {
+					onPanic(stacktrace.AppendToError(&util.PanicError{Value: r}))
+				}
 			}
 		}()
 
 		log = log.With(logger.Handler(name))
-		err := j.Command(log)
+		err := j.runCommand(log)
 		if err != nil && j.ErrorHandler != nil {
 			j.ErrorHandler(name, err, log)
 		}
@@ -88,6 +104,16 @@ func (j *Job) getWrappedFunc(name string, log logger.Logger) func(callback JobAf
 	}
 }
 
+// runCommand executes job command, panic is recovered as error if PanicAsError is enabled.
+func (j *Job) runCommand(log logger.Logger) error {
+	if j.PanicAsError {
+		return util.SafeCall(func() error {
+			return j.Command(log)
+		})
+	}
+	return j.Command(log)
+}
+
 // getWrappedTimerFunc returns job timer func to run in the separate goroutine.
 func (j *Job) getWrappedTimerFunc(name string, log logger.Logger) func(chan bool) {
 	return func(stopChannel chan bool) {
@@ -306,6 +332,56 @@ func (j *JobManager) RunJobsOnceSequentially(names []string, stopOnError bool) e
 	return j.RunJobOnce(names[0], chained)
 }
 
+// RunJobsOnceSequentiallyWithDone works like RunJobsOnceSequentially, but calls done exactly once when the sequence
+// ends: after the last job or after the failed job if stopOnError is true. done receives the error of the last
+// executed job or an error if the next job cannot be found. If any job (or done itself) panics, the sequence
+// is stopped and done receives *util.PanicError. done is not called if the first job cannot be found
+// (the error is returned instead). done can be nil.
+func (j *JobManager) RunJobsOnceSequentiallyWithDone(names []string, stopOnError bool, done func(err error)) error {
+	var once sync.Once
+	finish := func(err error) {
+		once.Do(func() {
+			if done != nil {
+				done(err)
+			}
+		})
+	}
+
+	if len(names) == 0 {
+		finish(nil)
+		return nil
+	}
+
+	return j.runSequence(names, stopOnError, finish)
+}
+
+// runSequence runs the first job of the sequence. The rest of the sequence is run from the job callback.
+func (j *JobManager) runSequence(names []string, stopOnError bool, done func(err error)) error {
+	job, ok := j.FetchJob(names[0])
+	if !ok {
+		return fmt.Errorf("cannot find job `%s`", names[0])
+	}
+
+	go job.wrapFunc(names[0], j.Logger(), done)(j.sequenceCallback(names[1:], stopOnError, done))
+	return nil
+}
+
+// sequenceCallback returns JobAfterCallback which runs the rest of the sequence and calls done when it ends.
+func (j *JobManager) sequenceCallback(names []string, stopOnError bool, done func(err error)) JobAfterCallback {
+	return func(jobError error, log logger.Logger) error {
+		if len(names) == 0 || (jobError != nil && stopOnError) {
+			done(jobError)
+			return nil
+		}
+
+		if err := j.runSequence(names, stopOnError, done); err != nil {
+			done(err)
+			return err
+		}
+		return nil
+	}
+}
+
 // RunJobOnceSync starts provided job once in current goroutine if job exists. Will wait for job to end it's work.
 func (j *JobManager) RunJobOnceSync(name string) error {
 	if job, ok := j.FetchJob(name); ok {
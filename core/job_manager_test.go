@@ -6,6 +6,7 @@ import (
 	"math/rand"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	"go.uber.org/zap/zapcore"
 
 	"github.com/retailcrm/mg-transport-core/v2/core/logger"
+	"github.com/retailcrm/mg-transport-core/v2/core/util"
 )
 
 type JobTest struct {
@@ -360,6 +362,30 @@ func (t *JobTest) Test_getWrappedFuncPanic() {
 	assert.True(t.T(), t.panicked(time.Millisecond))
 }
 
+func (t *JobTest) Test_getWrappedFuncPanicAsError() {
+	defer func() {
+		require.Nil(t.T(), recover())
+	}()
+
+	t.clear()
+	t.oncePanicJob()
+	t.job.PanicAsError = true
+	fn := t.job.getWrappedFunc("job", t.testLogger())
+	require.NotNil(t.T(), fn)
+	go fn(nil)
+	assert.True(t.T(), t.executed())
+
+	select {
+	case err := <-t.executeErr:
+		var panicErr *util.PanicError
+		require.True(t.T(), errors.As(err, &panicErr))
+		assert.Equal(t.T(), "test panic", panicErr.Value)
+	case <-time.After(time.Millisecond * 10):
+		t.T().Fatal("panic wasn't passed to the error handler")
+	}
+	assert.False(t.T(), t.panicked(time.Millisecond))
+}
+
 func (t *JobTest) Test_run() {
 	defer func() {
 		require.Nil(t.T(), recover())
@@ -597,3 +623,139 @@ func (t *JobManagerTest) Test_Start() {
 	})
 	manager.Start()
 }
+
+func (t *JobManagerTest) runSequenceWithDone(names []string, stopOnError bool) (bool, error) {
+	done := make(chan error, 2)
+	require.NoError(t.T(), t.manager.RunJobsOnceSequentiallyWithDone(names, stopOnError, func(err error) {
+		done <- err
+	}))
+
+	select {
+	case err := <-done:
+		select {
+		case <-done:
+			t.T().Fatal("done was called twice")
+		case <-time.After(10 * time.Millisecond):
+		}
+		return true, err
+	case <-time.After(time.Second):
+		return false, nil
+	}
+}
+
+func (t *JobManagerTest) Test_RunJobsOnceSequentiallyWithDone() {
+	var calls []string
+	manager := NewJobManager()
+	t.manager, manager = manager, t.manager
+	defer func() { t.manager = manager }()
+
+	for _, name := range []string{"first", "second"} {
+		name := name
+		require.NoError(t.T(), t.manager.RegisterJob(name, &Job{
+			Command: func(log logger.Logger) error {
+				calls = append(calls, name)
+				return nil
+			},
+			ErrorHandler: DefaultJobErrorHandler(),
+			PanicHandler: DefaultJobPanicHandler(),
+		}))
+	}
+
+	ok, err := t.runSequenceWithDone([]string{"first", "second"}, true)
+	require.True(t.T(), ok, "done was not called in time")
+	assert.NoError(t.T(), err)
+	assert.Equal(t.T(), []string{"first", "second"}, calls)
+}
+
+func (t *JobManagerTest) Test_RunJobsOnceSequentiallyWithDone_Error() {
+	var secondCalled bool
+	jobErr := errors.New("job error")
+	manager := NewJobManager()
+	t.manager, manager = manager, t.manager
+	defer func() { t.manager = manager }()
+
+	require.NoError(t.T(), t.manager.RegisterJob("first", &Job{
+		Command: func(log logger.Logger) error {
+			return jobErr
+		},
+		ErrorHandler: DefaultJobErrorHandler(),
+		PanicHandler: DefaultJobPanicHandler(),
+	}))
+	require.NoError(t.T(), t.manager.RegisterJob("second", &Job{
+		Command: func(log logger.Logger) error {
+			secondCalled = true
+			return nil
+		},
+		ErrorHandler: DefaultJobErrorHandler(),
+		PanicHandler: DefaultJobPanicHandler(),
+	}))
+
+	ok, err := t.runSequenceWithDone([]string{"first", "second"}, true)
+	require.True(t.T(), ok, "done was not called in time")
+	assert.ErrorIs(t.T(), err, jobErr)
+	assert.False(t.T(), secondCalled)
+}
+
+func (t *JobManagerTest) Test_RunJobsOnceSequentiallyWithDone_Panic() {
+	var secondCalled atomic.Bool
+	manager := NewJobManager()
+	t.manager, manager = manager, t.manager
+	defer func() { t.manager = manager }()
+
+	require.NoError(t.T(), t.manager.RegisterJob("first", &Job{
+		Command: func(log logger.Logger) error {
+			panic("job panic")
+		},
+		ErrorHandler: DefaultJobErrorHandler(),
+		PanicHandler: DefaultJobPanicHandler(),
+	}))
+	require.NoError(t.T(), t.manager.RegisterJob("second", &Job{
+		Command: func(log logger.Logger) error {
+			secondCalled.Store(true)
+			return nil
+		},
+	}))
+
+	ok, err := t.runSequenceWithDone([]string{"first", "second"}, false)
+	require.True(t.T(), ok, "done was not called in time")
+	var panicErr *util.PanicError
+	require.ErrorAs(t.T(), err, &panicErr)
+	assert.Equal(t.T(), "job panic", panicErr.Value)
+	assert.False(t.T(), secondCalled.Load())
+}
+
+func (t *JobManagerTest) Test_RunJobsOnceSequentiallyWithDone_NilDone() {
+	called := make(chan struct{}, 2)
+	manager := NewJobManager()
+	t.manager, manager = manager, t.manager
+	defer func() { t.manager = manager }()
+
+	for _, name := range []string{"first", "second"} {
+		require.NoError(t.T(), t.manager.RegisterJob(name, &Job{
+			Command: func(log logger.Logger) error {
+				called <- struct{}{}
+				return nil
+			},
+			PanicHandler: func(_ string, r interface{}, _ logger.Logger) {
+				t.T().Errorf("unexpected panic: %v", r)
+			},
+		}))
+	}
+
+	require.NoError(t.T(), t.manager.RunJobsOnceSequentiallyWithDone(nil, true, nil))
+	require.NoError(t.T(), t.manager.RunJobsOnceSequentiallyWithDone([]string{"first", "second"}, true, nil))
+	for i := 0; i < 2; i++ {
+		select {
+		case <-called:
+		case <-time.After(time.Second):
+			t.T().Fatal("job was not called in time")
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+}
+
+func (t *JobManagerTest) Test_RunJobsOnceSequentiallyWithDone_Empty() {
+	ok, err := t.runSequenceWithDone(nil, true)
+	require.True(t.T(), ok)
+	assert.NoError(t.T(), err)
+}
@@ -1,17 +1,26 @@
 package core
 
 import (
+	"encoding/json"
+	"errors"
 	"html/template"
 	"io/fs"
 	"os"
 	"path"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/pelletier/go-toml/v2"
 	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
 	"gopkg.in/yaml.v2"
 
+	"github.com/retailcrm/mg-transport-core/v2/core/middleware"
 	"github.com/retailcrm/mg-transport-core/v2/core/util/errorutil"
 )
 
@@ -25,8 +34,61 @@ var DefaultLanguages = []language.Tag{
 // DefaultLanguage is a base language which will be chosen if current language is unspecified.
 var DefaultLanguage = language.English
 
+// Date and time formatting styles for the Localizer.FormatDateTime.
+const (
+	DateTimeStyleShort  = "short"
+	DateTimeStyleMedium = "medium"
+	DateTimeStyleLong   = "long"
+)
+
+// dateTimeLayouts contains date and time layouts for every supported root language and style.
+var dateTimeLayouts = map[language.Tag]map[string]string{
+	language.English: {
+		DateTimeStyleShort:  "1/2/06 3:04 PM",
+		DateTimeStyleMedium: "Jan 2, 2006 3:04 PM",
+		DateTimeStyleLong:   "January 2, 2006 3:04:05 PM",
+	},
+	language.Russian: {
+		DateTimeStyleShort:  "02.01.06 15:04",
+		DateTimeStyleMedium: "02.01.2006 15:04",
+		DateTimeStyleLong:   "2 January 2006 г. 15:04:05",
+	},
+	language.Spanish: {
+		DateTimeStyleShort:  "2/1/06 15:04",
+		DateTimeStyleMedium: "2 Jan 2006 15:04",
+		DateTimeStyleLong:   "2 de January de 2006 15:04:05",
+	},
+}
+
+// monthNames contains localized month names which will replace English names in the formatted date.
+var monthNames = map[language.Tag][12]string{
+	language.Russian: {
+		"января", "февраля", "марта", "апреля", "мая", "июня",
+		"июля", "августа", "сентября", "октября", "ноября", "декабря",
+	},
+	language.Spanish: {
+		"enero", "febrero", "marzo", "abril", "mayo", "junio",
+		"julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre",
+	},
+}
+
 // LocalizerContextKey is a key which is used to store localizer in gin.Context key-value storage.
-const LocalizerContextKey = "localizer"
+const LocalizerContextKey = middleware.LocalizerContextKey
+
+// MissingTranslationStrategy defines what Localizer returns if translation for the message is missing.
+type MissingTranslationStrategy uint8
+
+const (
+	// MissingTranslationReturnID returns message ID instead of the missing translation.
+	MissingTranslationReturnID MissingTranslationStrategy = iota
+	// MissingTranslationPanicInDebug panics if gin is in debug mode and returns message ID otherwise.
+	MissingTranslationPanicInDebug
+	// MissingTranslationReturnEmpty returns empty string instead of the missing translation.
+	MissingTranslationReturnEmpty
+)
+
+// DefaultLanguageQueryParam is a query parameter which overrides Accept-Language header in LocalizationMiddleware.
+const DefaultLanguageQueryParam = "lang"
 
 // Localizer struct.
 type Localizer struct {
@@ -36,6 +98,20 @@ type Localizer struct {
 	LocaleMatcher    language.Matcher
 	LanguageTag      language.Tag
 	TranslationsPath string
+	// FallbackChain contains languages which are consulted in order if message is missing in the current language,
+	// e.g. [es, en] for es-MX.
+	FallbackChain []language.Tag
+	// ContextKey is used to store localizer in gin.Context by LocalizationMiddleware.
+	// LocalizerContextKey is used if it's empty.
+	ContextKey string
+	// LanguageQueryParam is a query parameter which overrides Accept-Language header in LocalizationMiddleware
+	// if it contains supported language. DefaultLanguageQueryParam is used if it's empty.
+	LanguageQueryParam string
+	// OnMissing defines the result of GetLocalizedMessage, GetLocalizedTemplateMessage and GetLocalizedPluralMessage
+	// if translation is missing in the current language and FallbackChain. Other errors still cause panic.
+	OnMissing MissingTranslationStrategy
+	// languageMutex guards LanguageTag. It's not shared between clones unlike loadMutex.
+	languageMutex sync.RWMutex
 }
 
 // LocalizerInterface contains entire public interface of the localizer component.
@@ -83,6 +159,20 @@ type HTTPResponseLocalizer interface {
 	InternalServerErrorLocalized(string) (int, interface{})
 }
 
+// FallbackMessageLocalizer can localize strings with the fallback text. It's implemented by the Localizer,
+// but it's not a part of the LocalizerInterface in order to keep BC. Use LocalizedMessageOr for other localizers.
+type FallbackMessageLocalizer interface {
+	GetLocalizedMessageOr(string, string) string
+}
+
+// ExtendedHTTPResponseLocalizer can localize strings and return them with additional HTTP error codes.
+// It's implemented by the Localizer, but it's not a part of the LocalizerInterface in order to keep BC.
+type ExtendedHTTPResponseLocalizer interface {
+	ConflictLocalized(string) (int, interface{})
+	UnprocessableEntityLocalized(string) (int, interface{})
+	TooManyRequestsLocalized(string) (int, interface{})
+}
+
 // CloneableLocalizer is a localizer which can clone itself.
 type CloneableLocalizer interface {
 	Clone() CloneableLocalizer
@@ -109,8 +199,6 @@ func NewLocalizer(locale language.Tag, matcher language.Matcher, translationsPat
 // Usage:
 //
 //	NewLocalizerFS(language.English, DefaultLocalizerMatcher(), translationsFS)
-//
-// TODO This code should be covered with tests.
 func NewLocalizerFS(
 	locale language.Tag, matcher language.Matcher, translationsFS fs.FS,
 ) LocalizerInterface {
@@ -146,21 +234,53 @@ func DefaultLocalizerMatcher() language.Matcher {
 // This method should be used when LocalizationMiddleware is not feasible (outside of *gin.HandlerFunc).
 func (l *Localizer) Clone() CloneableLocalizer {
 	clone := &Localizer{
-		i18nStorage:      l.i18nStorage,
-		TranslationsFS:   l.TranslationsFS,
-		LocaleMatcher:    l.LocaleMatcher,
-		LanguageTag:      l.LanguageTag,
-		TranslationsPath: l.TranslationsPath,
-		loadMutex:        l.loadMutex,
+		i18nStorage:        l.i18nStorage,
+		TranslationsFS:     l.TranslationsFS,
+		LocaleMatcher:      l.LocaleMatcher,
+		LanguageTag:        l.Language(),
+		TranslationsPath:   l.TranslationsPath,
+		FallbackChain:      l.FallbackChain,
+		ContextKey:         l.ContextKey,
+		LanguageQueryParam: l.LanguageQueryParam,
+		OnMissing:          l.OnMissing,
+		loadMutex:          l.loadMutex,
 	}
 	clone.SetLanguage(DefaultLanguage)
 
 	return clone
 }
 
-// LocalizationMiddleware returns gin.HandlerFunc which will set localizer language by Accept-Language header
+// ForLanguage returns a copy of the localizer bound to the provided language. The copy shares translations
+// with the original localizer, original language stays unchanged. Useful for background jobs without request context.
+// Usage:
+//
+//	msg := localizer.ForLanguage(language.Russian).GetLocalizedMessage("message")
+func (l *Localizer) ForLanguage(tag language.Tag) LocalizerInterface {
+	if l.isUnd(tag) {
+		tag = DefaultLanguage
+	}
+
+	localizer := &Localizer{
+		i18nStorage:        l.i18nStorage,
+		TranslationsFS:     l.TranslationsFS,
+		LocaleMatcher:      l.LocaleMatcher,
+		LanguageTag:        tag,
+		TranslationsPath:   l.TranslationsPath,
+		FallbackChain:      l.FallbackChain,
+		ContextKey:         l.ContextKey,
+		LanguageQueryParam: l.LanguageQueryParam,
+		OnMissing:          l.OnMissing,
+		loadMutex:          l.loadMutex,
+	}
+	localizer.LoadTranslations()
+
+	return localizer
+}
+
+// LocalizationMiddleware returns gin.HandlerFunc which will set localizer language by Accept-Language header.
+// Language from the LanguageQueryParam (e.g. ?lang=ru) takes precedence over the header if it's supported.
 // Result Localizer instance will share it's internal data (translations, bundles, etc) with instance which was used
-// to append middleware to gin.
+// to append middleware to gin. Localizer is stored in gin.Context using ContextKey.
 // Because of that all Localizer instances from this middleware will share *same* mutex. This mutex is used to wrap
 // i18n.Bundle methods (those aren't goroutine-safe to use).
 // Usage:
@@ -170,13 +290,39 @@ func (l *Localizer) Clone() CloneableLocalizer {
 //	engine.Use(localizer.LocalizationMiddleware())
 func (l *Localizer) LocalizationMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		clone := l.Clone().(LocaleControls)
-		clone.SetLocale(c.GetHeader("Accept-Language"))
-		c.Set(LocalizerContextKey, clone)
+		clone := l.Clone().(*Localizer)
+		clone.setRequestLanguage(c)
+		c.Set(l.contextKey(), clone)
+	}
+}
+
+// setRequestLanguage sets language from the LanguageQueryParam or from the Accept-Language header.
+func (l *Localizer) setRequestLanguage(c *gin.Context) {
+	if tag, ok := l.match(c.Query(l.languageQueryParam())); ok {
+		l.SetLanguage(tag)
+		return
+	}
+	l.SetLocale(c.GetHeader("Accept-Language"))
+}
+
+// languageQueryParam returns the query parameter which overrides Accept-Language header.
+func (l *Localizer) languageQueryParam() string {
+	if l.LanguageQueryParam == "" {
+		return DefaultLanguageQueryParam
 	}
+	return l.LanguageQueryParam
 }
 
-// LocalizationFuncMap returns template.FuncMap (html template is used) with one method - trans
+// contextKey returns the key which is used to store localizer in gin.Context.
+func (l *Localizer) contextKey() string {
+	if l.ContextKey == "" {
+		return LocalizerContextKey
+	}
+	return l.ContextKey
+}
+
+// LocalizationFuncMap returns template.FuncMap (html template is used) with methods trans, transTpl,
+// fmtDate and fmtNum.
 // Usage in code:
 //
 //	engine := gin.New()
@@ -192,6 +338,7 @@ func (l *Localizer) LocalizationMiddleware() gin.HandlerFunc {
 // Usage in templates:
 //
 //	<p class="info">{{"need_login_msg" | trans}}
+//	<p class="cart">{{transPlural "cart_items" .Count "user" .UserName}}
 //
 // You can borrow FuncMap from this method and add your functions to it.
 func (l *Localizer) LocalizationFuncMap() template.FuncMap {
@@ -202,31 +349,44 @@ func (l *Localizer) LocalizationFuncMap() template.FuncMap {
 				return l.GetLocalizedMessage(messageID)
 			}
 
-			if len(parts)%2 != 0 {
-				parts = append(parts, "")
-			}
-
-			partsMap := make(map[string]interface{}, len(parts)/2) // nolint:gomnd
+			return l.GetLocalizedTemplateMessage(messageID, templatePartsMap(parts))
+		},
+		"transPlural": func(messageID string, count interface{}, parts ...string) string {
+			return l.GetLocalizedPluralMessage(messageID, count, templatePartsMap(parts))
+		},
+		"fmtDate": l.FormatDateTime,
+		"fmtNum":  l.FormatNumber,
+	}
+}
 
-			for i := 0; i < len(parts)-1; i += 2 {
-				partsMap[parts[i]] = parts[i+1]
-			}
+// templatePartsMap converts key-value pairs to the template data. Missing value for the last key is set to "".
+func templatePartsMap(parts []string) map[string]interface{} {
+	if len(parts)%2 != 0 {
+		parts = append(parts, "")
+	}
 
-			return l.GetLocalizedTemplateMessage(messageID, partsMap)
-		},
+	partsMap := make(map[string]interface{}, len(parts)/2+1) // nolint:gomnd
+	for i := 0; i < len(parts)-1; i += 2 {
+		partsMap[parts[i]] = parts[i+1]
 	}
+
+	return partsMap
 }
 
 // createLocaleBundleByTag creates locale bundle by language tag.
 func (l *Localizer) createLocaleBundleByTag(tag language.Tag) *i18n.Bundle {
 	bundle := i18n.NewBundle(tag)
 	bundle.RegisterUnmarshalFunc("yml", yaml.Unmarshal)
+	bundle.RegisterUnmarshalFunc("yaml", yaml.Unmarshal)
+	bundle.RegisterUnmarshalFunc("json", json.Unmarshal)
+	bundle.RegisterUnmarshalFunc("toml", toml.Unmarshal)
 	l.loadTranslationsToBundle(bundle)
 
 	return bundle
 }
 
-// LoadTranslations will load all translation files from translations directory or from embedded box.
+// LoadTranslations will load all translation files from embedded box and from translations directory.
+// Both sources can be used at once, messages from the directory override embedded messages with the same ID.
 func (l *Localizer) LoadTranslations() {
 	defer l.loadMutex.Unlock()
 	l.loadMutex.Lock()
@@ -235,19 +395,20 @@ func (l *Localizer) LoadTranslations() {
 
 // loadTranslationsToBundle loads translations to provided bundle.
 func (l *Localizer) loadTranslationsToBundle(i18nBundle *i18n.Bundle) {
-	switch {
-	case l.TranslationsPath != "":
-		if err := l.loadFromDirectory(i18nBundle); err != nil {
+	// Embedded translations are loaded first, so the files from the directory can override them.
+	if l.TranslationsFS != nil {
+		if err := l.loadFromFS(i18nBundle); err != nil {
 			panic(err.Error())
 		}
-	default:
-		if err := l.loadFromFS(i18nBundle); err != nil {
+	}
+	if l.TranslationsPath != "" {
+		if err := l.loadFromDirectory(i18nBundle); err != nil {
 			panic(err.Error())
 		}
 	}
 }
 
-// LoadTranslations will load all translation files from translations directory.
+// LoadTranslations will load all translation files (YAML, JSON or TOML) from translations directory.
 func (l *Localizer) loadFromDirectory(i18nBundle *i18n.Bundle) error {
 	files, err := os.ReadDir(l.TranslationsPath)
 	if err != nil {
@@ -255,7 +416,7 @@ func (l *Localizer) loadFromDirectory(i18nBundle *i18n.Bundle) error {
 	}
 
 	for _, f := range files {
-		if !f.IsDir() {
+		if !f.IsDir() && isTranslationFile(f.Name()) {
 			i18nBundle.MustLoadMessageFile(path.Join(l.TranslationsPath, f.Name()))
 		}
 	}
@@ -264,50 +425,97 @@ func (l *Localizer) loadFromDirectory(i18nBundle *i18n.Bundle) error {
 }
 
 // LoadTranslations will load all translation files from embedding in binary.
+// Subdirectories are walked recursively. Language can be specified in the file name (messages.en.yml)
+// or by the parent directory name (en/messages.yml).
 func (l *Localizer) loadFromFS(i18nBundle *i18n.Bundle) error {
-	translationFiles, err := fs.ReadDir(l.TranslationsFS, string('.'))
-	if err != nil {
+	return fs.WalkDir(l.TranslationsFS, ".", func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() || !isTranslationFile(filePath) {
+			return nil
+		}
+
+		data, err := fs.ReadFile(l.TranslationsFS, filePath)
+		if err != nil {
+			return err
+		}
+
+		_, err = i18nBundle.ParseMessageFileBytes(data, translationFileName(filePath))
 		return err
+	})
+}
+
+// isTranslationFile returns true if file has one of the supported translation file extensions.
+func isTranslationFile(filePath string) bool {
+	switch path.Ext(filePath) {
+	case ".yml", ".yaml", ".json", ".toml":
+		return true
+	default:
+		return false
 	}
-	for _, file := range translationFiles {
-		if !file.IsDir() {
-			data, err := fs.ReadFile(l.TranslationsFS, file.Name())
-			if err != nil {
-				return err
-			}
-			if _, err := i18nBundle.ParseMessageFileBytes(data, file.Name()); err != nil {
-				return err
-			}
+}
+
+// translationFileName returns file name with language tag. The closest parent directory named as a language tag
+// will be used if file name doesn't contain it (en/messages.yml becomes messages.en.yml).
+func translationFileName(filePath string) string {
+	name := path.Base(filePath)
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	if _, err := language.Parse(base); err == nil || strings.Contains(base, ".") {
+		return name
+	}
+
+	for dir := path.Dir(filePath); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if _, err := language.Parse(path.Base(dir)); err == nil {
+			return base + "." + path.Base(dir) + ext
 		}
 	}
 
-	return nil
+	return name
 }
 
 // getLocalizer returns *i18n.Localizer with provided language tag. It will be created if not exist.
 func (l *Localizer) getLocalizer(tag language.Tag) *i18n.Localizer {
-	var localizer *i18n.Localizer
-
 	if l.isUnd(tag) {
 		tag = DefaultLanguage
 	}
 
-	if item, ok := l.i18nStorage.Load(tag); !ok {
-		l.i18nStorage.Store(tag, i18n.NewLocalizer(l.createLocaleBundleByTag(tag), tag.String()))
-	} else {
-		localizer = item.(*i18n.Localizer)
+	if item, ok := l.i18nStorage.Load(tag); ok {
+		return item.(*i18n.Localizer)
 	}
 
-	return localizer
+	item, _ := l.i18nStorage.LoadOrStore(tag, i18n.NewLocalizer(l.createLocaleBundleByTag(tag), tag.String()))
+	return item.(*i18n.Localizer)
 }
 
+// matchByString returns the best supported language for the Accept-Language header value. DefaultLanguage is
+// returned for the empty, wildcard-only or malformed values and when none of the languages is supported.
 func (l *Localizer) matchByString(al string) language.Tag {
-	tag, _ := language.MatchStrings(l.LocaleMatcher, al)
-	if l.isUnd(tag) {
-		return DefaultLanguage
+	if tag, ok := l.match(al); ok {
+		return tag
+	}
+	return DefaultLanguage
+}
+
+// match returns supported language for the Accept-Language string. Returns false if there is no such language.
+func (l *Localizer) match(al string) (language.Tag, bool) {
+	if al == "" {
+		return language.Und, false
+	}
+
+	tags, _, err := language.ParseAcceptLanguage(al)
+	if err != nil || len(tags) == 0 {
+		return language.Und, false
 	}
 
-	return tag
+	tag, _, confidence := l.LocaleMatcher.Match(tags...)
+	if confidence == language.No || l.isUnd(tag) {
+		return language.Und, false
+	}
+
+	return tag, true
 }
 
 func (l *Localizer) isUnd(tag language.Tag) bool {
@@ -331,18 +539,73 @@ func (l *Localizer) Preload(tags []language.Tag) {
 	}
 }
 
+// MissingMessage is a message which is missing in the language.
+type MissingMessage struct {
+	Language  language.Tag
+	MessageID string
+}
+
+// MissingMessagesError is returned by Localizer.RequireMessages if some messages are missing.
+type MissingMessagesError struct {
+	Missing []MissingMessage
+}
+
+// Error lists all missing messages.
+func (e *MissingMessagesError) Error() string {
+	items := make([]string, len(e.Missing))
+	for i, item := range e.Missing {
+		items[i] = item.Language.String() + ": " + item.MessageID
+	}
+	return "missing translations: " + strings.Join(items, ", ")
+}
+
+// RequireMessages checks that provided messages exist in every loaded language (current and preloaded ones).
+// FallbackChain is not used during the check. It can be used at startup to detect missing translations early.
+// Returns *MissingMessagesError with all missing messages sorted by language.
+func (l *Localizer) RequireMessages(ids []string) error {
+	l.getCurrentLocalizer()
+
+	var tags []language.Tag
+	l.i18nStorage.Range(func(key, _ any) bool {
+		tags = append(tags, key.(language.Tag))
+		return true
+	})
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].String() < tags[j].String()
+	})
+
+	var missing []MissingMessage
+	for _, tag := range tags {
+		localizer := l.getLocalizer(tag)
+		for _, id := range ids {
+			if _, err := localizer.Localize(&i18n.LocalizeConfig{MessageID: id}); err != nil {
+				missing = append(missing, MissingMessage{Language: tag, MessageID: id})
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		return &MissingMessagesError{Missing: missing}
+	}
+	return nil
+}
+
 // SetLanguage will change language using language tag.
 func (l *Localizer) SetLanguage(tag language.Tag) {
 	if l.isUnd(tag) {
 		tag = DefaultLanguage
 	}
 
+	l.languageMutex.Lock()
 	l.LanguageTag = tag
+	l.languageMutex.Unlock()
 	l.LoadTranslations()
 }
 
 // Language returns current language tag.
 func (l *Localizer) Language() language.Tag {
+	l.languageMutex.RLock()
+	defer l.languageMutex.RUnlock()
 	return l.LanguageTag
 }
 
@@ -354,33 +617,168 @@ func (l *Localizer) FetchLanguage() {
 }
 
 // GetLocalizedMessage will return localized message by it's ID. It doesn't use `Must` prefix in order to keep BC.
+// Missing translation is handled according to the OnMissing strategy.
 func (l *Localizer) GetLocalizedMessage(messageID string) string {
-	return l.getCurrentLocalizer().MustLocalize(&i18n.LocalizeConfig{MessageID: messageID})
+	return l.mustLocalize(&i18n.LocalizeConfig{MessageID: messageID})
+}
+
+// GetLocalizedMessageOr will return localized message by it's ID or fallback if message cannot be localized.
+// Use it for the optional or dynamic message IDs instead of GetLocalizedMessage.
+func (l *Localizer) GetLocalizedMessageOr(messageID, fallback string) string {
+	msg, err := l.localize(&i18n.LocalizeConfig{MessageID: messageID})
+	if err != nil {
+		return fallback
+	}
+
+	return msg
+}
+
+// LocalizedMessageOr will return message localized by the provided localizer or fallback if message cannot be
+// localized. FallbackMessageLocalizer implementation is used if localizer provides it.
+func LocalizedMessageOr(localizer MessageLocalizer, messageID, fallback string) string {
+	if loc, ok := localizer.(FallbackMessageLocalizer); ok {
+		return loc.GetLocalizedMessageOr(messageID, fallback)
+	}
+
+	msg, err := localizer.Localize(messageID)
+	if err != nil || msg == "" {
+		return fallback
+	}
+
+	return msg
 }
 
 // GetLocalizedTemplateMessage will return localized message with specified data.
 // It doesn't use `Must` prefix in order to keep BC. It uses text/template syntax: https://golang.org/pkg/text/template/
 func (l *Localizer) GetLocalizedTemplateMessage(messageID string, templateData map[string]interface{}) string {
-	return l.getCurrentLocalizer().MustLocalize(&i18n.LocalizeConfig{
+	return l.mustLocalize(&i18n.LocalizeConfig{
 		MessageID:    messageID,
 		TemplateData: templateData,
 	})
 }
 
+// GetLocalizedPluralMessage will return the plural form of the message which matches the count in the current
+// language. Count can be an integer, float or a numeric string. It's available in the message as {{.PluralCount}}
+// unless templateData already contains this key. Translation example:
+//
+//	items:
+//	  one: "{{.PluralCount}} item"
+//	  other: "{{.PluralCount}} items"
+func (l *Localizer) GetLocalizedPluralMessage(
+	messageID string, count interface{}, templateData map[string]interface{}) string {
+	if _, ok := templateData["PluralCount"]; !ok {
+		data := make(map[string]interface{}, len(templateData)+1)
+		for key, val := range templateData {
+			data[key] = val
+		}
+		data["PluralCount"] = count
+		templateData = data
+	}
+
+	return l.mustLocalize(&i18n.LocalizeConfig{
+		MessageID:    messageID,
+		PluralCount:  count,
+		TemplateData: templateData,
+	})
+}
+
 // Localize will return localized message by it's ID, or error if message wasn't found.
 func (l *Localizer) Localize(messageID string) (string, error) {
-	return l.getCurrentLocalizer().Localize(&i18n.LocalizeConfig{MessageID: messageID})
+	return l.localize(&i18n.LocalizeConfig{MessageID: messageID})
 }
 
 // LocalizeTemplateMessage will return localized message with specified data, or error if message wasn't found
 // It uses text/template syntax: https://golang.org/pkg/text/template/
 func (l *Localizer) LocalizeTemplateMessage(messageID string, templateData map[string]interface{}) (string, error) {
-	return l.getCurrentLocalizer().Localize(&i18n.LocalizeConfig{
+	return l.localize(&i18n.LocalizeConfig{
 		MessageID:    messageID,
 		TemplateData: templateData,
 	})
 }
 
+// localize message using current language. Languages from the FallbackChain are used if message is missing.
+func (l *Localizer) localize(cfg *i18n.LocalizeConfig) (string, error) {
+	msg, err := l.getCurrentLocalizer().Localize(cfg)
+	var notFound *i18n.MessageNotFoundErr
+	if err == nil || !errors.As(err, &notFound) {
+		return msg, err
+	}
+
+	for _, tag := range l.FallbackChain {
+		if fallback, fallbackErr := l.getLocalizer(tag).Localize(cfg); fallbackErr == nil {
+			return fallback, nil
+		}
+	}
+
+	return msg, err
+}
+
+// mustLocalize is the same as localize, but it panics if message cannot be localized.
+// Missing translation is handled according to the OnMissing strategy.
+func (l *Localizer) mustLocalize(cfg *i18n.LocalizeConfig) string {
+	msg, err := l.localize(cfg)
+	if err == nil {
+		return msg
+	}
+
+	var notFound *i18n.MessageNotFoundErr
+	if !errors.As(err, &notFound) {
+		panic(err)
+	}
+
+	switch l.OnMissing {
+	case MissingTranslationReturnEmpty:
+		return ""
+	case MissingTranslationPanicInDebug:
+		if gin.IsDebugging() {
+			panic(err)
+		}
+		return cfg.MessageID
+	default:
+		return cfg.MessageID
+	}
+}
+
+// FormatDateTime formats provided time using current language and style (short, medium or long).
+// Medium style will be used for unknown styles, English layouts will be used for unsupported languages.
+// Usage in templates:
+//
+//	<p class="date">{{fmtDate .CreatedAt "long"}}</p>
+func (l *Localizer) FormatDateTime(t time.Time, style string) string {
+	lang := GetRootLanguageTag(l.Language())
+	layouts, ok := dateTimeLayouts[lang]
+	if !ok {
+		lang = language.English
+		layouts = dateTimeLayouts[lang]
+	}
+
+	layout, ok := layouts[style]
+	if !ok {
+		layout = layouts[DateTimeStyleMedium]
+	}
+
+	result := t.Format(layout)
+	if names, ok := monthNames[lang]; ok {
+		name := names[t.Month()-1]
+		if strings.Contains(layout, "January") {
+			result = strings.Replace(result, t.Month().String(), name, 1)
+		} else if strings.Contains(layout, "Jan") {
+			result = strings.Replace(result, t.Month().String()[:3], string([]rune(name)[:3]), 1)
+		}
+	}
+
+	return result
+}
+
+// FormatNumber formats provided number using grouping and decimal separators of the current language.
+// Any integer or floating point type can be passed, integers are formatted without precision loss.
+// Usage in templates:
+//
+//	<p class="count">{{fmtNum .Count}}</p>
+func (l *Localizer) FormatNumber(n interface{}) string {
+	return message.NewPrinter(l.Language()).Sprint(number.Decimal(n))
+}
+
 // BadRequestLocalized is same as errorutil.BadRequest(string), but passed string will be localized.
 func (l *Localizer) BadRequestLocalized(err string) (int, interface{}) {
 	return errorutil.BadRequest(l.GetLocalizedMessage(err))
@@ -401,12 +799,32 @@ func (l *Localizer) InternalServerErrorLocalized(err string) (int, interface{})
 	return errorutil.InternalServerError(l.GetLocalizedMessage(err))
 }
 
+// ConflictLocalized is same as errorutil.Conflict(string), but passed string will be localized.
+func (l *Localizer) ConflictLocalized(err string) (int, interface{}) {
+	return errorutil.Conflict(l.GetLocalizedMessage(err))
+}
+
+// UnprocessableEntityLocalized is same as errorutil.UnprocessableEntity(string), but passed string will be localized.
+func (l *Localizer) UnprocessableEntityLocalized(err string) (int, interface{}) {
+	return errorutil.UnprocessableEntity(l.GetLocalizedMessage(err))
+}
+
+// TooManyRequestsLocalized is same as errorutil.TooManyRequests(string), but passed string will be localized.
+func (l *Localizer) TooManyRequestsLocalized(err string) (int, interface{}) {
+	return errorutil.TooManyRequests(l.GetLocalizedMessage(err))
+}
+
 // GetContextLocalizer returns localizer from context if it is present there.
-// Language will be set using Accept-Language header and root language tag.
-func GetContextLocalizer(c *gin.Context) (loc LocalizerInterface, ok bool) {
-	loc, ok = extractLocalizerFromContext(c)
+// Language will be set using the language query parameter or Accept-Language header and root language tag.
+// Custom context key can be provided if it was set in the Localizer.ContextKey, LocalizerContextKey is used otherwise.
+func GetContextLocalizer(c *gin.Context, key ...string) (loc LocalizerInterface, ok bool) {
+	loc, ok = extractLocalizerFromContext(c, key...)
 	if loc != nil {
-		loc.SetLocale(c.GetHeader("Accept-Language"))
+		if localizer, isLocalizer := loc.(*Localizer); isLocalizer {
+			localizer.setRequestLanguage(c)
+		} else {
+			loc.SetLocale(c.GetHeader("Accept-Language"))
+		}
 
 		lang := GetRootLanguageTag(loc.Language())
 		if lang != loc.Language() {
@@ -418,20 +836,26 @@ func GetContextLocalizer(c *gin.Context) (loc LocalizerInterface, ok bool) {
 }
 
 // MustGetContextLocalizer returns Localizer instance if it exists in provided context. Panics otherwise.
-func MustGetContextLocalizer(c *gin.Context) LocalizerInterface {
-	if localizer, ok := GetContextLocalizer(c); ok {
+// Custom context key can be provided the same way as in GetContextLocalizer.
+func MustGetContextLocalizer(c *gin.Context, key ...string) LocalizerInterface {
+	if localizer, ok := GetContextLocalizer(c, key...); ok {
 		return localizer
 	}
 	panic("localizer is not present in provided context")
 }
 
 // extractLocalizerFromContext returns localizer from context if it exist there.
-func extractLocalizerFromContext(c *gin.Context) (LocalizerInterface, bool) {
+func extractLocalizerFromContext(c *gin.Context, key ...string) (LocalizerInterface, bool) {
 	if c == nil {
 		return nil, false
 	}
 
-	if item, ok := c.Get(LocalizerContextKey); ok {
+	contextKey := LocalizerContextKey
+	if len(key) > 0 && key[0] != "" {
+		contextKey = key[0]
+	}
+
+	if item, ok := c.Get(contextKey); ok {
 		if localizer, ok := item.(LocalizerInterface); ok {
 			return localizer, true
 		}
@@ -1,6 +1,9 @@
 package core
 
 import (
+	"bytes"
+	"errors"
+	"html/template"
 	"math/rand"
 	"net/http"
 	"net/http/httptest"
@@ -9,6 +12,7 @@ import (
 	"path"
 	"sync"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -18,6 +22,7 @@ import (
 	"golang.org/x/text/language"
 
 	"github.com/retailcrm/mg-transport-core/v2/core/util/errorutil"
+	"github.com/retailcrm/mg-transport-core/v2/core/util/testutil"
 )
 
 var (
@@ -100,6 +105,151 @@ func (l *LocalizerTest) Test_LocalizationMiddleware_Context() {
 	assert.Equal(l.T(), "Тестовое сообщение", ruLocalizer.GetLocalizedMessage("message"))
 }
 
+func (l *LocalizerTest) Test_LocalizationMiddleware_CustomContextKey() {
+	localizer := NewLocalizer(language.English, DefaultLocalizerMatcher(), testTranslationsDir).(*Localizer)
+	localizer.ContextKey = "customLocalizer"
+	c := l.getContextWithLang(language.Spanish)
+	localizer.LocalizationMiddleware()(c)
+
+	_, ok := c.Get(LocalizerContextKey)
+	l.Assert().False(ok)
+	_, ok = GetContextLocalizer(c)
+	l.Assert().False(ok)
+
+	loc, ok := GetContextLocalizer(c, "customLocalizer")
+	l.Require().True(ok)
+	l.Assert().Equal(language.Spanish, loc.Language())
+	l.Assert().Equal("Mensaje de prueba", loc.GetLocalizedMessage("message"))
+	l.Assert().Equal("customLocalizer", loc.(*Localizer).ContextKey)
+	l.Assert().NotNil(MustGetContextLocalizer(c, "customLocalizer"))
+}
+
+func (l *LocalizerTest) Test_LocalizationMiddleware_LanguageQueryParam() {
+	c := l.getContextWithLang(language.English)
+	c.Request.URL.RawQuery = "lang=ru"
+	l.localizer.LocalizationMiddleware()(c)
+	l.Assert().Equal(language.Russian, MustGetContextLocalizer(c).Language())
+
+	c = l.getContextWithLang(language.Spanish)
+	c.Request.URL.RawQuery = "lang=invalid-"
+	l.localizer.LocalizationMiddleware()(c)
+	l.Assert().Equal(language.Spanish, MustGetContextLocalizer(c).Language())
+
+	c = l.getContextWithLang(language.Spanish)
+	c.Request.URL.RawQuery = "lang=zz"
+	l.localizer.LocalizationMiddleware()(c)
+	l.Assert().Equal(language.Spanish, MustGetContextLocalizer(c).Language())
+}
+
+func (l *LocalizerTest) Test_LocalizationMiddleware_CustomLanguageQueryParam() {
+	localizer := NewLocalizer(language.English, DefaultLocalizerMatcher(), testTranslationsDir).(*Localizer)
+	localizer.LanguageQueryParam = "locale"
+
+	c := l.getContextWithLang(language.English)
+	c.Request.URL.RawQuery = "lang=ru&locale=es"
+	localizer.LocalizationMiddleware()(c)
+	l.Assert().Equal(language.Spanish, MustGetContextLocalizer(c).Language())
+}
+
+func (l *LocalizerTest) Test_OnMissing() {
+	localizer := NewLocalizer(language.English, DefaultLocalizerMatcher(), testTranslationsDir).(*Localizer)
+	l.Assert().Equal(MissingTranslationReturnID, localizer.OnMissing)
+	l.Assert().Equal("missing_key", localizer.GetLocalizedMessage("missing_key"))
+	l.Assert().Equal("missing_key", localizer.GetLocalizedTemplateMessage("missing_key", map[string]interface{}{}))
+	l.Assert().Equal("Test message", localizer.GetLocalizedMessage("message"))
+
+	localizer.OnMissing = MissingTranslationReturnEmpty
+	l.Assert().Equal("", localizer.GetLocalizedMessage("missing_key"))
+	l.Assert().Equal("", localizer.GetLocalizedTemplateMessage("missing_key", nil))
+	l.Assert().Equal("", localizer.ForLanguage(language.Russian).GetLocalizedMessage("missing_key"))
+	l.Assert().Equal("", localizer.Clone().(*Localizer).GetLocalizedMessage("missing_key"))
+}
+
+func (l *LocalizerTest) Test_OnMissing_PanicInDebug() {
+	mode := gin.Mode()
+	defer gin.SetMode(mode)
+	localizer := NewLocalizer(language.English, DefaultLocalizerMatcher(), testTranslationsDir).(*Localizer)
+	localizer.OnMissing = MissingTranslationPanicInDebug
+
+	gin.SetMode(gin.DebugMode)
+	l.Assert().Panics(func() {
+		localizer.GetLocalizedMessage("missing_key")
+	})
+	l.Assert().Panics(func() {
+		localizer.GetLocalizedTemplateMessage("missing_key", nil)
+	})
+
+	gin.SetMode(gin.ReleaseMode)
+	l.Assert().Equal("missing_key", localizer.GetLocalizedMessage("missing_key"))
+	l.Assert().Equal("missing_key", localizer.GetLocalizedTemplateMessage("missing_key", nil))
+}
+
+func (l *LocalizerTest) Test_LocalizationMiddleware_DefaultContextKey() {
+	c := l.getContextWithLang(language.Russian)
+	l.localizer.LocalizationMiddleware()(c)
+
+	_, ok := c.Get(LocalizerContextKey)
+	l.Assert().True(ok)
+
+	loc, ok := GetContextLocalizer(c, "")
+	l.Require().True(ok)
+	l.Assert().Equal("Тестовое сообщение", loc.GetLocalizedMessage("message"))
+}
+
+func (l *LocalizerTest) Test_FallbackChain() {
+	dir := l.T().TempDir()
+	l.Require().NoError(os.WriteFile(path.Join(dir, "translate.en.yml"),
+		[]byte("only_en: English only\ncommon: Common"), os.ModePerm))
+	l.Require().NoError(os.WriteFile(path.Join(dir, "translate.es.yml"),
+		[]byte("common: Común"), os.ModePerm))
+
+	esMX := language.MustParse("es-MX")
+	localizer := NewLocalizer(esMX, DefaultLocalizerMatcher(), dir).(*Localizer)
+	_, err := localizer.Localize("only_en")
+	l.Assert().Error(err)
+
+	localizer.FallbackChain = []language.Tag{language.Spanish, language.English}
+	l.Assert().Equal(esMX, localizer.Language())
+
+	msg, err := localizer.Localize("only_en")
+	l.Require().NoError(err)
+	l.Assert().Equal("English only", msg)
+
+	msg, err = localizer.Localize("common")
+	l.Require().NoError(err)
+	l.Assert().Equal("Común", msg)
+
+	_, err = localizer.Localize("missing")
+	l.Assert().Error(err)
+
+	clone := localizer.Clone().(*Localizer)
+	l.Assert().Equal(localizer.FallbackChain, clone.FallbackChain)
+}
+
+func (l *LocalizerTest) Test_RequireMessages() {
+	dir := l.T().TempDir()
+	l.Require().NoError(os.WriteFile(path.Join(dir, "translate.en.yml"),
+		[]byte("only_en: English only\ncommon: Common"), os.ModePerm))
+	l.Require().NoError(os.WriteFile(path.Join(dir, "translate.es.yml"),
+		[]byte("common: Común"), os.ModePerm))
+
+	localizer := NewLocalizer(language.English, DefaultLocalizerMatcher(), dir).(*Localizer)
+	localizer.FallbackChain = []language.Tag{language.English}
+	localizer.Preload([]language.Tag{language.Spanish})
+
+	l.Assert().NoError(localizer.RequireMessages([]string{"common"}))
+
+	err := localizer.RequireMessages([]string{"common", "only_en", "missing"})
+	var missingErr *MissingMessagesError
+	l.Require().ErrorAs(err, &missingErr)
+	l.Assert().Equal([]MissingMessage{
+		{Language: language.English, MessageID: "missing"},
+		{Language: language.Spanish, MessageID: "only_en"},
+		{Language: language.Spanish, MessageID: "missing"},
+	}, missingErr.Missing)
+	l.Assert().Equal("missing translations: en: missing, es: only_en, es: missing", err.Error())
+}
+
 func (l *LocalizerTest) Test_LocalizationMiddleware_Httptest() {
 	var wg sync.WaitGroup
 	l.localizer.Preload(DefaultLanguages)
@@ -148,6 +298,12 @@ func (l *LocalizerTest) Test_LocalizationFuncMap() {
 	functions := l.localizer.LocalizationFuncMap()
 	_, ok := functions["trans"]
 	assert.True(l.T(), ok)
+	_, ok = functions["fmtDate"]
+	assert.True(l.T(), ok)
+	_, ok = functions["fmtNum"]
+	assert.True(l.T(), ok)
+	_, ok = functions["transPlural"]
+	assert.True(l.T(), ok)
 }
 
 func (l *LocalizerTest) Test_GetLocalizedMessage() {
@@ -159,6 +315,24 @@ func (l *LocalizerTest) Test_GetLocalizedMessage() {
 	assert.Equal(l.T(), "Test message", message)
 }
 
+func (l *LocalizerTest) Test_GetLocalizedMessageOr() {
+	localizer := l.localizer.(*Localizer).ForLanguage(language.Russian).(FallbackMessageLocalizer)
+
+	l.Assert().Equal("Тестовое сообщение", localizer.GetLocalizedMessageOr("message", "fallback"))
+	l.Assert().Equal("fallback", localizer.GetLocalizedMessageOr("missing", "fallback"))
+	l.Assert().NotPanics(func() {
+		localizer.GetLocalizedMessageOr("", "")
+	})
+}
+
+func (l *LocalizerTest) Test_LocalizedMessageOr() {
+	localizer := l.localizer.(*Localizer).ForLanguage(language.Russian)
+
+	l.Assert().Equal("Тестовое сообщение", LocalizedMessageOr(localizer, "message", "fallback"))
+	l.Assert().Equal("fallback", LocalizedMessageOr(localizer, "missing", "fallback"))
+	l.Assert().Equal("fallback", LocalizedMessageOr(messageLocalizerMock{}, "message", "fallback"))
+}
+
 func (l *LocalizerTest) Test_Clone() {
 	defer func() {
 		require.Nil(l.T(), recover())
@@ -172,6 +346,42 @@ func (l *LocalizerTest) Test_Clone() {
 	assert.Equal(l.T(), "Тестовое сообщение", localizer.GetLocalizedMessage("message"))
 }
 
+func (l *LocalizerTest) Test_ForLanguage() {
+	l.localizer.SetLanguage(language.English)
+	localizer := l.localizer.(*Localizer).ForLanguage(language.Spanish)
+
+	assert.Equal(l.T(), language.English, l.localizer.Language())
+	assert.Equal(l.T(), language.Spanish, localizer.Language())
+	assert.Equal(l.T(), "Mensaje de prueba", localizer.GetLocalizedMessage("message"))
+	assert.Equal(l.T(), "Test message", l.localizer.GetLocalizedMessage("message"))
+	assert.Equal(l.T(), DefaultLanguage, l.localizer.(*Localizer).ForLanguage(language.Und).Language())
+}
+
+func (l *LocalizerTest) Test_ConcurrentSetLocale() {
+	localizer := l.localizer.Clone().(LocalizerInterface)
+	locales := []string{"en", "es", "ru"}
+	wg := &sync.WaitGroup{}
+
+	for i := 0; i < 10; i++ {
+		wg.Add(2) // nolint:mnd
+		go func(locale string) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				localizer.SetLocale(locale)
+			}
+		}(locales[i%len(locales)])
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				assert.NotEmpty(l.T(), localizer.GetLocalizedMessage("message"))
+				assert.NotEmpty(l.T(), localizer.Language().String())
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
 func (l *LocalizerTest) Test_GetLocalizedTemplateMessage() {
 	defer func() {
 		require.Nil(l.T(), recover())
@@ -188,6 +398,21 @@ func (l *LocalizerTest) Test_BadRequestLocalized() {
 	assert.Equal(l.T(), "Test message", resp.(errorutil.Response).Error)
 }
 
+func (l *LocalizerTest) Test_CustomStatusLocalized() {
+	localizer, ok := l.localizer.(*Localizer).ForLanguage(language.Russian).(ExtendedHTTPResponseLocalizer)
+	l.Require().True(ok)
+	for status, fn := range map[int]func(string) (int, interface{}){
+		http.StatusConflict:            localizer.ConflictLocalized,
+		http.StatusUnprocessableEntity: localizer.UnprocessableEntityLocalized,
+		http.StatusTooManyRequests:     localizer.TooManyRequestsLocalized,
+	} {
+		code, resp := fn("message")
+
+		assert.Equal(l.T(), status, code)
+		assert.Equal(l.T(), "Тестовое сообщение", resp.(errorutil.Response).Error)
+	}
+}
+
 // getContextWithLang generates context with Accept-Language header.
 func (l *LocalizerTest) getContextWithLang(tag language.Tag) *gin.Context {
 	urlInstance, _ := url.Parse("https://example.com")
@@ -222,6 +447,10 @@ func TestLocalizer_DefaultLocalizerBundle(t *testing.T) {
 	assert.NotNil(t, DefaultLocalizerBundle())
 }
 
+func TestLocalizer_ContextKey(t *testing.T) {
+	assert.Equal(t, LocalizerContextKey, testutil.LocalizerContextKey)
+}
+
 func TestLocalizer_LocalizerBundle(t *testing.T) {
 	assert.NotNil(t, LocalizerBundle(language.Russian))
 }
@@ -237,3 +466,180 @@ func TestLocalizer_NoDirectory(t *testing.T) {
 		path.Join(os.TempDir(), "this directory should not exist"),
 	)
 }
+
+func TestLocalizer_FormatDateTime(t *testing.T) {
+	createTestLangFiles(t)
+	localizer := NewLocalizer(language.English, DefaultLocalizerMatcher(), testTranslationsDir).(*Localizer)
+	date := time.Date(2024, time.March, 5, 14, 7, 9, 0, time.UTC)
+
+	assert.Equal(t, "3/5/24 2:07 PM", localizer.FormatDateTime(date, DateTimeStyleShort))
+	assert.Equal(t, "Mar 5, 2024 2:07 PM", localizer.FormatDateTime(date, DateTimeStyleMedium))
+	assert.Equal(t, "March 5, 2024 2:07:09 PM", localizer.FormatDateTime(date, DateTimeStyleLong))
+	assert.Equal(t, "Mar 5, 2024 2:07 PM", localizer.FormatDateTime(date, "unknown"))
+
+	localizer.SetLanguage(language.Russian)
+	assert.Equal(t, "05.03.24 14:07", localizer.FormatDateTime(date, DateTimeStyleShort))
+	assert.Equal(t, "05.03.2024 14:07", localizer.FormatDateTime(date, DateTimeStyleMedium))
+	assert.Equal(t, "5 марта 2024 г. 14:07:09", localizer.FormatDateTime(date, DateTimeStyleLong))
+
+	localizer.SetLanguage(language.Spanish)
+	assert.Equal(t, "5 mar 2024 14:07", localizer.FormatDateTime(date, DateTimeStyleMedium))
+	assert.Equal(t, "5 de marzo de 2024 14:07:09", localizer.FormatDateTime(date, DateTimeStyleLong))
+
+	localizer.SetLanguage(language.German)
+	assert.Equal(t, "March 5, 2024 2:07:09 PM", localizer.FormatDateTime(date, DateTimeStyleLong))
+}
+
+func TestLocalizer_FormatNumber(t *testing.T) {
+	createTestLangFiles(t)
+	localizer := NewLocalizer(language.English, DefaultLocalizerMatcher(), testTranslationsDir).(*Localizer)
+
+	assert.Equal(t, "1,234,567.89", localizer.FormatNumber(1234567.89))
+	assert.Equal(t, "-42", localizer.FormatNumber(-42))
+	assert.Equal(t, "9,007,199,254,740,993", localizer.FormatNumber(int64(9007199254740993)))
+	assert.Equal(t, "18,446,744,073,709,551,615", localizer.FormatNumber(uint64(18446744073709551615)))
+
+	var buf bytes.Buffer
+	tpl := template.Must(template.New("num").Funcs(localizer.LocalizationFuncMap()).Parse(`{{fmtNum .}}`))
+	require.NoError(t, tpl.Execute(&buf, 1234567))
+	assert.Equal(t, "1,234,567", buf.String())
+
+	localizer.SetLanguage(language.Russian)
+	assert.Equal(t, "1\u00a0234\u00a0567,89", localizer.FormatNumber(1234567.89))
+}
+
+func TestLocalizer_LoadFromNestedFS(t *testing.T) {
+	translationsFS := fstest.MapFS{
+		"translate.en.yml":      {Data: []byte("message: Test message")},
+		"ru/messages.yml":       {Data: []byte("message: Тестовое сообщение")},
+		"es/nested/errors.yaml": {Data: []byte("error: Error de prueba")},
+		"es/messages.es.yml":    {Data: []byte("message: Mensaje de prueba")},
+		"README.md":             {Data: []byte("not a translation")},
+	}
+
+	localizer := NewLocalizerFS(language.English, DefaultLocalizerMatcher(), translationsFS)
+	assert.Equal(t, "Test message", localizer.GetLocalizedMessage("message"))
+
+	localizer.SetLanguage(language.Russian)
+	assert.Equal(t, "Тестовое сообщение", localizer.GetLocalizedMessage("message"))
+
+	localizer.SetLanguage(language.Spanish)
+	assert.Equal(t, "Mensaje de prueba", localizer.GetLocalizedMessage("message"))
+	assert.Equal(t, "Error de prueba", localizer.GetLocalizedMessage("error"))
+}
+
+func TestLocalizer_TransPlural(t *testing.T) {
+	translationsFS := fstest.MapFS{
+		"translate.en.yml": {Data: []byte("items:\n  one: \"{{.PluralCount}} item\"\n  other: \"{{.PluralCount}} items\"\n")},
+		"translate.ru.yml": {Data: []byte(`items:
+  one: "{{.PluralCount}} товар"
+  few: "{{.PluralCount}} товара"
+  many: "{{.PluralCount}} товаров"
+  other: "{{.PluralCount}} товара"
+cart:
+  one: "{{.PluralCount}} товар в корзине {{.user}}"
+  few: "{{.PluralCount}} товара в корзине {{.user}}"
+  many: "{{.PluralCount}} товаров в корзине {{.user}}"
+  other: "{{.PluralCount}} товара в корзине {{.user}}"
+`)},
+	}
+
+	localizer := NewLocalizerFS(language.Russian, DefaultLocalizerMatcher(), translationsFS).(*Localizer)
+	tpl := template.Must(template.New("plural").Funcs(localizer.LocalizationFuncMap()).
+		Parse(`{{transPlural "items" .}}|{{transPlural "cart" . "user" "Ивана"}}`))
+	render := func(count int) string {
+		var buf bytes.Buffer
+		require.NoError(t, tpl.Execute(&buf, count))
+		return buf.String()
+	}
+
+	assert.Equal(t, "0 товаров|0 товаров в корзине Ивана", render(0))
+	assert.Equal(t, "1 товар|1 товар в корзине Ивана", render(1))
+	assert.Equal(t, "3 товара|3 товара в корзине Ивана", render(3))
+	assert.Equal(t, "5 товаров|5 товаров в корзине Ивана", render(5))
+
+	localizer.SetLanguage(language.English)
+	assert.Equal(t, "1 item", localizer.GetLocalizedPluralMessage("items", 1, nil))
+	assert.Equal(t, "5 items", localizer.GetLocalizedPluralMessage("items", 5, nil))
+}
+
+func TestLocalizer_SetLocale_Fallback(t *testing.T) {
+	localizer := &Localizer{LocaleMatcher: language.NewMatcher([]language.Tag{language.Russian, language.English})}
+
+	for _, header := range []string{"", "*", "garbage;;;q=x", "!!!", "de-DE,de;q=0.9", "*;q=0.5"} {
+		assert.Equal(t, DefaultLanguage, localizer.matchByString(header), "header: %q", header)
+	}
+	assert.Equal(t, "ru", GetRootLanguageTag(localizer.matchByString("ru-RU,ru;q=0.9,en;q=0.8")).String())
+}
+
+func TestLocalizer_LoadFromFSAndDirectory(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(path.Join(dir, "translate.en.yml"), []byte("message: Overridden message"), 0600))
+
+	localizer := &Localizer{
+		i18nStorage:      &sync.Map{},
+		LocaleMatcher:    DefaultLocalizerMatcher(),
+		TranslationsPath: dir,
+		TranslationsFS: fstest.MapFS{
+			"translate.en.yml": {Data: []byte("message: Embedded message\nembedded: Embedded only")},
+		},
+		loadMutex: &sync.RWMutex{},
+	}
+	localizer.SetLanguage(language.English)
+	localizer.LoadTranslations()
+
+	assert.Equal(t, "Overridden message", localizer.GetLocalizedMessage("message"))
+	assert.Equal(t, "Embedded only", localizer.GetLocalizedMessage("embedded"))
+}
+
+func Test_translationFileName(t *testing.T) {
+	assert.Equal(t, "messages.en.yml", translationFileName("messages.en.yml"))
+	assert.Equal(t, "en.yml", translationFileName("en.yml"))
+	assert.Equal(t, "messages.ru.yml", translationFileName("ru/messages.yml"))
+	assert.Equal(t, "messages.es.yml", translationFileName("ru/messages.es.yml"))
+	assert.Equal(t, "errors.es.yml", translationFileName("es/nested/errors.yml"))
+	assert.Equal(t, "messages.yml", translationFileName("common/messages.yml"))
+}
+
+func TestLocalizer_LoadJSONAndTOML(t *testing.T) {
+	translationsFS := fstest.MapFS{
+		"translate.en.json": {Data: []byte(`{"message": "Test message", "message_template": "Test message with {{.data}}"}`)},
+		"translate.ru.toml": {Data: []byte(`message = "Тестовое сообщение"`)},
+	}
+
+	localizer := NewLocalizerFS(language.English, DefaultLocalizerMatcher(), translationsFS)
+	assert.Equal(t, "Test message", localizer.GetLocalizedMessage("message"))
+	assert.Equal(t, "Test message with value",
+		localizer.GetLocalizedTemplateMessage("message_template", map[string]interface{}{"data": "value"}))
+
+	localizer.SetLanguage(language.Russian)
+	assert.Equal(t, "Тестовое сообщение", localizer.GetLocalizedMessage("message"))
+}
+
+func TestLocalizer_LoadJSONFromDirectory(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(path.Join(dir, "translate.en.json"), []byte(`{"message": "Test message"}`), 0600))
+	require.NoError(t, os.WriteFile(path.Join(dir, "README.md"), []byte("not a translation"), 0600))
+
+	localizer := NewLocalizer(language.English, DefaultLocalizerMatcher(), dir)
+	assert.Equal(t, "Test message", localizer.GetLocalizedMessage("message"))
+}
+
+// messageLocalizerMock is a MessageLocalizer which doesn't implement FallbackMessageLocalizer.
+type messageLocalizerMock struct{}
+
+func (messageLocalizerMock) GetLocalizedMessage(id string) string {
+	return id
+}
+
+func (messageLocalizerMock) GetLocalizedTemplateMessage(id string, _ map[string]interface{}) string {
+	return id
+}
+
+func (messageLocalizerMock) Localize(string) (string, error) {
+	return "", errors.New("translation is missing")
+}
+
+func (messageLocalizerMock) LocalizeTemplateMessage(string, map[string]interface{}) (string, error) {
+	return "", errors.New("translation is missing")
+}
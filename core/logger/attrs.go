@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	json "github.com/goccy/go-json"
 
@@ -43,6 +44,33 @@ const HTTPStatusAttr = "statusCode"
 // HTTPStatusNameAttr represents the attribute name for the HTTP status name.
 const HTTPStatusNameAttr = "statusName"
 
+// HTTPRequestAttr represents the attribute name for the HTTP request.
+const HTTPRequestAttr = "request"
+
+// HTTPResponseAttr represents the attribute name for the HTTP response.
+const HTTPResponseAttr = "response"
+
+// HTTPURLAttr represents the attribute name for the HTTP request URL.
+const HTTPURLAttr = "url"
+
+// HTTPContentLengthAttr represents the attribute name for the HTTP content length.
+const HTTPContentLengthAttr = "contentLength"
+
+// HTTPHeadersAttr represents the attribute name for the HTTP headers.
+const HTTPHeadersAttr = "headers"
+
+// RedactedValue replaces values of the sensitive headers in the logs.
+const RedactedValue = "<redacted>"
+
+// RedactedHeaders contains headers which values will be replaced with RedactedValue by the HTTPRequest.
+var RedactedHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+}
+
 // Err returns a zap.Field with the given error value.
 func Err(err any) zap.Field {
 	if err == nil {
@@ -66,6 +94,46 @@ func HTTPStatusName(code int) zap.Field {
 	return zap.String(HTTPStatusNameAttr, http.StatusText(code))
 }
 
+// HTTPRequest returns a zap.Field with the given request method, URL and headers. Values of RedactedHeaders are hidden.
+func HTTPRequest(req *http.Request) zap.Field {
+	if req == nil {
+		return zap.String(HTTPRequestAttr, "<nil>")
+	}
+
+	headers := make(map[string]string, len(req.Header))
+	for name, values := range req.Header {
+		headers[name] = strings.Join(values, ", ")
+	}
+	for _, name := range RedactedHeaders {
+		if _, ok := headers[http.CanonicalHeaderKey(name)]; ok {
+			headers[http.CanonicalHeaderKey(name)] = RedactedValue
+		}
+	}
+
+	return zap.Dict(HTTPRequestAttr,
+		zap.String(HTTPMethodAttr, req.Method),
+		zap.Stringer(HTTPURLAttr, req.URL),
+		zap.Any(HTTPHeadersAttr, headers),
+	)
+}
+
+// HTTPResponse returns a zap.Field with the given response status code, content length and request method and URL.
+func HTTPResponse(resp *http.Response) zap.Field {
+	if resp == nil {
+		return zap.String(HTTPResponseAttr, "<nil>")
+	}
+
+	fields := make([]zap.Field, 0, 4) // nolint:mnd
+	if resp.Request != nil {
+		fields = append(fields, zap.String(HTTPMethodAttr, resp.Request.Method), zap.Stringer(HTTPURLAttr, resp.Request.URL))
+	}
+
+	return zap.Dict(HTTPResponseAttr, append(fields,
+		zap.Int(HTTPStatusAttr, resp.StatusCode),
+		zap.Int64(HTTPContentLengthAttr, resp.ContentLength),
+	)...)
+}
+
 // StreamID returns a zap.Fields with the give stream ID.
 func StreamID(val any) zap.Field {
 	switch item := val.(type) {
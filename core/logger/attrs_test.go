@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"io"
 	"net/http"
 	"testing"
@@ -200,6 +201,44 @@ func TestBody(t *testing.T) {
 	}
 }
 
+func TestHTTPRequest(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "https://example.com/api/v5/orders?limit=20", nil)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-KEY", "secret")
+	req.Header.Set("Authorization", "Bearer secret")
+
+	enc := zapcore.NewMapObjectEncoder()
+	HTTPRequest(req).AddTo(enc)
+
+	fields, ok := enc.Fields[HTTPRequestAttr].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, http.MethodPost, fields[HTTPMethodAttr])
+	assert.Equal(t, "https://example.com/api/v5/orders?limit=20", fields[HTTPURLAttr])
+	assert.Equal(t, map[string]string{
+		"Content-Type":  "application/json",
+		"X-Api-Key":     RedactedValue,
+		"Authorization": RedactedValue,
+	}, fields[HTTPHeadersAttr])
+	assert.Equal(t, "<nil>", HTTPRequest(nil).String)
+}
+
+func TestHTTPResponse(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://example.com/api/v5/orders", nil)
+	require.NoError(t, err)
+
+	enc := zapcore.NewMapObjectEncoder()
+	HTTPResponse(&http.Response{Request: req, StatusCode: http.StatusOK, ContentLength: 42}).AddTo(enc)
+
+	assert.Equal(t, map[string]interface{}{
+		HTTPMethodAttr:        http.MethodGet,
+		HTTPURLAttr:           "https://example.com/api/v5/orders",
+		HTTPStatusAttr:        int64(http.StatusOK),
+		HTTPContentLengthAttr: int64(42),
+	}, enc.Fields[HTTPResponseAttr])
+	assert.Equal(t, "<nil>", HTTPResponse(nil).String)
+}
+
 type readerMock struct {
 	mock.Mock
 }
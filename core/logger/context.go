@@ -0,0 +1,32 @@
+package logger
+
+import "context"
+
+// loggerCtxKey is used to store logger in the context.Context.
+type loggerCtxKey struct{}
+
+// ContextWithLogger returns copy of the context with provided logger. It can be used to propagate
+// connection- or account-scoped logger to the code which doesn't work with gin (jobs, workers, etc.).
+// Usage:
+//
+//	ctx := logger.ContextWithLogger(context.Background(), log.ForConnection(conn.URL))
+//	go processWebhook(ctx, data)
+func ContextWithLogger(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerCtxKey{}, l)
+}
+
+// FromContext returns logger stored by ContextWithLogger. Logger from the gin.Context (see GinMiddleware)
+// is returned if gin.Context is passed. No-op logger is returned if there is no logger in the context.
+func FromContext(ctx context.Context) Logger {
+	if ctx == nil {
+		return NewNil()
+	}
+	if l, ok := ctx.Value(loggerCtxKey{}).(Logger); ok && l != nil {
+		return l
+	}
+	if l, ok := ctx.Value(LoggerContextKey).(Logger); ok && l != nil {
+		return l
+	}
+
+	return NewNil()
+}
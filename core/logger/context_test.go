@@ -0,0 +1,32 @@
+package logger
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromContext(t *testing.T) {
+	log := NewDefault("json", true).ForConnection("conn")
+	ctx := ContextWithLogger(context.Background(), log)
+
+	assert.Same(t, log, FromContext(ctx))
+	assert.Same(t, log, FromContext(context.WithValue(ctx, struct{}{}, "value")))
+}
+
+func TestFromContext_Default(t *testing.T) {
+	assert.IsType(t, &Nil{}, FromContext(context.Background()))
+	assert.IsType(t, &Nil{}, FromContext(nil)) // nolint:staticcheck
+	assert.IsType(t, &Nil{}, FromContext(ContextWithLogger(context.Background(), nil)))
+}
+
+func TestFromContext_Gin(t *testing.T) {
+	log := NewDefault("json", true)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Set(LoggerContextKey, log)
+
+	assert.Same(t, log, FromContext(c))
+}
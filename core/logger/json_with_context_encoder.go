@@ -22,6 +22,7 @@
 package logger
 
 import (
+	"bytes"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -39,6 +40,9 @@ import (
 // For JSON-escaping; see jsonWithContextEncoder.safeAddString below.
 const _hex = "0123456789abcdef"
 
+// DefaultContextKey is the key of the object which contains all non-predefined fields in the JSON log entry.
+const DefaultContextKey = "context"
+
 var _jsonWithContextPool = NewPool(func() *jsonWithContextEncoder {
 	return &jsonWithContextEncoder{}
 })
@@ -48,14 +52,45 @@ func init() {
 }
 
 func registerJSONWithContext() {
-	err := zap.RegisterEncoder("json-with-context", func(config zapcore.EncoderConfig) (zapcore.Encoder, error) {
-		return NewJSONWithContextEncoder(config), nil
-	})
-	if err != nil {
+	if err := RegisterJSONWithContextEncoder("json-with-context"); err != nil {
 		panic(err)
 	}
 }
 
+// JSONEncoderOption configures the encoder returned by NewJSONWithContextEncoder.
+type JSONEncoderOption func(enc *jsonWithContextEncoder)
+
+// WithContextKey sets the key of the object which will contain all non-predefined fields.
+// DefaultContextKey is used if the key is empty.
+func WithContextKey(key string) JSONEncoderOption {
+	return func(enc *jsonWithContextEncoder) {
+		if key != "" {
+			enc.contextKey = key
+		}
+	}
+}
+
+// WithKeyDeduplication makes the encoder keep only the last value for each top-level key within the entry,
+// so {"foo":"bar","foo":"baz"} becomes {"foo":"baz"}. The key keeps its first position.
+// Note that the encoded entry is parsed once again to find the duplicates, so it's noticeably slower than the
+// default mode. Use it only if your log pipeline doesn't accept the duplicated keys.
+func WithKeyDeduplication() JSONEncoderOption {
+	return func(enc *jsonWithContextEncoder) {
+		enc.dedupeKeys = true
+	}
+}
+
+// RegisterJSONWithContextEncoder registers the JSON encoder with provided options under the provided name.
+// The name can be used later in the zap.Config.Encoding. Usage:
+//
+//	_ = logger.RegisterJSONWithContextEncoder("json-with-extra", logger.WithContextKey("extra"))
+//	log, err := zap.Config{Encoding: "json-with-extra", EncoderConfig: logger.EncoderConfigJSON()}.Build()
+func RegisterJSONWithContextEncoder(name string, opts ...JSONEncoderOption) error {
+	return zap.RegisterEncoder(name, func(config zapcore.EncoderConfig) (zapcore.Encoder, error) {
+		return NewJSONWithContextEncoder(config, opts...), nil
+	})
+}
+
 func putJSONWithContextEncoder(enc *jsonWithContextEncoder) {
 	if enc.reflectBuf != nil {
 		enc.reflectBuf.Free()
@@ -66,6 +101,8 @@ func putJSONWithContextEncoder(enc *jsonWithContextEncoder) {
 	enc.openNamespaces = 0
 	enc.reflectBuf = nil
 	enc.reflectEnc = nil
+	enc.contextKey = ""
+	enc.dedupeKeys = false
 	_jsonWithContextPool.Put(enc)
 }
 
@@ -74,6 +111,8 @@ type jsonWithContextEncoder struct {
 	buf            *buffer.Buffer
 	spaced         bool // include spaces after colons and commas
 	openNamespaces int
+	contextKey     string // key for the non-predefined fields
+	dedupeKeys     bool   // keep only the last value for the duplicated top-level keys
 
 	// for encoding generic values by reflection
 	reflectBuf *buffer.Buffer
@@ -91,9 +130,16 @@ type jsonWithContextEncoder struct {
 // This is permitted by the JSON specification, but not encouraged. Many
 // libraries will ignore duplicate key-value pairs (typically keeping the last
 // pair) when unmarshaling, but users should attempt to avoid adding duplicate
-// keys.
-func NewJSONWithContextEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
-	return newJSONWithContextEncoder(cfg, false)
+// keys. Use WithKeyDeduplication if duplicates are not acceptable.
+//
+// All fields except the predefined ones (handler, connection, account, streamId) are grouped into
+// the object with DefaultContextKey key. Use WithContextKey to change it.
+func NewJSONWithContextEncoder(cfg zapcore.EncoderConfig, opts ...JSONEncoderOption) zapcore.Encoder {
+	enc := newJSONWithContextEncoder(cfg, false)
+	for _, opt := range opts {
+		opt(enc)
+	}
+	return enc
 }
 
 func newJSONWithContextEncoder(cfg zapcore.EncoderConfig, spaced bool) *jsonWithContextEncoder {
@@ -112,6 +158,7 @@ func newJSONWithContextEncoder(cfg zapcore.EncoderConfig, spaced bool) *jsonWith
 		EncoderConfig: &cfg,
 		buf:           GetBufferPool(),
 		spaced:        spaced,
+		contextKey:    DefaultContextKey,
 	}
 }
 
@@ -381,6 +428,8 @@ func (enc *jsonWithContextEncoder) clone() *jsonWithContextEncoder {
 	clone.EncoderConfig = enc.EncoderConfig
 	clone.spaced = enc.spaced
 	clone.openNamespaces = enc.openNamespaces
+	clone.contextKey = enc.contextKey
+	clone.dedupeKeys = enc.dedupeKeys
 	clone.buf = GetBufferPool()
 	return clone
 }
@@ -444,12 +493,15 @@ func (enc *jsonWithContextEncoder) EncodeEntry(ent zapcore.Entry, fields []zapco
 		final.addElementSeparator()
 		final.buf.Write(enc.buf.Bytes())
 	}
-	addFields(final, fields)
+	addFields(final, final.contextKey, fields)
 	final.closeOpenNamespaces()
 	if ent.Stack != "" && final.StacktraceKey != "" {
 		final.AddString(final.StacktraceKey, ent.Stack)
 	}
 	final.buf.AppendByte('}')
+	if final.dedupeKeys {
+		final.dedupe()
+	}
 	final.buf.AppendString(final.LineEnding)
 
 	ret := final.buf
@@ -457,7 +509,7 @@ func (enc *jsonWithContextEncoder) EncodeEntry(ent zapcore.Entry, fields []zapco
 	return ret, nil
 }
 
-func addFields(enc zapcore.ObjectEncoder, fields []zapcore.Field) {
+func addFields(enc zapcore.ObjectEncoder, contextKey string, fields []zapcore.Field) {
 	m := make(map[string]interface{})
 	hasEntries := false
 	for _, f := range fields {
@@ -466,6 +518,15 @@ func addFields(enc zapcore.ObjectEncoder, fields []zapcore.Field) {
 			f.AddTo(enc)
 		default:
 			hasEntries = true
+			switch f.Type { // nolint:exhaustive
+			case zapcore.ObjectMarshalerType, zapcore.ArrayMarshalerType, zapcore.BoolType,
+				zapcore.Float64Type, zapcore.Float32Type:
+				// These types cannot be represented by the raw field values, groups are encoded as nested maps.
+				mapEnc := zapcore.NewMapObjectEncoder()
+				f.AddTo(mapEnc)
+				m[f.Key] = mapEnc.Fields[f.Key]
+				continue
+			}
 			if f.Interface != nil {
 				switch t := f.Interface.(type) {
 				case fmt.Stringer:
@@ -487,8 +548,54 @@ func addFields(enc zapcore.ObjectEncoder, fields []zapcore.Field) {
 		}
 	}
 	if hasEntries {
-		zap.Any("context", m).AddTo(enc)
+		zap.Any(contextKey, m).AddTo(enc)
+	}
+}
+
+// dedupe rewrites the encoded entry, keeping only the last value for each top-level key.
+// The buffer is left as is if there are no duplicates or if it cannot be parsed.
+func (enc *jsonWithContextEncoder) dedupe() {
+	dec := json.NewDecoder(bytes.NewReader(enc.buf.Bytes()))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return
 	}
+
+	var (
+		keys       []string
+		values     = make(map[string]json.RawMessage)
+		duplicates bool
+	)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return
+		}
+		var val json.RawMessage
+		if err := dec.Decode(&val); err != nil {
+			return
+		}
+		if _, exists := values[key]; exists {
+			duplicates = true
+		} else {
+			keys = append(keys, key)
+		}
+		values[key] = val
+	}
+	if !duplicates {
+		return
+	}
+
+	enc.truncate()
+	enc.buf.AppendByte('{')
+	for _, key := range keys {
+		enc.addKey(key)
+		enc.buf.Write(values[key])
+	}
+	enc.buf.AppendByte('}')
 }
 
 func (enc *jsonWithContextEncoder) truncate() {
@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func encodeJSONEntry(t *testing.T, enc zapcore.Encoder, fields ...zap.Field) map[string]interface{} {
+	buf, err := enc.EncodeEntry(zapcore.Entry{Level: zapcore.InfoLevel, Message: "test"}, fields)
+	require.NoError(t, err)
+	defer buf.Free()
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry), buf.String())
+	return entry
+}
+
+func TestJSONWithContextEncoder_DefaultContextKey(t *testing.T) {
+	entry := encodeJSONEntry(t, NewJSONWithContextEncoder(EncoderConfigJSON()),
+		zap.String(HandlerAttr, "handler"), zap.String("key", "value"))
+
+	assert.Equal(t, "handler", entry[HandlerAttr])
+	assert.Equal(t, map[string]interface{}{"key": "value"}, entry[DefaultContextKey])
+}
+
+func TestJSONWithContextEncoder_WithContextKey(t *testing.T) {
+	enc := NewJSONWithContextEncoder(EncoderConfigJSON(), WithContextKey("extra"))
+	entry := encodeJSONEntry(t, enc.Clone(), zap.String(HandlerAttr, "handler"), zap.Int("num", 1))
+
+	assert.Equal(t, "handler", entry[HandlerAttr])
+	assert.Equal(t, map[string]interface{}{"num": float64(1)}, entry["extra"])
+	assert.NotContains(t, entry, DefaultContextKey)
+}
+
+func TestRegisterJSONWithContextEncoder(t *testing.T) {
+	require.NoError(t, RegisterJSONWithContextEncoder("json-with-fields", WithContextKey("fields")))
+
+	cfg := zap.NewProductionConfig()
+	cfg.Encoding = "json-with-fields"
+	cfg.EncoderConfig = EncoderConfigJSON()
+	cfg.OutputPaths = []string{"stdout"}
+	_, err := cfg.Build()
+	assert.NoError(t, err)
+}
+
+func TestJSONWithContextEncoder_Duplicates(t *testing.T) {
+	enc := NewJSONWithContextEncoder(EncoderConfigJSON())
+	enc.AddString(HandlerAttr, "first")
+	buf, err := enc.EncodeEntry(zapcore.Entry{Message: "test"}, []zap.Field{zap.String(HandlerAttr, "second")})
+	require.NoError(t, err)
+	defer buf.Free()
+
+	assert.Contains(t, buf.String(), `"handler":"first","handler":"second"`)
+}
+
+func TestJSONWithContextEncoder_WithKeyDeduplication(t *testing.T) {
+	enc := NewJSONWithContextEncoder(EncoderConfigJSON(), WithKeyDeduplication())
+	enc.AddString(HandlerAttr, "first")
+	enc.AddString(ConnectionAttr, "conn")
+	buf, err := enc.EncodeEntry(zapcore.Entry{Message: "test"}, []zap.Field{
+		zap.String(HandlerAttr, "second"),
+		zap.String(HandlerAttr, "third"),
+		zap.String("key", "value"),
+	})
+	require.NoError(t, err)
+	defer buf.Free()
+
+	assert.Equal(t,
+		`{"level_name":"INFO","datetime":"0001-01-01T00:00:00Z","message":"test","handler":"third",`+
+			`"connection":"conn","context":{"key":"value"}}`+"\n",
+		buf.String())
+}
@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"regexp"
+
+	"go.uber.org/zap"
+)
+
+// DefaultPIIReplacement is used by the DefaultPIIMasker instead of the masked data.
+const DefaultPIIReplacement = "***"
+
+var (
+	// PIIEmailRegexp matches email addresses.
+	PIIEmailRegexp = regexp.MustCompile(`[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`)
+	// PIIPhoneRegexp matches phone number candidates with 7-15 digits which may be separated by spaces, dots, dashes
+	// or brackets. DefaultPIIMasker masks only the candidates which are valid phone numbers, see util.MaskPII.
+	PIIPhoneRegexp = regexp.MustCompile(`\+?\(?\d(?:[\s\-.()]{0,2}\d){6,14}`)
+)
+
+// DefaultPIIMasker masks emails and phone numbers. It is used by MaskedString and can be replaced
+// with custom masker in order to change masking patterns or replacement.
+var DefaultPIIMasker = NewPIIMasker(DefaultPIIReplacement, PIIEmailRegexp, PIIPhoneRegexp)
+
+// PIIMasker replaces personal data in the strings using provided patterns.
+type PIIMasker struct {
+	validators  map[*regexp.Regexp]func(string) bool
+	Replacement string
+	Patterns    []*regexp.Regexp
+}
+
+// NewPIIMasker returns masker which replaces every match of provided patterns with replacement.
+// Patterns are applied in provided order.
+func NewPIIMasker(replacement string, patterns ...*regexp.Regexp) *PIIMasker {
+	return &PIIMasker{Replacement: replacement, Patterns: patterns}
+}
+
+// WithValidator sets the function which checks every match of the pattern. Only the matches for which
+// validate returns true are replaced. It should be called before the masker is used.
+func (m *PIIMasker) WithValidator(pattern *regexp.Regexp, validate func(string) bool) *PIIMasker {
+	if m.validators == nil {
+		m.validators = make(map[*regexp.Regexp]func(string) bool)
+	}
+	m.validators[pattern] = validate
+	return m
+}
+
+// Mask returns string with all personal data replaced.
+func (m *PIIMasker) Mask(s string) string {
+	for _, pattern := range m.Patterns {
+		validate, ok := m.validators[pattern]
+		if !ok {
+			s = pattern.ReplaceAllLiteralString(s, m.Replacement)
+			continue
+		}
+
+		s = pattern.ReplaceAllStringFunc(s, func(match string) string {
+			if validate(match) {
+				return m.Replacement
+			}
+			return match
+		})
+	}
+	return s
+}
+
+// MaskedString returns a zap.Field with the given string value masked by the DefaultPIIMasker.
+func MaskedString(key, val string) zap.Field {
+	return zap.String(key, DefaultPIIMasker.Mask(val))
+}
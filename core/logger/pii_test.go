@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPIIMasker_Mask(t *testing.T) {
+	source := "Customer john.doe+test@example.com called from +7 (999) 123-45-67, order #1234 for 2 items"
+
+	assert.Equal(t,
+		"Customer *** called from ***, order #1234 for 2 items",
+		DefaultPIIMasker.Mask(source))
+	assert.Equal(t, "nothing to mask here", DefaultPIIMasker.Mask("nothing to mask here"))
+}
+
+func TestPIIMasker_CustomPattern(t *testing.T) {
+	masker := NewPIIMasker("[hidden]", regexp.MustCompile(`secret-\w+`))
+
+	assert.Equal(t, "token: [hidden]", masker.Mask("token: secret-abc"))
+}
+
+func TestPIIMasker_WithValidator(t *testing.T) {
+	pattern := regexp.MustCompile(`\d+`)
+	masker := NewPIIMasker("***", pattern).WithValidator(pattern, func(match string) bool {
+		return len(match) > 3
+	})
+
+	assert.Equal(t, "order 123 for ***", masker.Mask("order 123 for 12345"))
+}
+
+func TestMaskedString(t *testing.T) {
+	field := MaskedString("text", "email me at user@example.org or call 89991234567")
+
+	assert.Equal(t, "text", field.Key)
+	assert.Equal(t, "email me at *** or call ***", field.String)
+}
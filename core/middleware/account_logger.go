@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/db/models"
+	"github.com/retailcrm/mg-transport-core/v2/core/logger"
+)
+
+// AccountLogger returns middleware which will put connection- and account-scoped logger into the "logger"
+// context field. Sentry component and logger.MustGet will use this logger, so every downstream log entry
+// (including panic recovery) will contain the same connection and account fields.
+//
+// The middleware must be placed after the middlewares that put connection and account into the context.
+// Logger from logger.GinMiddleware will be used instead of the base logger if it is present in the context.
+// Context logger won't be changed if neither connection nor account is present in the context.
+//
+// Usage:
+//
+//	engine.Use(logger.GinMiddleware(log), loadConnection(), middleware.AccountLogger(log, "connection", "account"))
+func AccountLogger(base logger.Logger, connKey, accKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		conn, hasConn := c.Get(connKey)
+		acc, hasAcc := c.Get(accKey)
+		if !hasConn && !hasAcc {
+			return
+		}
+
+		log := base
+		if item, ok := c.Get(logger.LoggerContextKey); ok {
+			if ctxLogger, ok := item.(logger.Logger); ok && ctxLogger != nil {
+				log = ctxLogger
+			}
+		}
+		if log == nil {
+			return
+		}
+
+		if hasConn {
+			log = log.ForConnection(loggerValue(conn))
+		}
+		if hasAcc {
+			log = log.ForAccount(loggerValue(acc))
+		}
+
+		c.Set(logger.LoggerContextKey, log)
+	}
+}
+
+// loggerValue converts the context value to the value which can be safely used as a log field.
+// Models are converted to their identifiers to prevent credentials leakage into the logs.
+func loggerValue(item any) any {
+	switch val := item.(type) {
+	case models.Connection:
+		return val.Address()
+	case *models.Connection:
+		return val.Address()
+	case models.Account:
+		return val.Name
+	case *models.Account:
+		return val.Name
+	case fmt.Stringer:
+		return val.String()
+	default:
+		return val
+	}
+}
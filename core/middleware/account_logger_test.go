@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/db/models"
+	"github.com/retailcrm/mg-transport-core/v2/core/logger"
+	"github.com/retailcrm/mg-transport-core/v2/core/util/testutil"
+)
+
+func TestAccountLogger(t *testing.T) {
+	log := testutil.NewBufferedLoggerSilent()
+	g := gin.New()
+	g.Use(func(c *gin.Context) {
+		c.Set("connection", &models.Connection{URL: "https://test.retailcrm.pro", Key: "secret"})
+		c.Set("account", models.Account{Name: "@account"})
+	}, AccountLogger(log, "connection", "account"))
+	g.GET("/", func(c *gin.Context) {
+		logger.MustGet(c).Info("message")
+		c.Status(http.StatusOK)
+	})
+
+	rr := httptest.NewRecorder()
+	g.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	items, err := testutil.NewJSONRecordScanner(log).ScanAll()
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "message", items[0].Message)
+	assert.Equal(t, "https://test.retailcrm.pro", items[0].Connection)
+	assert.Equal(t, "@account", items[0].Account)
+	assert.NotContains(t, log.String(), "secret")
+}
+
+func TestAccountLogger_PreservesContextLogger(t *testing.T) {
+	log := testutil.NewBufferedLoggerSilent()
+	g := gin.New()
+	g.Use(logger.GinMiddleware(log), func(c *gin.Context) {
+		c.Set("connection", "conn")
+	}, AccountLogger(logger.NewNil(), "connection", "account"))
+	g.GET("/", func(c *gin.Context) {
+		logger.MustGet(c).Info("message")
+		c.Status(http.StatusOK)
+	})
+
+	g.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	items, err := testutil.NewJSONRecordScanner(log).ScanAll()
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "message", items[0].Message)
+	assert.Equal(t, "conn", items[0].Connection)
+	assert.Empty(t, items[0].Account)
+	assert.NotEmpty(t, items[0].StreamID)
+}
+
+func TestAccountLogger_NoEntities(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	AccountLogger(logger.NewNil(), "connection", "account")(c)
+
+	_, exists := c.Get(logger.LoggerContextKey)
+	assert.False(t, exists)
+}
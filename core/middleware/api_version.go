@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/util/errorutil"
+)
+
+// APIVersionContextKey is a key which is used to store parsed API version in gin.Context.
+const APIVersionContextKey = "api_version"
+
+// RequireAPIVersion returns middleware which checks that provided header contains one of the supported API versions.
+// Request will be aborted with 400 Bad Request if header is missing or contains unsupported version.
+// Error message will be localized using localizer from the context (see core.Localizer.LocalizationMiddleware)
+// with APIVersionMissingMessageID and APIVersionUnsupportedMessageID translations; English message will be used
+// if localizer or translation is not available. Parsed version can be obtained via GetAPIVersion.
+//
+// Usage:
+//
+//	webhooks := engine.Router().Group("/webhook", middleware.RequireAPIVersion("X-Api-Version", []string{"1", "2"}))
+func RequireAPIVersion(header string, supported []string, opts ...Option) gin.HandlerFunc {
+	o := newOptions(opts)
+	versions := make(map[string]struct{}, len(supported))
+	for _, version := range supported {
+		versions[version] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		version := strings.TrimSpace(c.GetHeader(header))
+		if version == "" {
+			c.AbortWithStatusJSON(errorutil.BadRequest(o.localizeMessage(c, APIVersionMissingMessageID)))
+			return
+		}
+
+		if _, ok := versions[version]; !ok {
+			c.AbortWithStatusJSON(errorutil.BadRequest(o.localizeMessage(c, APIVersionUnsupportedMessageID)))
+			return
+		}
+
+		c.Set(APIVersionContextKey, version)
+	}
+}
+
+// GetAPIVersion returns API version which was stored in the context by the RequireAPIVersion middleware.
+func GetAPIVersion(c *gin.Context) (string, bool) {
+	version := c.GetString(APIVersionContextKey)
+	return version, version != ""
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type localizerMock map[string]string
+
+func (l localizerMock) Localize(messageID string) (string, error) {
+	if msg, ok := l[messageID]; ok {
+		return msg, nil
+	}
+	return "", errors.New("message not found")
+}
+
+func apiVersionRouter(localizer messageLocalizer) *gin.Engine {
+	g := gin.New()
+	if localizer != nil {
+		g.Use(func(c *gin.Context) {
+			c.Set(LocalizerContextKey, localizer)
+		})
+	}
+	g.Use(RequireAPIVersion("X-Api-Version", []string{"1", "2"}))
+	g.GET("/", func(c *gin.Context) {
+		version, _ := GetAPIVersion(c)
+		c.String(http.StatusOK, version)
+	})
+	return g
+}
+
+func TestRequireAPIVersion_Supported(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Api-Version", " 2 ")
+	rr := httptest.NewRecorder()
+	apiVersionRouter(nil).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "2", rr.Body.String())
+}
+
+func TestRequireAPIVersion_Unsupported(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Api-Version", "3")
+	rr := httptest.NewRecorder()
+	apiVersionRouter(localizerMock{
+		APIVersionUnsupportedMessageID: "Версия API не поддерживается",
+	}).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.JSONEq(t, `{"error":"Версия API не поддерживается"}`, rr.Body.String())
+}
+
+func TestRequireAPIVersion_Missing(t *testing.T) {
+	rr := httptest.NewRecorder()
+	apiVersionRouter(localizerMock{}).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.JSONEq(t, `{"error":"API version is not provided"}`, rr.Body.String())
+}
+
+func TestGetAPIVersion_NotSet(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	version, ok := GetAPIVersion(c)
+
+	assert.False(t, ok)
+	assert.Empty(t, version)
+}
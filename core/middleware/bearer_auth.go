@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/util/errorutil"
+)
+
+// BearerPrincipalContextKey is a key which is used to store the principal returned by the token validator.
+const BearerPrincipalContextKey = "bearer_principal"
+
+const bearerPrefix = "bearer "
+
+// BearerTokenValidator checks the token and returns the principal (user, account, etc.) which owns it.
+type BearerTokenValidator func(token string) (any, error)
+
+// BearerAuth returns middleware which reads the token from the "Authorization: Bearer <token>" header and checks it
+// using the provided validator. Principal returned by the validator can be obtained via GetBearerPrincipal.
+// Request will be aborted with 401 Unauthorized if token is missing or validator returns an error.
+// Error message will be localized using localizer from the context with BearerTokenMissingMessageID and
+// BearerTokenInvalidMessageID translations. Requests with ignored methods are passed without the check.
+//
+// Usage:
+//
+//	admin := engine.Router().Group("/admin", middleware.BearerAuth(func(token string) (any, error) {
+//		return findAdminByToken(token)
+//	}, middleware.DefaultIgnoredMethods))
+func BearerAuth(validate BearerTokenValidator, ignoredMethods []string, opts ...Option) gin.HandlerFunc {
+	o := newOptions(opts)
+
+	return func(c *gin.Context) {
+		for _, method := range ignoredMethods {
+			if c.Request.Method == method {
+				return
+			}
+		}
+
+		token := bearerToken(c.GetHeader("Authorization"))
+		if token == "" {
+			abortUnauthorized(c, o.localizeMessage(c, BearerTokenMissingMessageID))
+			return
+		}
+
+		principal, err := validate(token)
+		if err != nil {
+			abortUnauthorized(c, o.localizeMessage(c, BearerTokenInvalidMessageID))
+			return
+		}
+
+		c.Set(BearerPrincipalContextKey, principal)
+	}
+}
+
+// GetBearerPrincipal returns principal which was stored in the context by the BearerAuth middleware.
+func GetBearerPrincipal(c *gin.Context) (any, bool) {
+	return c.Get(BearerPrincipalContextKey)
+}
+
+func bearerToken(header string) string {
+	if len(header) <= len(bearerPrefix) || !strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+		return ""
+	}
+
+	return strings.TrimSpace(header[len(bearerPrefix):])
+}
+
+func abortUnauthorized(c *gin.Context, msg string) {
+	c.Header("WWW-Authenticate", "Bearer")
+	c.AbortWithStatusJSON(errorutil.Unauthorized(msg))
+}
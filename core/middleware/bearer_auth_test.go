@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func bearerAuthRouter(localizer messageLocalizer, ignoredMethods ...string) *gin.Engine {
+	g := gin.New()
+	if localizer != nil {
+		g.Use(func(c *gin.Context) {
+			c.Set(LocalizerContextKey, localizer)
+		})
+	}
+	g.Use(BearerAuth(func(token string) (any, error) {
+		if token == "valid" {
+			return "admin", nil
+		}
+		return nil, errors.New("invalid token")
+	}, ignoredMethods))
+	handler := func(c *gin.Context) {
+		principal, _ := GetBearerPrincipal(c)
+		c.String(http.StatusOK, "%v", principal)
+	}
+	g.GET("/", handler)
+	g.POST("/", handler)
+	return g
+}
+
+func serveBearerAuth(g *gin.Engine, method, header string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/", nil)
+	if header != "" {
+		req.Header.Set("Authorization", header)
+	}
+	rr := httptest.NewRecorder()
+	g.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestBearerAuth_Valid(t *testing.T) {
+	rr := serveBearerAuth(bearerAuthRouter(nil), http.MethodGet, "bearer valid")
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "admin", rr.Body.String())
+}
+
+func TestBearerAuth_Invalid(t *testing.T) {
+	rr := serveBearerAuth(bearerAuthRouter(nil), http.MethodGet, "Bearer invalid")
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Equal(t, "Bearer", rr.Header().Get("WWW-Authenticate"))
+	assert.JSONEq(t, `{"error":"Authorization token is invalid"}`, rr.Body.String())
+}
+
+func TestBearerAuth_Missing(t *testing.T) {
+	g := bearerAuthRouter(localizerMock{BearerTokenMissingMessageID: "Токен не передан"})
+
+	for _, header := range []string{"", "Basic dXNlcjpwYXNz", "Bearer "} {
+		rr := serveBearerAuth(g, http.MethodPost, header)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code, header)
+		assert.JSONEq(t, `{"error":"Токен не передан"}`, rr.Body.String(), header)
+	}
+}
+
+func TestBearerAuth_IgnoredMethods(t *testing.T) {
+	g := bearerAuthRouter(nil, DefaultIgnoredMethods...)
+
+	rr := serveBearerAuth(g, http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "<nil>", rr.Body.String())
+
+	rr = serveBearerAuth(g, http.MethodPost, "")
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/util/errorutil"
+)
+
+// ConcurrencyLimit returns middleware which limits amount of the simultaneously processed requests.
+// Request will be aborted with 429 Too Many Requests if limit is reached. If wait timeout is positive,
+// request will wait for the free slot for that time (or until request context is done) before being aborted.
+// Limit is shared between all routes which use the same middleware instance, it's not applied if limit is not positive.
+// Error message will be localized using localizer from the context with ConcurrencyLimitExceededMessageID translation.
+//
+// Usage:
+//
+//	webhooks := engine.Router().Group("/webhook", middleware.ConcurrencyLimit(100, time.Second))
+func ConcurrencyLimit(limit int, wait time.Duration, opts ...Option) gin.HandlerFunc {
+	if limit <= 0 {
+		return func(c *gin.Context) {}
+	}
+
+	o := newOptions(opts)
+	slots := make(chan struct{}, limit)
+
+	return func(c *gin.Context) {
+		if !acquireSlot(c, slots, wait) {
+			c.AbortWithStatusJSON(errorutil.Error(http.StatusTooManyRequests,
+				o.localizeMessage(c, ConcurrencyLimitExceededMessageID)))
+			return
+		}
+		defer func() { <-slots }()
+
+		c.Next()
+	}
+}
+
+// acquireSlot takes the slot from the semaphore. Returns false if there is no free slot after timeout.
+func acquireSlot(c *gin.Context, slots chan struct{}, timeout time.Duration) bool {
+	select {
+	case slots <- struct{}{}:
+		return true
+	default:
+	}
+	if timeout <= 0 {
+		return false
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-c.Request.Context().Done():
+		return false
+	}
+}
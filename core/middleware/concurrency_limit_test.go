@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func concurrencyLimitRouter(release chan struct{}, started chan struct{}, wait time.Duration) *gin.Engine {
+	g := gin.New()
+	g.Use(ConcurrencyLimit(2, wait))
+	g.GET("/", func(c *gin.Context) {
+		started <- struct{}{}
+		<-release
+		c.Status(http.StatusOK)
+	})
+	return g
+}
+
+func serveConcurrent(g *gin.Engine, n int) (wg *sync.WaitGroup, codes chan int) {
+	wg = &sync.WaitGroup{}
+	codes = make(chan int, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rr := httptest.NewRecorder()
+			g.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+			codes <- rr.Code
+		}()
+	}
+	return wg, codes
+}
+
+func TestConcurrencyLimit(t *testing.T) {
+	release, started := make(chan struct{}), make(chan struct{}, 3)
+	g := concurrencyLimitRouter(release, started, 0)
+	wg, codes := serveConcurrent(g, 2)
+	<-started
+	<-started
+
+	rr := httptest.NewRecorder()
+	g.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.JSONEq(t, `{"error":"Too many requests are being processed, try again later"}`, rr.Body.String())
+
+	close(release)
+	wg.Wait()
+	close(codes)
+	for code := range codes {
+		assert.Equal(t, http.StatusOK, code)
+	}
+
+	rr = httptest.NewRecorder()
+	g.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestConcurrencyLimit_Wait(t *testing.T) {
+	release, started := make(chan struct{}), make(chan struct{}, 3)
+	g := concurrencyLimitRouter(release, started, time.Second)
+	wg, _ := serveConcurrent(g, 2)
+	<-started
+	<-started
+
+	waiting, codes := serveConcurrent(g, 1)
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	waiting.Wait()
+	assert.Equal(t, http.StatusOK, <-codes)
+
+	release = make(chan struct{})
+	defer close(release)
+	g = concurrencyLimitRouter(release, make(chan struct{}, 3), 20*time.Millisecond)
+	_, _ = serveConcurrent(g, 2)
+	require.Eventually(t, func() bool {
+		rr := httptest.NewRecorder()
+		g.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+		return rr.Code == http.StatusTooManyRequests
+	}, time.Second, time.Millisecond)
+}
+
+func TestConcurrencyLimit_Localized(t *testing.T) {
+	g := gin.New()
+	g.Use(func(c *gin.Context) {
+		c.Set(LocalizerContextKey, localizerMock{ConcurrencyLimitExceededMessageID: "Слишком много запросов"})
+	})
+	g.Use(ConcurrencyLimit(1, 0))
+	g.GET("/", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	g.GET("/nested", func(c *gin.Context) {
+		rr := httptest.NewRecorder()
+		g.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+		c.String(rr.Code, rr.Body.String())
+	})
+
+	rr := httptest.NewRecorder()
+	g.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/nested", nil))
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.JSONEq(t, `{"error":"Слишком много запросов"}`, rr.Body.String())
+}
+
+func TestConcurrencyLimit_Disabled(t *testing.T) {
+	g := gin.New()
+	g.Use(ConcurrencyLimit(0, 0))
+	g.GET("/", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	rr := httptest.NewRecorder()
+	g.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/util/errorutil"
+)
+
+// RequireContentType returns middleware which checks that Content-Type of the request is one of the allowed
+// media types. Parameters (e.g. charset) are ignored, comparison is case-insensitive. Request will be aborted with
+// 415 Unsupported Media Type otherwise. Missing Content-Type is allowed for GET and HEAD requests and for the requests
+// without body (e.g. DELETE or OPTIONS).
+// Error message will be localized using localizer from the context with ContentTypeUnsupportedMessageID translation.
+//
+// Usage:
+//
+//	webhooks := engine.Router().Group("/webhook", middleware.RequireContentType([]string{binding.MIMEJSON}))
+func RequireContentType(types []string, opts ...Option) gin.HandlerFunc {
+	o := newOptions(opts)
+	allowed := make(map[string]struct{}, len(types))
+	for _, contentType := range types {
+		allowed[strings.ToLower(contentType)] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		header := c.GetHeader("Content-Type")
+		if header == "" && (c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead ||
+			!hasBody(c.Request)) {
+			return
+		}
+
+		mediaType, _, err := mime.ParseMediaType(header)
+		if err == nil {
+			if _, ok := allowed[mediaType]; ok {
+				return
+			}
+		}
+
+		c.AbortWithStatusJSON(errorutil.Error(http.StatusUnsupportedMediaType,
+			o.localizeMessage(c, ContentTypeUnsupportedMessageID)))
+	}
+}
+
+// hasBody returns true if request has body or its size is unknown.
+func hasBody(r *http.Request) bool {
+	return r.ContentLength != 0 || len(r.TransferEncoding) > 0
+}
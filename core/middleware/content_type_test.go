@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func contentTypeRouter(localizer messageLocalizer) *gin.Engine {
+	g := gin.New()
+	if localizer != nil {
+		g.Use(func(c *gin.Context) {
+			c.Set(LocalizerContextKey, localizer)
+		})
+	}
+	g.Use(RequireContentType([]string{"application/json"}))
+	handler := func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	}
+	g.GET("/", handler)
+	g.POST("/", handler)
+	g.DELETE("/", handler)
+	g.OPTIONS("/", handler)
+	return g
+}
+
+func serveContentType(g *gin.Engine, method, contentType string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/", strings.NewReader(`{}`))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rr := httptest.NewRecorder()
+	g.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestRequireContentType_Allowed(t *testing.T) {
+	g := contentTypeRouter(nil)
+
+	assert.Equal(t, http.StatusOK, serveContentType(g, http.MethodPost, "application/json").Code)
+	assert.Equal(t, http.StatusOK, serveContentType(g, http.MethodPost, "Application/JSON; charset=utf-8").Code)
+}
+
+func TestRequireContentType_Disallowed(t *testing.T) {
+	rr := serveContentType(contentTypeRouter(nil), http.MethodPost, "application/x-www-form-urlencoded")
+	assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+	assert.JSONEq(t, `{"error":"Content type is not supported"}`, rr.Body.String())
+
+	rr = serveContentType(contentTypeRouter(localizerMock{
+		ContentTypeUnsupportedMessageID: "Тип содержимого не поддерживается",
+	}), http.MethodPost, "text/plain")
+	assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+	assert.JSONEq(t, `{"error":"Тип содержимого не поддерживается"}`, rr.Body.String())
+
+	rr = serveContentType(contentTypeRouter(nil), http.MethodPost, "invalid;;")
+	assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+}
+
+func TestRequireContentType_Missing(t *testing.T) {
+	g := contentTypeRouter(nil)
+
+	assert.Equal(t, http.StatusOK, serveContentType(g, http.MethodGet, "").Code)
+	assert.Equal(t, http.StatusUnsupportedMediaType, serveContentType(g, http.MethodPost, "").Code)
+	assert.Equal(t, http.StatusUnsupportedMediaType, serveContentType(g, http.MethodGet, "text/plain").Code)
+}
+
+func TestRequireContentType_NoBody(t *testing.T) {
+	g := contentTypeRouter(nil)
+
+	for _, method := range []string{http.MethodPost, http.MethodDelete, http.MethodOptions} {
+		rr := httptest.NewRecorder()
+		g.ServeHTTP(rr, httptest.NewRequest(method, "/", nil))
+		assert.Equal(t, http.StatusOK, rr.Code, method)
+	}
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, serveContentType(g, http.MethodDelete, "").Code)
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.TransferEncoding = []string{"chunked"}
+	rr := httptest.NewRecorder()
+	g.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+}
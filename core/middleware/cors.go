@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultCORSMethods is a list of methods which will be allowed if CORSConfig.AllowMethods is empty.
+var DefaultCORSMethods = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE"}
+
+// DefaultCORSHeaders is a list of headers which will be allowed if CORSConfig.AllowHeaders is empty.
+// CSRF token headers are included in order to work with VerifyCSRFMiddleware.
+var DefaultCORSHeaders = []string{"Origin", "Content-Type", "Accept", "X-CSRF-Token", "X-XSRF-Token"}
+
+// CORSConfig contains configuration for the CORS middleware.
+type CORSConfig struct {
+	// AllowOrigins is a list of allowed origins. Use "*" to allow any origin or
+	// a pattern with single wildcard like "https://*.example.com" to allow subdomains.
+	AllowOrigins []string
+	// AllowMethods is a list of allowed methods. DefaultCORSMethods will be used if it's empty.
+	AllowMethods []string
+	// AllowHeaders is a list of allowed request headers. DefaultCORSHeaders will be used if it's empty.
+	AllowHeaders []string
+	// ExposeHeaders is a list of response headers which will be available to the client.
+	ExposeHeaders []string
+	// AllowCredentials allows requests with credentials (cookies, authorization headers).
+	// It cannot be used together with "*" in AllowOrigins, allowed origins must be listed explicitly.
+	AllowCredentials bool
+	// MaxAge in seconds tells how long the preflight response can be cached. Zero means no header.
+	MaxAge int
+}
+
+// CORS returns middleware which handles cross-origin requests using provided configuration.
+// Preflight requests (OPTIONS with Access-Control-Request-Method header) are answered with
+// 204 No Content and aborted. OPTIONS is present in DefaultIgnoredMethods, so the middleware
+// can be safely used together with VerifyCSRFMiddleware(DefaultIgnoredMethods).
+// The function panics if AllowCredentials is enabled and AllowOrigins contains "*" because it would allow
+// credentialed requests from any website.
+//
+// Usage:
+//
+//	engine.Router().Use(middleware.CORS(middleware.CORSConfig{
+//		AllowOrigins:     []string{"https://admin.example.com", "https://*.example.com"},
+//		AllowCredentials: true,
+//		MaxAge:           600,
+//	}))
+func CORS(cfg CORSConfig) gin.HandlerFunc {
+	anyOrigin := cfg.allowsAnyOrigin()
+	if anyOrigin && cfg.AllowCredentials {
+		panic(`CORS AllowCredentials cannot be used with "*" in AllowOrigins`)
+	}
+
+	methods := cfg.AllowMethods
+	if len(methods) == 0 {
+		methods = DefaultCORSMethods
+	}
+
+	headers := cfg.AllowHeaders
+	if len(headers) == 0 {
+		headers = DefaultCORSHeaders
+	}
+
+	allowMethods := strings.Join(methods, ", ")
+	allowHeaders := strings.Join(headers, ", ")
+	exposeHeaders := strings.Join(cfg.ExposeHeaders, ", ")
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Origin")
+		if !cfg.originAllowed(origin) {
+			if isPreflight(c.Request) {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+
+			c.Next()
+			return
+		}
+
+		if anyOrigin {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Header("Access-Control-Allow-Origin", origin)
+		}
+
+		if cfg.AllowCredentials {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !isPreflight(c.Request) {
+			if exposeHeaders != "" {
+				c.Header("Access-Control-Expose-Headers", exposeHeaders)
+			}
+
+			c.Next()
+			return
+		}
+
+		c.Header("Access-Control-Allow-Methods", allowMethods)
+		c.Header("Access-Control-Allow-Headers", allowHeaders)
+
+		if cfg.MaxAge > 0 {
+			c.Header("Access-Control-Max-Age", strconv.Itoa(cfg.MaxAge))
+		}
+
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+func (c CORSConfig) allowsAnyOrigin() bool {
+	for _, item := range c.AllowOrigins {
+		if item == "*" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c CORSConfig) originAllowed(origin string) bool {
+	for _, item := range c.AllowOrigins {
+		if item == "*" || item == origin {
+			return true
+		}
+
+		if prefix, suffix, found := strings.Cut(item, "*"); found &&
+			len(origin) > len(prefix)+len(suffix) &&
+			strings.HasPrefix(origin, prefix) &&
+			strings.HasSuffix(origin, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func corsRouter(cfg CORSConfig) *gin.Engine {
+	g := gin.New()
+	g.Use(CORS(cfg))
+	g.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	return g
+}
+
+func TestCORS_Preflight(t *testing.T) {
+	g := corsRouter(CORSConfig{
+		AllowOrigins:     []string{"https://admin.example.com"},
+		AllowMethods:     []string{"GET", "POST"},
+		AllowHeaders:     []string{"Content-Type"},
+		AllowCredentials: true,
+		MaxAge:           600,
+	})
+
+	req := httptest.NewRequest(http.MethodOptions, "/", nil)
+	req.Header.Set("Origin", "https://admin.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	rr := httptest.NewRecorder()
+	g.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, "https://admin.example.com", rr.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET, POST", rr.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Content-Type", rr.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "true", rr.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "600", rr.Header().Get("Access-Control-Max-Age"))
+	assert.Equal(t, "Origin", rr.Header().Get("Vary"))
+	assert.Empty(t, rr.Body.String())
+}
+
+func TestCORS_Preflight_Forbidden(t *testing.T) {
+	g := corsRouter(CORSConfig{AllowOrigins: []string{"https://admin.example.com"}})
+
+	req := httptest.NewRequest(http.MethodOptions, "/", nil)
+	req.Header.Set("Origin", "https://evil.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	rr := httptest.NewRecorder()
+	g.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORS_Get(t *testing.T) {
+	g := corsRouter(CORSConfig{
+		AllowOrigins:  []string{"https://*.example.com"},
+		ExposeHeaders: []string{"X-Total-Count"},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Origin", "https://admin.example.com")
+	rr := httptest.NewRecorder()
+	g.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "ok", rr.Body.String())
+	assert.Equal(t, "https://admin.example.com", rr.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "X-Total-Count", rr.Header().Get("Access-Control-Expose-Headers"))
+	assert.Empty(t, rr.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Empty(t, rr.Header().Get("Access-Control-Allow-Methods"))
+}
+
+func TestCORS_Get_AnyOrigin(t *testing.T) {
+	g := corsRouter(CORSConfig{AllowOrigins: []string{"*"}})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Origin", "https://other.com")
+	rr := httptest.NewRecorder()
+	g.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "*", rr.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORS_AnyOriginWithCredentials(t *testing.T) {
+	assert.Panics(t, func() {
+		CORS(CORSConfig{AllowOrigins: []string{"https://admin.example.com", "*"}, AllowCredentials: true})
+	})
+	assert.NotPanics(t, func() {
+		CORS(CORSConfig{AllowOrigins: []string{"https://*.example.com"}, AllowCredentials: true})
+	})
+}
+
+func TestCORS_Get_NotAllowedOrigin(t *testing.T) {
+	g := corsRouter(CORSConfig{AllowOrigins: []string{"https://*.example.com"}})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Origin", "https://example.com.evil.com")
+	rr := httptest.NewRecorder()
+	g.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORS_NoOrigin(t *testing.T) {
+	g := corsRouter(CORSConfig{AllowOrigins: []string{"*"}})
+
+	rr := httptest.NewRecorder()
+	g.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, rr.Header().Get("Vary"))
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/securecookie"
 	"github.com/gorilla/sessions"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/util"
 )
 
 // CSRFErrorReason is a error reason type.
@@ -56,8 +58,7 @@ var DefaultCSRFTokenGetter = func(c *gin.Context) string {
 	} else if t := r.Header.Get("X-XSRF-Token"); len(t) > 0 {
 		return t
 	} else if c.Request.Body != nil {
-		data, _ := io.ReadAll(c.Request.Body)
-		c.Request.Body = io.NopCloser(bytes.NewReader(data))
+		data, _ := util.RawBody(c)
 		t := r.FormValue("csrf_token")
 		c.Request.Body = io.NopCloser(bytes.NewReader(data))
 
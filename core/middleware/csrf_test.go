@@ -6,12 +6,16 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/sessions"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/util"
 )
 
 type CSRFTest struct {
@@ -95,6 +99,21 @@ func TestCSRF_DefaultCSRFTokenGetter_Form(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestCSRF_DefaultCSRFTokenGetter_CachedBody(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("csrf_token=token"))
+	c.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	data, err := util.RawBody(c)
+	require.NoError(t, err)
+	c.Request.Body = io.NopCloser(bytes.NewReader(nil))
+
+	assert.Equal(t, "token", DefaultCSRFTokenGetter(c))
+	restored, err := io.ReadAll(c.Request.Body)
+	require.NoError(t, err)
+	assert.Equal(t, data, restored)
+}
+
 func TestCSRF_NewCSRF_NilStore(t *testing.T) {
 	defer func() {
 		assert.NotNil(t, recover())
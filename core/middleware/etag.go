@@ -0,0 +1,184 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultETagMaxBodySize is the default maximum size of the response body which will be buffered by the ETag
+// middleware. Responses larger than that will be sent as is without ETag.
+const DefaultETagMaxBodySize = 1 << 20
+
+// WithETagMaxBodySize sets the maximum size of the response body which will be buffered by the ETag middleware.
+// DefaultETagMaxBodySize is used if size is not positive.
+func WithETagMaxBodySize(size int) Option {
+	return func(o *options) {
+		if size > 0 {
+			o.etagMaxBodySize = size
+		}
+	}
+}
+
+// ETag returns middleware which computes weak ETag for successful GET and HEAD responses and
+// responds with 304 Not Modified if it matches the If-None-Match request header.
+// Response is buffered in order to compute its hash. Only DefaultETagMaxBodySize bytes (can be changed
+// with WithETagMaxBodySize) will be buffered, bigger responses are streamed to the client without ETag.
+// Flushed responses are not buffered too.
+func ETag(opts ...Option) gin.HandlerFunc {
+	o := newOptions(opts)
+	limit := o.etagMaxBodySize
+	if limit <= 0 {
+		limit = DefaultETagMaxBodySize
+	}
+
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+			return
+		}
+
+		w := &etagWriter{
+			ResponseWriter: c.Writer,
+			limit:          limit,
+			status:         http.StatusOK,
+		}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+		w.finish(c.Request)
+	}
+}
+
+// etagWriter buffers the response until it exceeds the limit.
+type etagWriter struct {
+	gin.ResponseWriter
+	buf       bytes.Buffer
+	limit     int
+	status    int
+	streaming bool
+}
+
+func (w *etagWriter) WriteHeader(code int) {
+	if w.streaming {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+
+	if code > 0 {
+		w.status = code
+	}
+}
+
+func (w *etagWriter) WriteHeaderNow() {
+	if w.streaming {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *etagWriter) Write(data []byte) (int, error) {
+	if !w.streaming && w.buf.Len()+len(data) > w.limit {
+		if err := w.stream(); err != nil {
+			return 0, err
+		}
+	}
+
+	if w.streaming {
+		return w.ResponseWriter.Write(data)
+	}
+
+	return w.buf.Write(data)
+}
+
+func (w *etagWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *etagWriter) Status() int {
+	if w.streaming {
+		return w.ResponseWriter.Status()
+	}
+
+	return w.status
+}
+
+func (w *etagWriter) Size() int {
+	if w.streaming {
+		return w.ResponseWriter.Size()
+	}
+
+	if w.buf.Len() == 0 {
+		return -1
+	}
+
+	return w.buf.Len()
+}
+
+func (w *etagWriter) Written() bool {
+	return w.streaming || w.buf.Len() > 0
+}
+
+func (w *etagWriter) Flush() {
+	_ = w.stream()
+	w.ResponseWriter.Flush()
+}
+
+// stream sends buffered data to the client and switches writer to the pass-through mode.
+func (w *etagWriter) stream() error {
+	if w.streaming {
+		return nil
+	}
+
+	w.streaming = true
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.buf.Len() == 0 {
+		return nil
+	}
+
+	_, err := w.ResponseWriter.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+func (w *etagWriter) finish(r *http.Request) {
+	if w.streaming {
+		return
+	}
+
+	if w.status == http.StatusOK && w.buf.Len() > 0 && w.Header().Get("ETag") == "" {
+		sum := sha256.Sum256(w.buf.Bytes())
+		etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+		w.Header().Set("ETag", etag)
+
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			w.Header().Del("Content-Length")
+			w.ResponseWriter.WriteHeader(http.StatusNotModified)
+			w.ResponseWriter.WriteHeaderNow()
+			return
+		}
+	}
+
+	_ = w.stream()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// etagMatches performs weak comparison of the If-None-Match header value and the ETag.
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, item := range strings.Split(header, ",") {
+		item = strings.TrimSpace(item)
+		if item == "*" || strings.TrimPrefix(item, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
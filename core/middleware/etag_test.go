@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func etagRouter(opts ...Option) *gin.Engine {
+	g := gin.New()
+	g.Use(ETag(opts...))
+	g.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, "settings page")
+	})
+	g.GET("/large", func(c *gin.Context) {
+		c.String(http.StatusOK, strings.Repeat("a", DefaultETagMaxBodySize+1))
+	})
+	g.GET("/error", func(c *gin.Context) {
+		c.String(http.StatusBadRequest, "error")
+	})
+	return g
+}
+
+func TestETag(t *testing.T) {
+	g := etagRouter()
+
+	rr := httptest.NewRecorder()
+	g.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "settings page", rr.Body.String())
+	etag := rr.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.True(t, strings.HasPrefix(etag, `W/"`))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("If-None-Match", etag)
+	rr = httptest.NewRecorder()
+	g.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Empty(t, rr.Body.String())
+	assert.Equal(t, etag, rr.Header().Get("ETag"))
+}
+
+func TestETag_Mismatch(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("If-None-Match", `W/"other"`)
+	rr := httptest.NewRecorder()
+	etagRouter().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "settings page", rr.Body.String())
+}
+
+func TestETag_LargeBody(t *testing.T) {
+	rr := httptest.NewRecorder()
+	etagRouter().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/large", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, DefaultETagMaxBodySize+1, rr.Body.Len())
+	assert.Empty(t, rr.Header().Get("ETag"))
+}
+
+func TestETag_MaxBodySize(t *testing.T) {
+	rr := httptest.NewRecorder()
+	etagRouter(WithETagMaxBodySize(4)).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "settings page", rr.Body.String())
+	assert.Empty(t, rr.Header().Get("ETag"))
+
+	rr = httptest.NewRecorder()
+	etagRouter(WithETagMaxBodySize(0)).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.NotEmpty(t, rr.Header().Get("ETag"))
+}
+
+func TestETag_NotOK(t *testing.T) {
+	rr := httptest.NewRecorder()
+	etagRouter().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/error", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "error", rr.Body.String())
+	assert.Empty(t, rr.Header().Get("ETag"))
+}
+
+func Test_etagMatches(t *testing.T) {
+	assert.True(t, etagMatches(`W/"abc"`, `W/"abc"`))
+	assert.True(t, etagMatches(`"abc"`, `W/"abc"`))
+	assert.True(t, etagMatches(`"def", W/"abc"`, `W/"abc"`))
+	assert.True(t, etagMatches("*", `W/"abc"`))
+	assert.False(t, etagMatches("", `W/"abc"`))
+	assert.False(t, etagMatches(`W/"def"`, `W/"abc"`))
+}
@@ -0,0 +1,267 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/util"
+)
+
+// jsonSchemaURL is used as a location of the schema passed to the ValidateJSONSchema.
+const jsonSchemaURL = "schema.json"
+
+// schemaKeywordJSON is used as a keyword of the error if request body is not a valid JSON.
+const schemaKeywordJSON = "json"
+
+// schemaMessagePrinter is used to build English messages for the schema errors.
+var schemaMessagePrinter = message.NewPrinter(language.English)
+
+// schemaError describes single schema violation.
+type schemaError struct {
+	// field is a path to the invalid value in dot notation, e.g. "customer.phones[0].number".
+	field string
+	// keyword which validation has failed, e.g. "required" or "maxLength".
+	keyword string
+	// param contains keyword value, e.g. "255" for maxLength or "string" for type.
+	param   string
+	message string
+}
+
+// Error returns error message with path.
+func (e schemaError) Error() string {
+	if e.field == "" {
+		return e.message
+	}
+	return e.field + ": " + e.message
+}
+
+// ValidateJSONSchema returns middleware which validates request body against the JSON schema. Schema is compiled
+// once, the function panics if schema is invalid. Draft 7 is used if schema doesn't contain "$schema" keyword.
+// Remote references are not loaded. Body is read via util.RawBody, so handler can read or bind it again.
+//
+// Request will be aborted with 400 Bad Request and the list of util.FieldError in the "error" field if body
+// doesn't match the schema. Message ID for every error consists of util.ValidationMessageIDPrefix and the schema
+// keyword, e.g. "validation_required" or "validation_maxLength". Translations can use "Field" and "Param" template
+// parameters. English message is used if localizer or translation is not available. Request is aborted with
+// the RequestBodyUnreadableMessageID message if body cannot be read.
+//
+// Usage:
+//
+//	engine.Router().POST("/webhook", middleware.ValidateJSONSchema(webhookSchema), webhookHandler)
+func ValidateJSONSchema(schema []byte, opts ...Option) gin.HandlerFunc {
+	compiled := mustCompileJSONSchema(schema)
+	o := newOptions(opts)
+
+	return func(c *gin.Context) {
+		data, err := util.RawBody(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": []util.FieldError{{
+				Message: o.localizeMessage(c, RequestBodyUnreadableMessageID),
+			}}})
+			return
+		}
+
+		validationErrs := validateJSONSchema(compiled, data)
+		if len(validationErrs) == 0 {
+			return
+		}
+
+		var loc util.TemplateLocalizer
+		if item, ok := c.Get(o.localizerKey); ok {
+			loc, _ = item.(util.TemplateLocalizer)
+		}
+
+		result := make([]util.FieldError, 0, len(validationErrs))
+		for _, validationErr := range validationErrs {
+			result = append(result, util.FieldError{
+				Field:   validationErr.field,
+				Message: localizeSchemaError(validationErr, loc),
+			})
+		}
+
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": result})
+	}
+}
+
+// mustCompileJSONSchema compiles JSON schema and panics if it's invalid.
+func mustCompileJSONSchema(schema []byte) *jsonschema.Schema {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schema))
+	if err != nil {
+		panic(fmt.Errorf("invalid JSON schema: %w", err))
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.DefaultDraft(jsonschema.Draft7)
+	if err := compiler.AddResource(jsonSchemaURL, doc); err != nil {
+		panic(fmt.Errorf("invalid JSON schema: %w", err))
+	}
+
+	compiled, err := compiler.Compile(jsonSchemaURL)
+	if err != nil {
+		panic(fmt.Errorf("invalid JSON schema: %w", err))
+	}
+
+	return compiled
+}
+
+// validateJSONSchema validates JSON document against the schema. Empty result means that document is valid.
+// Errors are sorted by path.
+func validateJSONSchema(schema *jsonschema.Schema, data []byte) []schemaError {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return []schemaError{{keyword: schemaKeywordJSON, message: "invalid JSON: " + err.Error()}}
+	}
+
+	err = schema.Validate(doc)
+	if err == nil {
+		return nil
+	}
+
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return []schemaError{{message: err.Error()}}
+	}
+
+	var result []schemaError
+	collectSchemaErrors(validationErr, &result)
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].field < result[j].field
+	})
+	return result
+}
+
+// collectSchemaErrors converts leaf validation errors to the schemaError list.
+func collectSchemaErrors(validationErr *jsonschema.ValidationError, result *[]schemaError) {
+	if len(validationErr.Causes) > 0 {
+		for _, cause := range validationErr.Causes {
+			collectSchemaErrors(cause, result)
+		}
+		return
+	}
+
+	field := schemaErrorField(validationErr.InstanceLocation)
+	switch k := validationErr.ErrorKind.(type) {
+	case *kind.Required:
+		for _, name := range k.Missing {
+			*result = append(*result, schemaError{
+				field: joinSchemaErrorField(field, name), keyword: "required", param: name, message: "is required",
+			})
+		}
+	case *kind.AdditionalProperties:
+		for _, name := range k.Properties {
+			*result = append(*result, schemaError{
+				field:   joinSchemaErrorField(field, name),
+				keyword: "additionalProperties",
+				param:   name,
+				message: "is not allowed",
+			})
+		}
+	default:
+		var keyword string
+		if path := k.KeywordPath(); len(path) > 0 {
+			keyword = path[len(path)-1]
+		}
+		*result = append(*result, schemaError{
+			field:   field,
+			keyword: keyword,
+			param:   schemaErrorParam(k),
+			message: k.LocalizedString(schemaMessagePrinter),
+		})
+	}
+}
+
+// schemaErrorParam returns keyword value of the most common errors.
+func schemaErrorParam(errorKind jsonschema.ErrorKind) string {
+	switch k := errorKind.(type) {
+	case *kind.Type:
+		return strings.Join(k.Want, ",")
+	case *kind.Pattern:
+		return k.Want
+	case *kind.Format:
+		return k.Want
+	case *kind.MinLength:
+		return strconv.Itoa(k.Want)
+	case *kind.MaxLength:
+		return strconv.Itoa(k.Want)
+	case *kind.MinItems:
+		return strconv.Itoa(k.Want)
+	case *kind.MaxItems:
+		return strconv.Itoa(k.Want)
+	case *kind.MinProperties:
+		return strconv.Itoa(k.Want)
+	case *kind.MaxProperties:
+		return strconv.Itoa(k.Want)
+	case *kind.Minimum:
+		return ratString(k.Want)
+	case *kind.Maximum:
+		return ratString(k.Want)
+	case *kind.ExclusiveMinimum:
+		return ratString(k.Want)
+	case *kind.ExclusiveMaximum:
+		return ratString(k.Want)
+	case *kind.MultipleOf:
+		return ratString(k.Want)
+	}
+
+	return ""
+}
+
+// schemaErrorField converts JSON pointer tokens to the dot notation, e.g. "customer.phones[0].number".
+func schemaErrorField(location []string) string {
+	var field string
+	for _, token := range location {
+		if _, err := strconv.Atoi(token); err == nil {
+			field += "[" + token + "]"
+			continue
+		}
+		field = joinSchemaErrorField(field, token)
+	}
+	return field
+}
+
+func joinSchemaErrorField(field, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
+
+func ratString(r *big.Rat) string {
+	if r == nil {
+		return ""
+	}
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	f, _ := r.Float64()
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func localizeSchemaError(validationErr schemaError, loc util.TemplateLocalizer) string {
+	if loc == nil {
+		return validationErr.Error()
+	}
+
+	msg, err := loc.LocalizeTemplateMessage(util.ValidationMessageIDPrefix+validationErr.keyword,
+		map[string]interface{}{
+			"Field": validationErr.field,
+			"Param": validationErr.param,
+		})
+	if err != nil {
+		return validationErr.Error()
+	}
+
+	return msg
+}
@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+const testJSONSchema = `{
+	"type": "object",
+	"required": ["name", "phones"],
+	"additionalProperties": false,
+	"properties": {
+		"name": {"type": "string", "minLength": 1, "maxLength": 10},
+		"age": {"type": "integer", "minimum": 0},
+		"phones": {"type": "array", "minItems": 1, "items": {"type": "string", "pattern": "^\\+[0-9]+$"}}
+	}
+}`
+
+type templateLocalizerMock map[string]string
+
+func (l templateLocalizerMock) LocalizeTemplateMessage(id string, data map[string]interface{}) (string, error) {
+	tpl, ok := l[id]
+	if !ok {
+		return "", errors.New("message not found")
+	}
+	var buf bytes.Buffer
+	if err := template.Must(template.New(id).Parse(tpl)).Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func jsonSchemaRouter(localizer interface{}, key ...string) *gin.Engine {
+	contextKey := LocalizerContextKey
+	var opts []Option
+	if len(key) > 0 {
+		contextKey = key[0]
+		opts = append(opts, WithLocalizerKey(key[0]))
+	}
+
+	g := gin.New()
+	if localizer != nil {
+		g.Use(func(c *gin.Context) {
+			c.Set(contextKey, localizer)
+		})
+	}
+	g.POST("/", ValidateJSONSchema([]byte(testJSONSchema), opts...), func(c *gin.Context) {
+		data, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(data))
+	})
+	return g
+}
+
+func serveJSONSchema(g *gin.Engine, body string) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	g.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+	return rr
+}
+
+func TestValidateJSONSchema_Valid(t *testing.T) {
+	body := `{"name":"John","age":30,"phones":["+79990000000"]}`
+	rr := serveJSONSchema(jsonSchemaRouter(nil), body)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, body, rr.Body.String())
+}
+
+func TestValidateJSONSchema_Invalid(t *testing.T) {
+	rr := serveJSONSchema(jsonSchemaRouter(nil), `{"name":"","age":1.5,"phones":["123"],"extra":true}`)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.JSONEq(t, `{"error":[
+		{"field":"age","message":"age: got number, want integer"},
+		{"field":"extra","message":"extra: is not allowed"},
+		{"field":"name","message":"name: minLength: got 0, want 1"},
+		{"field":"phones[0]","message":"phones[0]: '123' does not match pattern '^\\\\+[0-9]+$'"}
+	]}`, rr.Body.String())
+}
+
+func TestValidateJSONSchema_Localized(t *testing.T) {
+	g := jsonSchemaRouter(templateLocalizerMock{
+		"validation_required":  "Поле {{.Field}} обязательно",
+		"validation_maxLength": "Поле {{.Field}} должно быть не длиннее {{.Param}} символов",
+		"validation_minimum":   "Поле {{.Field}} должно быть не меньше {{.Param}}",
+	})
+	rr := serveJSONSchema(g, `{"name":"Very long name","age":-1}`)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.JSONEq(t, `{"error":[
+		{"field":"age","message":"Поле age должно быть не меньше 0"},
+		{"field":"name","message":"Поле name должно быть не длиннее 10 символов"},
+		{"field":"phones","message":"Поле phones обязательно"}
+	]}`, rr.Body.String())
+}
+
+func TestValidateJSONSchema_LocalizerKey(t *testing.T) {
+	g := jsonSchemaRouter(templateLocalizerMock{"validation_required": "Поле {{.Field}} обязательно"}, "custom")
+	rr := serveJSONSchema(g, `{"name":"John"}`)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.JSONEq(t, `{"error":[{"field":"phones","message":"Поле phones обязательно"}]}`, rr.Body.String())
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset by peer: 10.0.0.1")
+}
+
+func TestValidateJSONSchema_UnreadableBody(t *testing.T) {
+	rr := httptest.NewRecorder()
+	jsonSchemaRouter(nil).ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", failingReader{}))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.JSONEq(t, `{"error":[{"message":"Request body cannot be read"}]}`, rr.Body.String())
+}
+
+func TestValidateJSONSchema_MalformedBody(t *testing.T) {
+	rr := serveJSONSchema(jsonSchemaRouter(nil), `{"name":`)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "invalid JSON")
+}
+
+func TestValidateJSONSchema_Composition(t *testing.T) {
+	schema := `{
+		"definitions": {
+			"text": {"type": "object", "required": ["text"], "properties": {"text": {"type": "string"}}},
+			"file": {"type": "object", "required": ["url"], "properties": {"url": {"type": "string", "format": "uri"}}}
+		},
+		"type": "object",
+		"required": ["message"],
+		"properties": {
+			"message": {"oneOf": [{"$ref": "#/definitions/text"}, {"$ref": "#/definitions/file"}]}
+		}
+	}`
+	g := gin.New()
+	g.POST("/", ValidateJSONSchema([]byte(schema)), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	assert.Equal(t, http.StatusOK, serveJSONSchema(g, `{"message":{"text":"hello"}}`).Code)
+	assert.Equal(t, http.StatusOK, serveJSONSchema(g, `{"message":{"url":"https://example.com/a.jpg"}}`).Code)
+
+	rr := serveJSONSchema(g, `{"message":{"id":1}}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.JSONEq(t, `{"error":[
+		{"field":"message.text","message":"message.text: is required"},
+		{"field":"message.url","message":"message.url: is required"}
+	]}`, rr.Body.String())
+}
+
+func TestValidateJSONSchema_InvalidSchema(t *testing.T) {
+	assert.Panics(t, func() {
+		ValidateJSONSchema([]byte(`{"$ref": "#/definitions/user"}`))
+	})
+	assert.Panics(t, func() {
+		ValidateJSONSchema([]byte(`{"type": "object",`))
+	})
+	assert.Panics(t, func() {
+		ValidateJSONSchema([]byte(`{"type": "unknown"}`))
+	})
+}
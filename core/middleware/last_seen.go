@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LastSeenStore stores time of the last request for every connection.
+type LastSeenStore interface {
+	// Touch stores the request time for the connection.
+	Touch(conn string, at time.Time)
+	// LastSeen returns time of the last request for the connection.
+	LastSeen(conn string) (time.Time, bool)
+}
+
+// MemoryLastSeenStore is an in-memory LastSeenStore. It's safe for concurrent use.
+type MemoryLastSeenStore struct {
+	items map[string]time.Time
+	mu    sync.RWMutex
+}
+
+// NewMemoryLastSeenStore returns empty MemoryLastSeenStore.
+func NewMemoryLastSeenStore() *MemoryLastSeenStore {
+	return &MemoryLastSeenStore{items: make(map[string]time.Time)}
+}
+
+// Touch stores the request time for the connection. Older time won't replace the newer one.
+func (s *MemoryLastSeenStore) Touch(conn string, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if prev, ok := s.items[conn]; !ok || at.After(prev) {
+		s.items[conn] = at
+	}
+}
+
+// LastSeen returns time of the last request for the connection.
+func (s *MemoryLastSeenStore) LastSeen(conn string) (time.Time, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	at, ok := s.items[conn]
+	return at, ok
+}
+
+// All returns copy of the stored data. Keys are connection identifiers.
+func (s *MemoryLastSeenStore) All() map[string]time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	items := make(map[string]time.Time, len(s.items))
+	for conn, at := range s.items {
+		items[conn] = at
+	}
+	return items
+}
+
+// LastSeen returns middleware which stores request time for the connection from the context.
+// Connection models are identified by their address, other values are formatted as is.
+// Requests without connection in the context are ignored. The middleware must be placed after the middleware
+// that puts connection into the context.
+//
+// Usage:
+//
+//	lastSeen := middleware.NewMemoryLastSeenStore()
+//	webhooks := engine.Router().Group("/webhook", loadConnection(), middleware.LastSeen(lastSeen, "connection"))
+func LastSeen(store LastSeenStore, connKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if conn, ok := c.Get(connKey); ok && conn != nil {
+			store.Touch(fmt.Sprint(loggerValue(conn)), time.Now())
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/db/models"
+)
+
+func TestLastSeen(t *testing.T) {
+	store := NewMemoryLastSeenStore()
+	g := gin.New()
+	g.Use(func(c *gin.Context) {
+		if conn := c.Query("conn"); conn != "" {
+			c.Set("connection", &models.Connection{URL: conn})
+		}
+	}, LastSeen(store, "connection"))
+	g.GET("/", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	before := time.Now()
+	g.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/?conn=https://a.retailcrm.pro", nil))
+	first, ok := store.LastSeen("https://a.retailcrm.pro")
+	require.True(t, ok)
+	assert.False(t, first.Before(before))
+
+	time.Sleep(time.Millisecond)
+	g.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/?conn=https://a.retailcrm.pro", nil))
+	second, ok := store.LastSeen("https://a.retailcrm.pro")
+	require.True(t, ok)
+	assert.True(t, second.After(first))
+
+	g.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Len(t, store.All(), 1)
+	_, ok = store.LastSeen("https://b.retailcrm.pro")
+	assert.False(t, ok)
+}
+
+func TestMemoryLastSeenStore_Touch(t *testing.T) {
+	store := NewMemoryLastSeenStore()
+	now := time.Now()
+
+	store.Touch("conn", now)
+	store.Touch("conn", now.Add(-time.Minute))
+
+	at, ok := store.LastSeen("conn")
+	require.True(t, ok)
+	assert.Equal(t, now, at)
+	assert.Equal(t, map[string]time.Time{"conn": now}, store.All())
+}
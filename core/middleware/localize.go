@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// Translation IDs of the middleware error messages. English messages from defaultMessages are used
+// if localizer or translation is not available.
+const (
+	// APIVersionMissingMessageID is a translation ID for the missing API version error.
+	APIVersionMissingMessageID = "api_version_missing"
+	// APIVersionUnsupportedMessageID is a translation ID for the unsupported API version error.
+	APIVersionUnsupportedMessageID = "api_version_unsupported"
+	// BearerTokenMissingMessageID is a translation ID for the missing Bearer token error.
+	BearerTokenMissingMessageID = "bearer_token_missing"
+	// BearerTokenInvalidMessageID is a translation ID for the invalid Bearer token error.
+	BearerTokenInvalidMessageID = "bearer_token_invalid"
+	// ContentTypeUnsupportedMessageID is a translation ID for the unsupported content type error.
+	ContentTypeUnsupportedMessageID = "content_type_unsupported"
+	// ConcurrencyLimitExceededMessageID is a translation ID for the concurrency limit error.
+	ConcurrencyLimitExceededMessageID = "concurrency_limit_exceeded"
+	// RequestBodyUnreadableMessageID is a translation ID for the request body read error.
+	RequestBodyUnreadableMessageID = "request_body_unreadable"
+)
+
+// defaultMessages are used if localizer or translation is not available.
+var defaultMessages = map[string]string{
+	APIVersionMissingMessageID:        "API version is not provided",
+	APIVersionUnsupportedMessageID:    "API version is not supported",
+	BearerTokenMissingMessageID:       "Authorization token is not provided",
+	BearerTokenInvalidMessageID:       "Authorization token is invalid",
+	ContentTypeUnsupportedMessageID:   "Content type is not supported",
+	ConcurrencyLimitExceededMessageID: "Too many requests are being processed, try again later",
+	RequestBodyUnreadableMessageID:    "Request body cannot be read",
+}
+
+// LocalizerContextKey is a default key which is used to store localizer in gin.Context.
+// core.LocalizerContextKey refers to this constant, testutil.LocalizerContextKey has the same value.
+const LocalizerContextKey = "localizer"
+
+// Option configures the middleware.
+type Option func(*options)
+
+// options contains settings which are shared by the middlewares.
+type options struct {
+	localizerKey    string
+	etagMaxBodySize int
+}
+
+// WithLocalizerKey sets the context key of the localizer which is used to localize error messages.
+// It must be provided if core.Localizer uses non-default ContextKey.
+func WithLocalizerKey(key string) Option {
+	return func(o *options) {
+		if key != "" {
+			o.localizerKey = key
+		}
+	}
+}
+
+// newOptions returns options with applied opts. LocalizerContextKey is used by default.
+func newOptions(opts []Option) options {
+	o := options{localizerKey: LocalizerContextKey}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// messageLocalizer is a part of core.LocalizerInterface which is used by the middlewares.
+type messageLocalizer interface {
+	Localize(string) (string, error)
+}
+
+// localizer returns localizer from the context or nil if it's not present.
+func (o options) localizer(c *gin.Context) messageLocalizer {
+	if item, ok := c.Get(o.localizerKey); ok {
+		if localizer, ok := item.(messageLocalizer); ok {
+			return localizer
+		}
+	}
+	return nil
+}
+
+// localizeMessage localizes message using localizer from the context. Default message is returned on failure.
+func (o options) localizeMessage(c *gin.Context, messageID string) string {
+	if localizer := o.localizer(c); localizer != nil {
+		if msg, err := localizer.Localize(messageID); err == nil {
+			return msg
+		}
+	}
+
+	return defaultMessages[messageID]
+}
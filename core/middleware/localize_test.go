@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithLocalizerKey(t *testing.T) {
+	localizer := localizerMock{
+		APIVersionMissingMessageID:      "Версия API не передана",
+		BearerTokenMissingMessageID:     "Токен не передан",
+		ContentTypeUnsupportedMessageID: "Тип содержимого не поддерживается",
+	}
+	rejectAll := func(string) (any, error) {
+		return nil, errors.New("invalid token")
+	}
+
+	for name, test := range map[string]struct {
+		middleware func(opts ...Option) gin.HandlerFunc
+		expected   string
+	}{
+		"RequireAPIVersion": {
+			middleware: func(opts ...Option) gin.HandlerFunc {
+				return RequireAPIVersion("X-Api-Version", []string{"1"}, opts...)
+			},
+			expected: "Версия API не передана",
+		},
+		"BearerAuth": {
+			middleware: func(opts ...Option) gin.HandlerFunc {
+				return BearerAuth(rejectAll, nil, opts...)
+			},
+			expected: "Токен не передан",
+		},
+		"RequireContentType": {
+			middleware: func(opts ...Option) gin.HandlerFunc {
+				return RequireContentType([]string{"application/json"}, opts...)
+			},
+			expected: "Тип содержимого не поддерживается",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			serve := func(opts ...Option) string {
+				g := gin.New()
+				g.Use(func(c *gin.Context) {
+					c.Set("custom_localizer", localizer)
+				}, test.middleware(opts...))
+				g.POST("/", func(c *gin.Context) {
+					c.Status(http.StatusOK)
+				})
+
+				rr := httptest.NewRecorder()
+				g.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("text")))
+				return rr.Body.String()
+			}
+
+			assert.NotContains(t, serve(), test.expected)
+			assert.Contains(t, serve(WithLocalizerKey("custom_localizer")), test.expected)
+		})
+	}
+}
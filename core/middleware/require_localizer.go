@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/logger"
+	"github.com/retailcrm/mg-transport-core/v2/core/util/errorutil"
+)
+
+// LocalizerMissingMessage is returned with 500 by RequireLocalizer. It's not localized for obvious reasons.
+const LocalizerMissingMessage = "Internal Server Error"
+
+// RequireLocalizer returns middleware which aborts the request with 500 if the localizer is not present in the
+// context. It allows catching the misconfigured handler chain before the handler panics in the
+// core.MustGetContextLocalizer. WithLocalizerKey option must be provided if localizer uses non-default context key.
+//
+// Usage:
+//
+//	engine.Router().Group("/api", middleware.RequireLocalizer(engine.Logger()))
+func RequireLocalizer(log logger.Logger, opts ...Option) gin.HandlerFunc {
+	o := newOptions(opts)
+
+	return func(c *gin.Context) {
+		if o.localizer(c) != nil {
+			c.Next()
+			return
+		}
+
+		if log != nil {
+			log.Error("localizer is not present in the context, check the middleware order",
+				zap.String(logger.HTTPMethodAttr, c.Request.Method),
+				zap.String("path", c.Request.URL.Path),
+				zap.String("key", o.localizerKey))
+		}
+		c.AbortWithStatusJSON(errorutil.InternalServerError(LocalizerMissingMessage))
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/util/testutil"
+)
+
+func requireLocalizerRouter(localizer interface{}, opts ...Option) (*gin.Engine, testutil.BufferedLogger) {
+	log := testutil.NewBufferedLoggerSilent()
+	g := gin.New()
+	g.Use(func(c *gin.Context) {
+		if localizer != nil {
+			c.Set(LocalizerContextKey, localizer)
+		}
+	}, RequireLocalizer(log, opts...))
+	g.GET("/", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return g, log
+}
+
+func TestRequireLocalizer(t *testing.T) {
+	g, log := requireLocalizerRouter(localizerMock{})
+	rr := httptest.NewRecorder()
+	g.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, log.String())
+}
+
+func TestRequireLocalizer_Missing(t *testing.T) {
+	for name, localizer := range map[string]interface{}{
+		"absent":    nil,
+		"wrongType": "localizer",
+	} {
+		t.Run(name, func(t *testing.T) {
+			g, log := requireLocalizerRouter(localizer)
+			rr := httptest.NewRecorder()
+			g.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, http.StatusInternalServerError, rr.Code)
+			assert.JSONEq(t, `{"error":"Internal Server Error"}`, rr.Body.String())
+			assert.Contains(t, log.String(), "localizer is not present in the context")
+		})
+	}
+}
+
+func TestRequireLocalizer_CustomKey(t *testing.T) {
+	g, _ := requireLocalizerRouter(localizerMock{}, WithLocalizerKey("custom"))
+	rr := httptest.NewRecorder()
+	g.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/logger"
+)
+
+// SlowRequestLogger returns middleware which logs requests that took more time than the threshold.
+// Logger from the context (see logger.GinMiddleware and AccountLogger) is used if it is present, so the log
+// entry will contain the same connection, account and stream ID fields as the other entries of the request.
+//
+// Usage:
+//
+//	engine.Use(logger.GinMiddleware(log), middleware.SlowRequestLogger(log, time.Second))
+func SlowRequestLogger(base logger.Logger, threshold time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		elapsed := time.Since(start)
+		if elapsed < threshold {
+			return
+		}
+
+		log := base
+		if item, ok := c.Get(logger.LoggerContextKey); ok {
+			if ctxLogger, ok := item.(logger.Logger); ok && ctxLogger != nil {
+				log = ctxLogger
+			}
+		}
+		if log == nil {
+			return
+		}
+
+		log.Warn("slow request",
+			zap.String(logger.HTTPMethodAttr, c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.Duration("duration", elapsed),
+			zap.Int(logger.HTTPStatusAttr, c.Writer.Status()),
+		)
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/db/models"
+	"github.com/retailcrm/mg-transport-core/v2/core/logger"
+	"github.com/retailcrm/mg-transport-core/v2/core/util/testutil"
+)
+
+func TestSlowRequestLogger(t *testing.T) {
+	log := testutil.NewBufferedLoggerSilent()
+	g := gin.New()
+	g.Use(SlowRequestLogger(log, 50*time.Millisecond), func(c *gin.Context) {
+		c.Set("connection", &models.Connection{URL: "https://test.retailcrm.pro"})
+		c.Set("account", models.Account{Name: "@account"})
+	}, AccountLogger(log, "connection", "account"))
+	g.GET("/fast", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	g.POST("/slow", func(c *gin.Context) {
+		time.Sleep(60 * time.Millisecond)
+		c.Status(http.StatusAccepted)
+	})
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/fast", nil),
+		httptest.NewRequest(http.MethodPost, "/slow", nil),
+	} {
+		g.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	items, err := testutil.NewJSONRecordScanner(log).ScanAll()
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "WARN", items[0].LevelName)
+	assert.Equal(t, "slow request", items[0].Message)
+	assert.Equal(t, "https://test.retailcrm.pro", items[0].Connection)
+	assert.Equal(t, "@account", items[0].Account)
+	assert.Equal(t, http.MethodPost, items[0].Context[logger.HTTPMethodAttr])
+	assert.Equal(t, "/slow", items[0].Context["path"])
+	assert.Equal(t, float64(http.StatusAccepted), items[0].Context[logger.HTTPStatusAttr])
+	assert.NotEmpty(t, items[0].Context["duration"])
+}
+
+func TestSlowRequestLogger_BaseLogger(t *testing.T) {
+	log := testutil.NewBufferedLoggerSilent()
+	g := gin.New()
+	g.Use(SlowRequestLogger(log, 0))
+	g.GET("/", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	g.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	items, err := testutil.NewJSONRecordScanner(log).ScanAll()
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Empty(t, items[0].Connection)
+}
@@ -0,0 +1,165 @@
+package core
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	metrics "github.com/retailcrm/zabbix-metrics-collector"
+)
+
+// DefaultRequestLatencyBuckets are used by RequestMetricsCollector if buckets are not provided.
+var DefaultRequestLatencyBuckets = []time.Duration{
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+}
+
+// RequestMetricsCollector aggregates request count and latency of every route and sends them to Zabbix.
+// It implements metrics.Collector and should be registered with Engine.UseZabbix, its middleware should be
+// added to the router. Metrics are reset after every submission, so Zabbix receives values for the interval.
+// Following keys are sent for every route which has received at least one request since the collector creation
+// (zeros are sent for the routes without requests in the interval):
+//   - <prefix>.http.request.count["METHOD","/route/:param"] - amount of requests;
+//   - <prefix>.http.request.latency["METHOD","/route/:param","500ms"] - amount of requests which took 500ms or less;
+//   - <prefix>.http.request.latency["METHOD","/route/:param","inf"] - amount of requests slower than the largest
+//     bucket.
+//
+// Key parameters are quoted because route may contain symbols which aren't allowed in unquoted parameters.
+// Requests which didn't match any route are not collected.
+// Usage:
+//
+//	collector := core.NewRequestMetricsCollector(app.Config.GetZabbixConfig().MetricPrefix)
+//	app.UseZabbix([]metrics.Collector{collector})
+//	app.Router().Use(collector.Middleware())
+type RequestMetricsCollector struct {
+	routes  map[requestRoute]*requestRouteMetrics
+	prefix  string
+	buckets []time.Duration
+	mu      sync.Mutex
+}
+
+type requestRoute struct {
+	method string
+	path   string
+}
+
+type requestRouteMetrics struct {
+	buckets []uint64
+	count   uint64
+	slow    uint64
+}
+
+// NewRequestMetricsCollector is a RequestMetricsCollector constructor. DefaultRequestLatencyBuckets are used
+// if buckets are not provided.
+func NewRequestMetricsCollector(prefix string, buckets ...time.Duration) *RequestMetricsCollector {
+	if len(buckets) == 0 {
+		buckets = DefaultRequestLatencyBuckets
+	}
+	sorted := append([]time.Duration{}, buckets...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	return &RequestMetricsCollector{
+		routes:  make(map[requestRoute]*requestRouteMetrics),
+		prefix:  prefix,
+		buckets: sorted,
+	}
+}
+
+// Middleware returns gin middleware which measures request latency.
+func (c *RequestMetricsCollector) Middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		if path := ctx.FullPath(); path != "" {
+			c.Observe(ctx.Request.Method, path, time.Since(start))
+		}
+	}
+}
+
+// Observe records the request to the route with provided latency.
+func (c *RequestMetricsCollector) Observe(method, path string, latency time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := requestRoute{method: method, path: path}
+	route, ok := c.routes[key]
+	if !ok {
+		route = &requestRouteMetrics{buckets: make([]uint64, len(c.buckets))}
+		c.routes[key] = route
+	}
+
+	route.count++
+	if latency > c.buckets[len(c.buckets)-1] {
+		route.slow++
+		return
+	}
+	for i, bucket := range c.buckets {
+		if latency <= bucket {
+			route.buckets[i]++
+		}
+	}
+}
+
+// Metrics returns collected metrics and resets them. Routes are kept, so zeros are returned for them next time.
+func (c *RequestMetricsCollector) Metrics() []metrics.Metric {
+	c.mu.Lock()
+	routes := make(map[requestRoute]requestRouteMetrics, len(c.routes))
+	for key, route := range c.routes {
+		routes[key] = *route
+		c.routes[key] = &requestRouteMetrics{buckets: make([]uint64, len(c.buckets))}
+	}
+	c.mu.Unlock()
+
+	keys := make([]requestRoute, 0, len(routes))
+	for key := range routes {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].path == keys[j].path {
+			return keys[i].method < keys[j].method
+		}
+		return keys[i].path < keys[j].path
+	})
+
+	items := make([]metrics.Metric, 0, len(routes)*(len(c.buckets)+2))
+	for _, key := range keys {
+		route := routes[key]
+		items = append(items, metrics.Metric{
+			Name:  c.metricName("http.request.count", key.method, key.path),
+			Value: strconv.FormatUint(route.count, 10),
+		})
+		for i, bucket := range c.buckets {
+			items = append(items, metrics.Metric{
+				Name:  c.metricName("http.request.latency", key.method, key.path, bucket.String()),
+				Value: strconv.FormatUint(route.buckets[i], 10),
+			})
+		}
+		items = append(items, metrics.Metric{
+			Name:  c.metricName("http.request.latency", key.method, key.path, "inf"),
+			Value: strconv.FormatUint(route.slow, 10),
+		})
+	}
+
+	return items
+}
+
+// metricName returns Zabbix item key with the prefix and quoted parameters.
+func (c *RequestMetricsCollector) metricName(name string, params ...string) string {
+	if c.prefix != "" {
+		name = c.prefix + "." + name
+	}
+
+	quoted := make([]string, len(params))
+	for i, param := range params {
+		quoted[i] = `"` + strings.ReplaceAll(param, `"`, `\"`) + `"`
+	}
+	return name + "[" + strings.Join(quoted, ",") + "]"
+}
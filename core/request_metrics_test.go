@@ -0,0 +1,103 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	metrics "github.com/retailcrm/zabbix-metrics-collector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/util/testutil"
+)
+
+func metricsMap(items []metrics.Metric) map[string]string {
+	result := make(map[string]string, len(items))
+	for _, item := range items {
+		result[item.Name] = item.Value
+	}
+	return result
+}
+
+func TestRequestMetricsCollector_Middleware(t *testing.T) {
+	collector := NewRequestMetricsCollector("mg", 50*time.Millisecond)
+	g := gin.New()
+	g.Use(collector.Middleware())
+	g.GET("/users/:id", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	g.POST("/slow", func(c *gin.Context) {
+		time.Sleep(60 * time.Millisecond)
+		c.Status(http.StatusOK)
+	})
+
+	for _, path := range []string{"/users/1", "/users/2", "/not-found"} {
+		g.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	g.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/slow", nil))
+
+	assert.Equal(t, map[string]string{
+		`mg.http.request.count["GET","/users/:id"]`:          "2",
+		`mg.http.request.latency["GET","/users/:id","50ms"]`: "2",
+		`mg.http.request.latency["GET","/users/:id","inf"]`:  "0",
+		`mg.http.request.count["POST","/slow"]`:              "1",
+		`mg.http.request.latency["POST","/slow","50ms"]`:     "0",
+		`mg.http.request.latency["POST","/slow","inf"]`:      "1",
+	}, metricsMap(collector.Metrics()))
+	assert.Equal(t, map[string]string{
+		`mg.http.request.count["GET","/users/:id"]`:          "0",
+		`mg.http.request.latency["GET","/users/:id","50ms"]`: "0",
+		`mg.http.request.latency["GET","/users/:id","inf"]`:  "0",
+		`mg.http.request.count["POST","/slow"]`:              "0",
+		`mg.http.request.latency["POST","/slow","50ms"]`:     "0",
+		`mg.http.request.latency["POST","/slow","inf"]`:      "0",
+	}, metricsMap(collector.Metrics()))
+}
+
+func TestRequestMetricsCollector_QuotedParams(t *testing.T) {
+	collector := NewRequestMetricsCollector("", time.Second)
+	collector.Observe(http.MethodGet, `/files/a,b/"c"`, time.Millisecond)
+
+	assert.Equal(t, map[string]string{
+		`http.request.count["GET","/files/a,b/\"c\""]`:         "1",
+		`http.request.latency["GET","/files/a,b/\"c\"","1s"]`:  "1",
+		`http.request.latency["GET","/files/a,b/\"c\"","inf"]`: "0",
+	}, metricsMap(collector.Metrics()))
+}
+
+func TestRequestMetricsCollector_Buckets(t *testing.T) {
+	collector := NewRequestMetricsCollector("", time.Second, 100*time.Millisecond)
+	collector.Observe(http.MethodGet, "/", 50*time.Millisecond)
+	collector.Observe(http.MethodGet, "/", 500*time.Millisecond)
+	collector.Observe(http.MethodGet, "/", 2*time.Second)
+
+	items := collector.Metrics()
+	require.Len(t, items, 4)
+	assert.Equal(t, metrics.Metric{Name: `http.request.count["GET","/"]`, Value: "3"}, items[0])
+	assert.Equal(t, metrics.Metric{Name: `http.request.latency["GET","/","100ms"]`, Value: "1"}, items[1])
+	assert.Equal(t, metrics.Metric{Name: `http.request.latency["GET","/","1s"]`, Value: "2"}, items[2])
+	assert.Equal(t, metrics.Metric{Name: `http.request.latency["GET","/","inf"]`, Value: "1"}, items[3])
+	assert.Len(t, NewRequestMetricsCollector("").buckets, len(DefaultRequestLatencyBuckets))
+}
+
+func TestRequestMetricsCollector_ZabbixTransport(t *testing.T) {
+	collector := NewRequestMetricsCollector("")
+	collector.Observe(http.MethodPost, "/webhook", 10*time.Millisecond)
+	server := newZabbixServerMock(t)
+	transport := newZabbixTransport(nil, server.Sender(), "host", time.Second, testutil.NewBufferedLoggerSilent())
+	transport.WithCollector(collector)
+
+	require.NoError(t, transport.Send())
+	packets := server.Packets()
+	require.Len(t, packets, 1)
+	keys := make([]string, 0, len(packets[0].Data))
+	for _, item := range packets[0].Data {
+		keys = append(keys, item.Key)
+	}
+	assert.Contains(t, keys, `http.request.count["POST","/webhook"]`)
+	assert.Contains(t, keys, `http.request.latency["POST","/webhook","100ms"]`)
+	assert.Contains(t, keys, `http.request.latency["POST","/webhook","inf"]`)
+}
@@ -0,0 +1,111 @@
+package core
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/util/errorutil"
+)
+
+// RouteHandler handles the request mounted by Engine.MountRoutes. Returned error is converted to the response:
+//   - errorutil.HTTPError is returned with its status, message is localized if it's a translation ID;
+//   - any other error is passed to the Sentry middleware which will respond with the localized DefaultError.
+type RouteHandler func(c *gin.Context) error
+
+// Route describes single route of the RouteGroup.
+type Route struct {
+	Method     string
+	Path       string
+	Handler    RouteHandler
+	Middleware []gin.HandlerFunc
+}
+
+// RouteGroup describes routes with the common path prefix and middleware.
+type RouteGroup struct {
+	Path       string
+	Middleware []gin.HandlerFunc
+	Routes     []Route
+}
+
+// NewRouteGroup returns new RouteGroup with provided path prefix and middleware.
+// Usage:
+//
+//	api := core.NewRouteGroup("/api", authMiddleware).
+//		GET("/info", infoHandler).
+//		POST("/send", sendHandler, middleware.RequireAPIVersion("X-Api-Version", []string{"1"}))
+//	engine.MountRoutes(api)
+func NewRouteGroup(path string, middleware ...gin.HandlerFunc) *RouteGroup {
+	return &RouteGroup{Path: path, Middleware: middleware}
+}
+
+// Handle adds route with provided method to the group.
+func (g *RouteGroup) Handle(method, path string, handler RouteHandler, middleware ...gin.HandlerFunc) *RouteGroup {
+	g.Routes = append(g.Routes, Route{Method: method, Path: path, Handler: handler, Middleware: middleware})
+	return g
+}
+
+// GET adds GET route to the group.
+func (g *RouteGroup) GET(path string, handler RouteHandler, middleware ...gin.HandlerFunc) *RouteGroup {
+	return g.Handle(http.MethodGet, path, handler, middleware...)
+}
+
+// POST adds POST route to the group.
+func (g *RouteGroup) POST(path string, handler RouteHandler, middleware ...gin.HandlerFunc) *RouteGroup {
+	return g.Handle(http.MethodPost, path, handler, middleware...)
+}
+
+// PUT adds PUT route to the group.
+func (g *RouteGroup) PUT(path string, handler RouteHandler, middleware ...gin.HandlerFunc) *RouteGroup {
+	return g.Handle(http.MethodPut, path, handler, middleware...)
+}
+
+// DELETE adds DELETE route to the group.
+func (g *RouteGroup) DELETE(path string, handler RouteHandler, middleware ...gin.HandlerFunc) *RouteGroup {
+	return g.Handle(http.MethodDelete, path, handler, middleware...)
+}
+
+// MountRoutes registers routes from the provided groups in the engine router. All routes share the engine
+// middleware (Sentry, localization, etc.) and the same error handling (see RouteHandler).
+func (e *Engine) MountRoutes(groups ...*RouteGroup) *Engine {
+	router := e.Router()
+	for _, group := range groups {
+		rg := router.Group(group.Path, group.Middleware...)
+		for _, route := range group.Routes {
+			handlers := append(append([]gin.HandlerFunc{}, route.Middleware...), e.wrapRouteHandler(route.Handler))
+			rg.Handle(route.Method, route.Path, handlers...)
+		}
+	}
+
+	return e
+}
+
+// wrapRouteHandler converts RouteHandler to gin.HandlerFunc.
+func (e *Engine) wrapRouteHandler(handler RouteHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := handler(c)
+		if err == nil {
+			return
+		}
+
+		var httpErr *errorutil.HTTPError
+		if !errors.As(err, &httpErr) {
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+
+		if httpErr.IsServerError() {
+			e.CaptureException(c, err)
+		}
+
+		message := httpErr.Message
+		if localizer, ok := GetContextLocalizer(c); ok {
+			if localized, err := localizer.Localize(message); err == nil {
+				message = localized
+			}
+		}
+		c.AbortWithStatusJSON(httpErr.Status, gin.H{"error": []string{message}})
+	}
+}
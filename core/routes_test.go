@@ -0,0 +1,83 @@
+package core
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/util/errorutil"
+)
+
+func (e *EngineTest) mountTestRoutes() {
+	e.engine.TranslationsPath = testTranslationsDir
+	e.engine.DefaultError = "message"
+	e.engine.Prepare()
+
+	group := NewRouteGroup("/api", func(c *gin.Context) {
+		c.Header("X-Group", "api")
+	}).
+		GET("/ok", func(c *gin.Context) error {
+			c.String(http.StatusOK, "ok")
+			return nil
+		}, func(c *gin.Context) {
+			c.Header("X-Route", "ok")
+		}).
+		POST("/bad", func(c *gin.Context) error {
+			return errorutil.NewHTTPError(http.StatusBadRequest, "message")
+		}).
+		PUT("/raw", func(c *gin.Context) error {
+			return errorutil.NewHTTPError(http.StatusConflict, "already exists")
+		}).
+		DELETE("/fail", func(c *gin.Context) error {
+			return errors.New("database is down")
+		})
+	e.engine.MountRoutes(group)
+}
+
+func (e *EngineTest) serveRoute(method, path string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest(method, path, nil)
+	require.NoError(e.T(), err)
+	req.Header.Set("Accept-Language", "es")
+
+	rr := httptest.NewRecorder()
+	e.engine.Router().ServeHTTP(rr, req)
+	return rr
+}
+
+func (e *EngineTest) Test_MountRoutes() {
+	e.mountTestRoutes()
+
+	rr := e.serveRoute(http.MethodGet, "/api/ok")
+	assert.Equal(e.T(), http.StatusOK, rr.Code)
+	assert.Equal(e.T(), "ok", rr.Body.String())
+	assert.Equal(e.T(), "api", rr.Header().Get("X-Group"))
+	assert.Equal(e.T(), "ok", rr.Header().Get("X-Route"))
+
+	rr = e.serveRoute(http.MethodGet, "/ok")
+	assert.Equal(e.T(), http.StatusNotFound, rr.Code)
+}
+
+func (e *EngineTest) Test_MountRoutes_HTTPError() {
+	e.mountTestRoutes()
+
+	rr := e.serveRoute(http.MethodPost, "/api/bad")
+	assert.Equal(e.T(), http.StatusBadRequest, rr.Code)
+	assert.Equal(e.T(), "api", rr.Header().Get("X-Group"))
+	assert.JSONEq(e.T(), `{"error":["Mensaje de prueba"]}`, rr.Body.String())
+
+	rr = e.serveRoute(http.MethodPut, "/api/raw")
+	assert.Equal(e.T(), http.StatusConflict, rr.Code)
+	assert.JSONEq(e.T(), `{"error":["already exists"]}`, rr.Body.String())
+}
+
+func (e *EngineTest) Test_MountRoutes_Error() {
+	e.mountTestRoutes()
+
+	rr := e.serveRoute(http.MethodDelete, "/api/fail")
+	assert.Equal(e.T(), http.StatusInternalServerError, rr.Code)
+	assert.JSONEq(e.T(), `{"error":["Test message"]}`, rr.Body.String())
+}
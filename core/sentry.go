@@ -19,6 +19,7 @@ import (
 
 	"github.com/retailcrm/mg-transport-core/v2/core/logger"
 	"github.com/retailcrm/mg-transport-core/v2/core/stacktrace"
+	"github.com/retailcrm/mg-transport-core/v2/core/util/errorutil"
 
 	"github.com/gin-gonic/gin"
 )
@@ -52,7 +53,26 @@ type Sentry struct {
 	ServerName         string
 	DefaultError       string
 	TaggedTypes        SentryTaggedTypes
-	init               sync.Once
+	// SkippedFramePackages contains package prefixes which frames will be removed from the Sentry events.
+	// Use stacktrace.DefaultSkippedPackages to remove gin and net/http frames. Logs will still contain the full stack.
+	SkippedFramePackages []string
+	// ScrubFields contains keys which will be removed from the request body, headers, query string, contexts
+	// and extra data of the Sentry events. Keys are case-insensitive.
+	ScrubFields []string
+	// DropRequestBody removes request body from the Sentry events entirely.
+	DropRequestBody bool
+	// ErrorClassifier decides which errors shouldn't be sent to Sentry. DefaultSentryErrorClassifier is used if it's nil.
+	ErrorClassifier SentryErrorClassifier
+	init            sync.Once
+}
+
+// SentryErrorClassifier returns true if error is caused by the upstream (e.g. client error from the CRM)
+// and the event with it should be dropped. Only the transport's own errors should be reported.
+type SentryErrorClassifier func(err error) bool
+
+// DefaultSentryErrorClassifier drops errors which wrap errorutil.HTTPError with 4xx status.
+func DefaultSentryErrorClassifier(err error) bool {
+	return errorutil.IsClientError(err)
 }
 
 // SentryTaggedStruct holds information about type, it's key in gin.Context (for middleware), and it's properties.
@@ -72,6 +92,10 @@ type SentryTaggedScalar struct {
 type SentryLoggerConfig struct {
 	TagForConnection string
 	TagForAccount    string
+	// CaptureResponseBody attaches response body (up to MaxCapturedResponseBodySize bytes) to the events
+	// which were captured during the request. Body is stored in the SentryResponseBodyExtra extra field.
+	// If Sentry.ScrubFields are set, body is attached only if it's a complete JSON or form which can be scrubbed.
+	CaptureResponseBody bool
 }
 
 // sentryTag contains sentry tag name and corresponding value from context.
@@ -123,15 +147,40 @@ func (s *Sentry) CaptureException(c *gin.Context, exception error) {
 	}
 	if hub := sentrygin.GetHubFromContext(c); hub != nil {
 		s.setScopeTags(c, hub.Scope())
+		s.setScopeResponseBody(c, hub.Scope())
 		hub.CaptureException(exception)
 		return
 	}
 }
 
+// CaptureExceptionWithExtra sends error to Sentry along with provided extra data (payload, IDs, etc.).
+// Extra data is set only for this event. Global hub is used if the context doesn't contain one, so it can be
+// called from the code which is not handled by SentryMiddlewares.
+func (s *Sentry) CaptureExceptionWithExtra(c *gin.Context, exception error, extra map[string]interface{}) {
+	if exception == nil {
+		return
+	}
+
+	hub := sentrygin.GetHubFromContext(c)
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+
+	hub.WithScope(func(scope *sentry.Scope) {
+		if c != nil && c.Request != nil {
+			s.setScopeTags(c, scope)
+			s.setScopeResponseBody(c, scope)
+		}
+		scope.SetExtras(extra)
+		hub.CaptureException(exception)
+	})
+}
+
 // CaptureMessage and send it to Sentry.
 func (s *Sentry) CaptureMessage(c *gin.Context, message string) {
 	if hub := sentrygin.GetHubFromContext(c); hub != nil {
 		s.setScopeTags(c, hub.Scope())
+		s.setScopeResponseBody(c, hub.Scope())
 		hub.CaptureMessage(message)
 		return
 	}
@@ -141,6 +190,7 @@ func (s *Sentry) CaptureMessage(c *gin.Context, message string) {
 func (s *Sentry) CaptureEvent(c *gin.Context, event *sentry.Event) {
 	if hub := sentrygin.GetHubFromContext(c); hub != nil {
 		s.setScopeTags(c, hub.Scope())
+		s.setScopeResponseBody(c, hub.Scope())
 		hub.CaptureEvent(event)
 		return
 	}
@@ -196,6 +246,10 @@ func (s *Sentry) tagsSetterMiddleware() gin.HandlerFunc {
 // exceptionCaptureMiddleware captures exceptions and sends a proper JSON response for them.
 func (s *Sentry) exceptionCaptureMiddleware() gin.HandlerFunc { // nolint:gocognit
 	return func(c *gin.Context) {
+		if s.SentryLoggerConfig.CaptureResponseBody {
+			captureResponseBody(c)
+		}
+
 		defer func() {
 			recovery := recover()
 			publicErrors := c.Errors.ByType(gin.ErrorTypePublic)
@@ -251,10 +305,16 @@ func (s *Sentry) exceptionCaptureMiddleware() gin.HandlerFunc { // nolint:gocogn
 
 // recoveryMiddleware is mostly borrowed from the gin itself. It only contains several modifications to add logger
 // prefixes to all newlines in the log. The amount of changes is infinitesimal in comparison to the original code.
+// Panics with errorutil.HTTPError are responded with the error status and message, 4xx panics are not logged.
 func (s *Sentry) recoveryMiddleware() gin.HandlerFunc { // nolint
 	return func(c *gin.Context) {
 		defer func() {
 			if err := recover(); err != nil { // nolint:nestif
+				if httpErr := asHTTPError(err); httpErr != nil && !httpErr.IsServerError() {
+					c.AbortWithStatusJSON(httpErr.Status, gin.H{"error": []string{httpErr.Message}})
+					return
+				}
+
 				l := s.obtainErrorLogger(c)
 
 				// Check for a broken connection, as it is not really a
@@ -297,6 +357,10 @@ func (s *Sentry) recoveryMiddleware() gin.HandlerFunc { // nolint
 					c.Error(err.(error)) // nolint: errcheck
 					c.Abort()
 				} else {
+					if httpErr := asHTTPError(err); httpErr != nil {
+						c.JSON(httpErr.Status, gin.H{"error": []string{httpErr.Message}})
+						return
+					}
 					if s.Localizer == nil {
 						c.JSON(http.StatusInternalServerError, gin.H{"error": []string{s.DefaultError}})
 						return
@@ -312,9 +376,91 @@ func (s *Sentry) recoveryMiddleware() gin.HandlerFunc { // nolint
 	}
 }
 
-// setScopeTags sets Sentry tags into scope using component configuration.
+// asHTTPError returns errorutil.HTTPError from the recovered value or nil if value is not an HTTPError.
+func asHTTPError(recovered interface{}) *errorutil.HTTPError {
+	err, ok := recovered.(error)
+	if !ok {
+		return nil
+	}
+
+	var httpErr *errorutil.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr
+	}
+	return nil
+}
+
+// isUpstreamError returns true if the event error or panic is classified as the upstream error by ErrorClassifier.
+func (s *Sentry) isUpstreamError(hint *sentry.EventHint) bool {
+	if hint == nil {
+		return false
+	}
+	classifier := s.ErrorClassifier
+	if classifier == nil {
+		classifier = DefaultSentryErrorClassifier
+	}
+	if hint.OriginalException != nil && classifier(hint.OriginalException) {
+		return true
+	}
+	err, ok := hint.RecoveredException.(error)
+	return ok && classifier(err)
+}
+
+// beforeSend processes the event before sending it to Sentry. Events with the upstream errors (see ErrorClassifier)
+// are dropped.
+func (s *Sentry) beforeSend(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+	if event == nil {
+		return nil
+	}
+	if s.isUpstreamError(hint) {
+		return nil
+	}
+	if len(s.SkippedFramePackages) > 0 {
+		s.filterEventFrames(event)
+	}
+	if len(s.ScrubFields) > 0 || s.DropRequestBody {
+		s.scrubEvent(event)
+	}
+	return event
+}
+
+// filterEventFrames removes frames which belong to the SkippedFramePackages from the event stacktraces.
+// Stacktrace is left intact if every frame in it should be removed.
+func (s *Sentry) filterEventFrames(event *sentry.Event) {
+	filter := func(st *sentry.Stacktrace) {
+		if st == nil || len(st.Frames) == 0 {
+			return
+		}
+		frames := make([]sentry.Frame, 0, len(st.Frames))
+		for _, frame := range st.Frames {
+			if !stacktrace.IsPackageSkipped(frame.Module, s.SkippedFramePackages) {
+				frames = append(frames, frame)
+			}
+		}
+		if len(frames) > 0 {
+			st.Frames = frames
+		}
+	}
+
+	for i := range event.Exception {
+		filter(event.Exception[i].Stacktrace)
+	}
+	for i := range event.Threads {
+		filter(event.Threads[i].Stacktrace)
+	}
+}
+
+// setScopeTags sets Sentry tags into scope using component configuration. Request method, matched route
+// and response status (if it was already set) are added too.
 func (s *Sentry) setScopeTags(c *gin.Context, scope *sentry.Scope) {
 	scope.SetTag("endpoint", c.Request.RequestURI)
+	scope.SetTag("method", c.Request.Method)
+	if route := c.FullPath(); route != "" {
+		scope.SetTag("route", route)
+	}
+	if c.Writer != nil && (c.Writer.Written() || c.Writer.Status() != http.StatusOK) {
+		scope.SetTag("status", strconv.Itoa(c.Writer.Status()))
+	}
 
 	for tag := range s.tagsFromContext(c) {
 		scope.SetTag(tag.Name, tag.Value)
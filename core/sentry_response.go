@@ -0,0 +1,67 @@
+package core
+
+import (
+	"bytes"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// SentryResponseBodyExtra is the Sentry event extra field which contains captured response body.
+	SentryResponseBodyExtra = "response_body"
+	// MaxCapturedResponseBodySize is the maximum size of the response body which will be attached to the event.
+	MaxCapturedResponseBodySize = 16 << 10
+)
+
+// responseCaptureContextKey is used to store responseCaptureWriter in the context.
+const responseCaptureContextKey = "sentryResponseCapture"
+
+// responseCaptureWriter copies the response body into the buffer.
+type responseCaptureWriter struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	truncated bool
+}
+
+// Write writes data to the response and to the buffer.
+func (w *responseCaptureWriter) Write(data []byte) (int, error) {
+	w.capture(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString writes string to the response and to the buffer.
+func (w *responseCaptureWriter) WriteString(data string) (int, error) {
+	w.capture([]byte(data))
+	return w.ResponseWriter.WriteString(data)
+}
+
+func (w *responseCaptureWriter) capture(data []byte) {
+	left := MaxCapturedResponseBodySize - w.body.Len()
+	if len(data) > left {
+		data = data[:max(left, 0)]
+		w.truncated = true
+	}
+	w.body.Write(data)
+}
+
+// captureResponseBody replaces the context writer with responseCaptureWriter.
+func captureResponseBody(c *gin.Context) {
+	w := &responseCaptureWriter{ResponseWriter: c.Writer}
+	c.Writer = w
+	c.Set(responseCaptureContextKey, w)
+}
+
+// setScopeResponseBody attaches captured response body to the scope if it's present. Truncated body is not
+// attached if ScrubFields are set because it cannot be parsed and scrubbed.
+func (s *Sentry) setScopeResponseBody(c *gin.Context, scope *sentry.Scope) {
+	item, ok := c.Get(responseCaptureContextKey)
+	if !ok {
+		return
+	}
+	w, ok := item.(*responseCaptureWriter)
+	if !ok || w.body.Len() == 0 || (w.truncated && len(s.ScrubFields) > 0) {
+		return
+	}
+	scope.SetExtra(SentryResponseBodyExtra, w.body.String())
+}
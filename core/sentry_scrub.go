@@ -0,0 +1,125 @@
+package core
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// scrubEvent removes ScrubFields from the event request and contexts. Request body is removed entirely
+// if DropRequestBody is true. JSON and form request bodies are scrubbed, other bodies are left intact.
+// Request cookies are always removed because they usually contain session identifiers and tokens.
+// Captured response body (see SentryLoggerConfig.CaptureResponseBody) is scrubbed the same way, but it's removed
+// if it's neither JSON nor form because its fields cannot be found.
+func (s *Sentry) scrubEvent(event *sentry.Event) {
+	fields := make(map[string]struct{}, len(s.ScrubFields))
+	for _, field := range s.ScrubFields {
+		fields[strings.ToLower(field)] = struct{}{}
+	}
+
+	if event.Request != nil {
+		if s.DropRequestBody {
+			event.Request.Data = ""
+		} else {
+			event.Request.Data = scrubRequestBody(event.Request.Data, fields)
+		}
+		event.Request.Cookies = ""
+		for header := range event.Request.Headers {
+			if isScrubbedField(header, fields) || strings.EqualFold(header, "Cookie") {
+				delete(event.Request.Headers, header)
+			}
+		}
+		if query, ok := scrubForm(event.Request.QueryString, fields); ok {
+			event.Request.QueryString = query
+		}
+	}
+
+	for key, ctx := range event.Contexts {
+		if isScrubbedField(key, fields) {
+			delete(event.Contexts, key)
+			continue
+		}
+		scrubMap(ctx, fields)
+	}
+	scrubMap(event.Extra, fields)
+	if body, ok := event.Extra[SentryResponseBodyExtra].(string); ok && len(fields) > 0 {
+		if scrubbed, ok := scrubBody(body, fields); ok {
+			event.Extra[SentryResponseBodyExtra] = scrubbed
+		} else {
+			delete(event.Extra, SentryResponseBodyExtra)
+		}
+	}
+}
+
+// scrubRequestBody removes fields from JSON or form body. Other bodies are returned as is.
+func scrubRequestBody(data string, fields map[string]struct{}) string {
+	if data == "" || len(fields) == 0 {
+		return data
+	}
+
+	if scrubbed, ok := scrubBody(data, fields); ok {
+		return scrubbed
+	}
+
+	return data
+}
+
+// scrubBody removes fields from JSON or form body. It returns false if body is neither JSON nor form.
+func scrubBody(data string, fields map[string]struct{}) (string, bool) {
+	var body interface{}
+	if err := json.Unmarshal([]byte(data), &body); err == nil {
+		scrubValue(body, fields)
+		result, err := json.Marshal(body)
+		return string(result), err == nil
+	}
+
+	return scrubForm(data, fields)
+}
+
+// scrubForm removes fields from URL-encoded form. It returns false if data is not a valid form.
+func scrubForm(data string, fields map[string]struct{}) (string, bool) {
+	if data == "" || len(fields) == 0 || !strings.Contains(data, "=") {
+		return data, false
+	}
+
+	values, err := url.ParseQuery(data)
+	if err != nil {
+		return data, false
+	}
+	for key := range values {
+		if isScrubbedField(key, fields) {
+			values.Del(key)
+		}
+	}
+
+	return values.Encode(), true
+}
+
+// scrubValue removes fields from the nested maps and slices.
+func scrubValue(val interface{}, fields map[string]struct{}) {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		scrubMap(v, fields)
+	case []interface{}:
+		for _, item := range v {
+			scrubValue(item, fields)
+		}
+	}
+}
+
+func scrubMap(m map[string]interface{}, fields map[string]struct{}) {
+	for key, val := range m {
+		if isScrubbedField(key, fields) {
+			delete(m, key)
+			continue
+		}
+		scrubValue(val, fields)
+	}
+}
+
+func isScrubbedField(key string, fields map[string]struct{}) bool {
+	_, ok := fields[strings.ToLower(key)]
+	return ok
+}
@@ -2,8 +2,10 @@ package core
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -17,6 +19,7 @@ import (
 	"github.com/retailcrm/mg-transport-core/v2/core/db/models"
 	"github.com/retailcrm/mg-transport-core/v2/core/logger"
 	"github.com/retailcrm/mg-transport-core/v2/core/stacktrace"
+	"github.com/retailcrm/mg-transport-core/v2/core/util/errorutil"
 	"github.com/retailcrm/mg-transport-core/v2/core/util/testutil"
 )
 
@@ -275,6 +278,43 @@ func (s *SentryTest) TestSentry_CaptureException() {
 	s.Assert().NotNil(transport.lastEvent.Exception[1].Stacktrace)
 }
 
+func (s *SentryTest) TestSentry_CaptureExceptionWithExtra() {
+	ctx, transport := s.ginCtxMock()
+	s.sentry.CaptureExceptionWithExtra(ctx, errors.New("test error"), map[string]interface{}{
+		"payload": `{"id":1}`,
+		"orderID": 1,
+	})
+
+	s.Require().NotNil(transport.lastEvent)
+	s.Assert().Equal(`{"id":1}`, transport.lastEvent.Extra["payload"])
+	s.Assert().Equal(1, transport.lastEvent.Extra["orderID"])
+
+	s.sentry.CaptureException(ctx, errors.New("another error"))
+	s.Require().NotNil(transport.lastEvent)
+	s.Assert().NotContains(transport.lastEvent.Extra, "payload")
+}
+
+func (s *SentryTest) TestSentry_CaptureExceptionWithExtra_NoHubInContext() {
+	hub, transport := s.hubMock()
+	current := sentry.CurrentHub()
+	previous := current.Client()
+	current.BindClient(hub.Client())
+	defer current.BindClient(previous)
+
+	ctx := &gin.Context{Request: httptest.NewRequest(http.MethodGet, "/", nil)}
+	s.sentry.CaptureExceptionWithExtra(ctx, errors.New("test error"), map[string]interface{}{"id": "value"})
+
+	s.Require().NotNil(transport.lastEvent)
+	s.Assert().Equal("value", transport.lastEvent.Extra["id"])
+	s.Assert().Equal("test error", transport.lastEvent.Exception[0].Value)
+}
+
+func (s *SentryTest) TestSentry_CaptureExceptionWithExtra_Nil() {
+	s.Assert().NotPanics(func() {
+		s.sentry.CaptureExceptionWithExtra(nil, nil, nil)
+	})
+}
+
 func (s *SentryTest) TestSentry_CaptureEvent_Nil() {
 	defer func() {
 		s.Assert().Nil(recover())
@@ -369,6 +409,329 @@ func (s *SentryTest) TestSentry_MiddlewaresError() {
 	s.Assert().NotNil(transport.lastEvent.Exception[2].Stacktrace)
 }
 
+func (s *SentryTest) serveHTTPErrorPanic(
+	httpErr *errorutil.HTTPError,
+) (*httptest.ResponseRecorder, *sentryMockTransport) {
+	opts := s.sentry.SentryConfig
+	opts.BeforeSend = s.sentry.beforeSend
+	client, err := sentry.NewClient(opts)
+	s.Require().NoError(err)
+	transport := newSentryMockTransport()
+	client.Transport = transport
+	hub := sentry.NewHub(client, sentry.NewScope())
+
+	g := gin.New()
+	g.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(sentry.SetHubOnContext(c.Request.Context(), hub))
+	})
+	g.Use(s.sentry.SentryMiddlewares()...)
+	g.GET("/", func(c *gin.Context) {
+		panic(httpErr)
+	})
+
+	rr := httptest.NewRecorder()
+	g.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	return rr, transport
+}
+
+func (s *SentryTest) TestSentry_MiddlewaresError_RequestTags() {
+	client, err := sentry.NewClient(s.sentry.SentryConfig)
+	s.Require().NoError(err)
+	transport := newSentryMockTransport()
+	client.Transport = transport
+	hub := sentry.NewHub(client, sentry.NewScope())
+
+	g := gin.New()
+	g.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(sentry.SetHubOnContext(c.Request.Context(), hub))
+	})
+	g.Use(s.sentry.SentryMiddlewares()...)
+	g.POST("/users/:id", func(c *gin.Context) {
+		c.Status(http.StatusBadGateway)
+		_ = c.Error(errors.New("upstream is unavailable"))
+	})
+
+	rr := httptest.NewRecorder()
+	g.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/users/1?lang=en", nil))
+
+	s.Require().NotNil(transport.lastEvent)
+	s.Assert().Equal(http.MethodPost, transport.lastEvent.Tags["method"])
+	s.Assert().Equal("/users/:id", transport.lastEvent.Tags["route"])
+	s.Assert().Equal("502", transport.lastEvent.Tags["status"])
+	s.Assert().Equal("/users/1?lang=en", transport.lastEvent.Tags["endpoint"])
+}
+
+func (s *SentryTest) TestSentry_CaptureException_RequestTags() {
+	ctx, transport := s.ginCtxMock()
+	s.sentry.CaptureException(ctx, errors.New("test error"))
+	s.Require().NotNil(transport.lastEvent)
+	s.Assert().Equal(http.MethodGet, transport.lastEvent.Tags["method"])
+	s.Assert().NotContains(transport.lastEvent.Tags, "route")
+	s.Assert().NotContains(transport.lastEvent.Tags, "status")
+
+	hub, transport := s.hubMock()
+	ctx, _ = gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest(http.MethodDelete, "/", nil)
+	ctx.Set("sentry", hub)
+	ctx.Status(http.StatusConflict)
+	s.sentry.CaptureException(ctx, errors.New("test error"))
+	s.Require().NotNil(transport.lastEvent)
+	s.Assert().Equal(http.MethodDelete, transport.lastEvent.Tags["method"])
+	s.Assert().Equal("409", transport.lastEvent.Tags["status"])
+}
+
+func (s *SentryTest) TestSentry_MiddlewaresPanic_ClientHTTPError() {
+	rr, transport := s.serveHTTPErrorPanic(errorutil.NewHTTPError(http.StatusBadRequest, "invalid payload"))
+
+	s.Assert().Equal(http.StatusBadRequest, rr.Code)
+	s.Assert().JSONEq(`{"error":["invalid payload"]}`, rr.Body.String())
+	s.Assert().Nil(transport.lastEvent)
+}
+
+func (s *SentryTest) TestSentry_MiddlewaresPanic_ServerHTTPError() {
+	rr, transport := s.serveHTTPErrorPanic(errorutil.NewHTTPError(http.StatusBadGateway, "upstream error"))
+
+	s.Assert().Equal(http.StatusBadGateway, rr.Code)
+	s.Assert().JSONEq(`{"error":["upstream error"]}`, rr.Body.String())
+	s.Assert().NotNil(transport.lastEvent)
+}
+
+func (s *SentryTest) TestSentry_MiddlewaresError_CaptureResponseBody() {
+	sentryInstance := &Sentry{
+		Logger:             s.logger,
+		DefaultError:       "error_save",
+		SentryLoggerConfig: SentryLoggerConfig{CaptureResponseBody: true},
+		ScrubFields:        []string{"token"},
+	}
+	opts := s.sentry.SentryConfig
+	opts.BeforeSend = sentryInstance.beforeSend
+	client, err := sentry.NewClient(opts)
+	s.Require().NoError(err)
+	transport := newSentryMockTransport()
+	client.Transport = transport
+	hub := sentry.NewHub(client, sentry.NewScope())
+
+	g := gin.New()
+	g.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(sentry.SetHubOnContext(c.Request.Context(), hub))
+	})
+	g.Use(sentryInstance.SentryMiddlewares()...)
+	g.GET("/", func(c *gin.Context) {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "upstream error", "token": "secret"})
+		_ = c.Error(errors.New("upstream error"))
+	})
+
+	rr := httptest.NewRecorder()
+	g.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	s.Assert().Equal(http.StatusBadGateway, rr.Code)
+	s.Require().NotNil(transport.lastEvent)
+	s.Assert().JSONEq(`{"error":"upstream error"}`, transport.lastEvent.Extra[SentryResponseBodyExtra].(string))
+}
+
+func (s *SentryTest) TestSentry_responseCaptureWriter_Limit() {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	captureResponseBody(c)
+	c.String(http.StatusOK, strings.Repeat("a", MaxCapturedResponseBodySize+10))
+
+	scope := sentry.NewScope()
+	(&Sentry{}).setScopeResponseBody(c, scope)
+	event := scope.ApplyToEvent(&sentry.Event{}, nil, nil)
+	s.Assert().Len(event.Extra[SentryResponseBodyExtra], MaxCapturedResponseBodySize)
+
+	scope = sentry.NewScope()
+	(&Sentry{ScrubFields: []string{"token"}}).setScopeResponseBody(c, scope)
+	event = scope.ApplyToEvent(&sentry.Event{}, nil, nil)
+	s.Assert().NotContains(event.Extra, SentryResponseBodyExtra)
+}
+
+func (s *SentryTest) TestSentry_MiddlewaresError_CaptureResponseBody_Scrub() {
+	sentryInstance := &Sentry{
+		Logger:             s.logger,
+		DefaultError:       "error_save",
+		SentryLoggerConfig: SentryLoggerConfig{CaptureResponseBody: true},
+		ScrubFields:        []string{"token"},
+	}
+	opts := s.sentry.SentryConfig
+	opts.BeforeSend = sentryInstance.beforeSend
+	client, err := sentry.NewClient(opts)
+	s.Require().NoError(err)
+	transport := newSentryMockTransport()
+	client.Transport = transport
+	hub := sentry.NewHub(client, sentry.NewScope())
+
+	g := gin.New()
+	g.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(sentry.SetHubOnContext(c.Request.Context(), hub))
+	})
+	g.Use(sentryInstance.SentryMiddlewares()...)
+	g.GET("/oversized", func(c *gin.Context) {
+		// The token is within the captured part, but the truncated JSON cannot be parsed.
+		c.JSON(http.StatusBadGateway, gin.H{
+			"a_token": "secret", "error": "upstream error", "z": strings.Repeat("a", MaxCapturedResponseBodySize),
+		})
+		_ = c.Error(errors.New("upstream error"))
+	})
+	g.GET("/text", func(c *gin.Context) {
+		c.String(http.StatusBadGateway, "upstream error, token: secret")
+		_ = c.Error(errors.New("upstream error"))
+	})
+
+	for _, path := range []string{"/oversized", "/text"} {
+		transport.lastEvent = nil
+		rr := httptest.NewRecorder()
+		g.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+
+		s.Require().NotNil(transport.lastEvent, path)
+		s.Assert().NotContains(transport.lastEvent.Extra, SentryResponseBodyExtra, path)
+	}
+}
+
+func (s *SentryTest) TestSentry_beforeSend_UpstreamErrors() {
+	event := &sentry.Event{Message: "event"}
+	sentryInstance := &Sentry{}
+
+	s.Assert().Nil(sentryInstance.beforeSend(event, &sentry.EventHint{
+		OriginalException: fmt.Errorf("cannot send: %w", errorutil.NewHTTPError(http.StatusBadRequest, "invalid")),
+	}))
+	s.Assert().Nil(sentryInstance.beforeSend(event, &sentry.EventHint{
+		RecoveredException: errorutil.NewHTTPError(http.StatusNotFound, "not found"),
+	}))
+	s.Assert().Equal(event, sentryInstance.beforeSend(event, &sentry.EventHint{
+		OriginalException: errorutil.NewHTTPError(http.StatusBadGateway, "upstream error"),
+	}))
+	s.Assert().Equal(event, sentryInstance.beforeSend(event, &sentry.EventHint{
+		OriginalException: errors.New("unknown error"),
+	}))
+	s.Assert().Equal(event, sentryInstance.beforeSend(event, &sentry.EventHint{RecoveredException: "panic"}))
+}
+
+func (s *SentryTest) TestSentry_beforeSend_CustomErrorClassifier() {
+	errIgnored := errors.New("ignored")
+	event := &sentry.Event{Message: "event"}
+	sentryInstance := &Sentry{ErrorClassifier: func(err error) bool {
+		return errors.Is(err, errIgnored)
+	}}
+
+	s.Assert().Nil(sentryInstance.beforeSend(event, &sentry.EventHint{
+		OriginalException: fmt.Errorf("wrapped: %w", errIgnored),
+	}))
+	s.Assert().Equal(event, sentryInstance.beforeSend(event, &sentry.EventHint{
+		OriginalException: errorutil.NewHTTPError(http.StatusBadRequest, "invalid"),
+	}))
+}
+
+func (s *SentryTest) TestSentry_CaptureException_UpstreamError() {
+	opts := s.sentry.SentryConfig
+	opts.BeforeSend = s.sentry.beforeSend
+	client, err := sentry.NewClient(opts)
+	s.Require().NoError(err)
+	transport := newSentryMockTransport()
+	client.Transport = transport
+	ctx := &gin.Context{Request: httptest.NewRequest(http.MethodGet, "/", nil)}
+	ctx.Set("sentry", sentry.NewHub(client, sentry.NewScope()))
+
+	s.sentry.CaptureException(ctx, fmt.Errorf("cannot send: %w",
+		errorutil.NewHTTPError(http.StatusUnprocessableEntity, "invalid")))
+	s.Assert().Nil(transport.lastEvent)
+
+	s.sentry.CaptureException(ctx, errors.New("unknown error"))
+	s.Assert().NotNil(transport.lastEvent)
+}
+
+func (s *SentryTest) TestSentry_beforeSend_FilterFrames() {
+	sentryInstance := &Sentry{SkippedFramePackages: stacktrace.DefaultSkippedPackages}
+	event := &sentry.Event{
+		Exception: []sentry.Exception{{
+			Stacktrace: &sentry.Stacktrace{Frames: []sentry.Frame{
+				{Module: "net/http", Function: "HandlerFunc.ServeHTTP"},
+				{Module: "github.com/gin-gonic/gin", Function: "(*Context).Next"},
+				{Module: "github.com/retailcrm/transport", Function: "handler"},
+			}},
+		}},
+		Threads: []sentry.Thread{{
+			Stacktrace: &sentry.Stacktrace{Frames: []sentry.Frame{
+				{Module: "github.com/gin-gonic/gin", Function: "(*Context).Next"},
+			}},
+		}},
+	}
+
+	result := sentryInstance.beforeSend(event, nil)
+	s.Require().NotNil(result)
+	s.Require().Len(result.Exception[0].Stacktrace.Frames, 1)
+	s.Assert().Equal("github.com/retailcrm/transport", result.Exception[0].Stacktrace.Frames[0].Module)
+	s.Assert().Len(result.Threads[0].Stacktrace.Frames, 1, "stacktrace should be intact if all frames are skipped")
+}
+
+func (s *SentryTest) TestSentry_beforeSend_NoFilter() {
+	frames := []sentry.Frame{
+		{Module: "net/http", Function: "HandlerFunc.ServeHTTP"},
+		{Module: "github.com/retailcrm/transport", Function: "handler"},
+	}
+	event := &sentry.Event{Exception: []sentry.Exception{{Stacktrace: &sentry.Stacktrace{Frames: frames}}}}
+
+	result := (&Sentry{}).beforeSend(event, nil)
+	s.Require().NotNil(result)
+	s.Assert().Equal(frames, result.Exception[0].Stacktrace.Frames)
+}
+
+func (s *SentryTest) TestSentry_beforeSend_Scrub() {
+	sentryInstance := &Sentry{ScrubFields: []string{"card", "Authorization", "token", "payment"}}
+	client, err := sentry.NewClient(sentry.ClientOptions{Dsn: TestSentryDSN, BeforeSend: sentryInstance.beforeSend})
+	s.Require().NoError(err)
+	transport := newSentryMockTransport()
+	client.Transport = transport
+
+	sentry.NewHub(client, sentry.NewScope()).CaptureEvent(&sentry.Event{
+		Message: "test",
+		Request: &sentry.Request{
+			Data:        `{"name":"John","card":"4111111111111111","items":[{"card":"4111","id":1}]}`,
+			QueryString: "token=secret&page=1",
+			Cookies:     "session=secret; theme=dark",
+			Headers: map[string]string{
+				"authorization": "Bearer token", "Accept": "application/json", "Cookie": "session=secret; theme=dark",
+			},
+		},
+		Contexts: map[string]sentry.Context{
+			"payment": {"card": "4111111111111111"},
+			"order":   {"id": 1, "customer": map[string]interface{}{"Card": "4111", "name": "John"}},
+		},
+		Extra: map[string]interface{}{"token": "secret", "attempt": 1},
+	})
+
+	s.Require().NotNil(transport.lastEvent)
+	req := transport.lastEvent.Request
+	s.Require().NotNil(req)
+	s.Assert().JSONEq(`{"name":"John","items":[{"id":1}]}`, req.Data)
+	s.Assert().Equal("page=1", req.QueryString)
+	s.Assert().Empty(req.Cookies)
+	s.Assert().Equal(map[string]string{"Accept": "application/json"}, req.Headers)
+	s.Assert().NotContains(transport.lastEvent.Contexts, "payment")
+	s.Assert().Equal(map[string]interface{}{"name": "John"}, transport.lastEvent.Contexts["order"]["customer"])
+	s.Assert().Equal(map[string]interface{}{"attempt": 1}, transport.lastEvent.Extra)
+}
+
+func (s *SentryTest) TestSentry_beforeSend_ScrubForm() {
+	event := &sentry.Event{Request: &sentry.Request{Data: "login=user&password=secret"}}
+	result := (&Sentry{ScrubFields: []string{"password"}}).beforeSend(event, nil)
+	s.Assert().Equal("login=user", result.Request.Data)
+
+	event = &sentry.Event{Request: &sentry.Request{Data: "plain text password"}}
+	result = (&Sentry{ScrubFields: []string{"password"}}).beforeSend(event, nil)
+	s.Assert().Equal("plain text password", result.Request.Data)
+}
+
+func (s *SentryTest) TestSentry_beforeSend_DropRequestBody() {
+	event := &sentry.Event{Request: &sentry.Request{
+		Data:    `{"card":"4111111111111111"}`,
+		Headers: map[string]string{"Accept": "application/json"},
+	}}
+
+	result := (&Sentry{DropRequestBody: true}).beforeSend(event, nil)
+	s.Assert().Empty(result.Request.Data)
+	s.Assert().Equal(map[string]string{"Accept": "application/json"}, result.Request.Headers)
+}
+
 func TestSentry_Suite(t *testing.T) {
 	suite.Run(t, new(SentryTest))
 }
@@ -0,0 +1,82 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// DefaultSentryFlushTimeout is used by Engine.Shutdown to flush Sentry events if context has no deadline.
+const DefaultSentryFlushTimeout = 2 * time.Second
+
+// MinSentryFlushTimeout is used by Engine.Shutdown to flush Sentry events if context deadline is too close
+// or has already passed (e.g. hooks have consumed the whole shutdown timeout).
+const MinSentryFlushTimeout = 100 * time.Millisecond
+
+// ErrSentryFlushTimeout is returned by Engine.Shutdown if buffered Sentry events weren't sent in time.
+var ErrSentryFlushTimeout = errors.New("cannot flush sentry events: timeout")
+
+// ShutdownHook is called by Engine.Shutdown. It should respect the context cancellation.
+type ShutdownHook func(ctx context.Context) error
+
+// OnShutdown registers hook which will be called by Engine.Shutdown. Hooks are called in the reverse order
+// of registration, so the resource which was initialized first will be released last.
+func (e *Engine) OnShutdown(fn ShutdownHook) *Engine {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.shutdownHooks = append(e.shutdownHooks, fn)
+	return e
+}
+
+// Shutdown calls registered shutdown hooks, stops the database pinger, flushes Sentry events and closes
+// the database connection. All errors are joined into one, hooks are called even if the previous hook has failed.
+// Hooks are removed after the call, so they won't be called twice.
+// Usage:
+//
+//	app.OnShutdown(func(ctx context.Context) error {
+//		return server.Shutdown(ctx)
+//	})
+//	// Wait for signal.
+//	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//	defer cancel()
+//	if err := app.Shutdown(ctx); err != nil {
+//		app.Logger().Error("shutdown failed", logger.Err(err))
+//	}
+func (e *Engine) Shutdown(ctx context.Context) error {
+	e.mutex.Lock()
+	hooks := e.shutdownHooks
+	e.shutdownHooks = nil
+	e.mutex.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i](ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if e.dbPinger != nil {
+		e.dbPinger.Stop()
+	}
+
+	if sentry.CurrentHub().Client() != nil {
+		timeout := DefaultSentryFlushTimeout
+		if deadline, ok := ctx.Deadline(); ok {
+			timeout = max(time.Until(deadline), MinSentryFlushTimeout)
+		}
+		if !sentry.Flush(timeout) {
+			errs = append(errs, ErrSentryFlushTimeout)
+		}
+	}
+
+	if e.DB != nil {
+		if err := e.DB.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("cannot close database: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,107 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/config"
+)
+
+func (e *EngineTest) prepareShutdownEngine() sqlmock.Sqlmock {
+	db, mock, err := sqlmock.New()
+	require.NoError(e.T(), err)
+
+	cfg := e.engine.Config.(config.Config)
+	cfg.Database.Connection = db
+	e.engine.Config = cfg
+	e.engine.TranslationsPath = testTranslationsDir
+	e.engine.Prepare()
+	return mock
+}
+
+func (e *EngineTest) Test_Shutdown_HooksOrder() {
+	mock := e.prepareShutdownEngine()
+	mock.ExpectClose()
+
+	var calls []int
+	for i := 1; i <= 3; i++ {
+		i := i
+		e.engine.OnShutdown(func(ctx context.Context) error {
+			calls = append(calls, i)
+			return nil
+		})
+	}
+
+	require.NoError(e.T(), e.engine.Shutdown(context.Background()))
+	assert.Equal(e.T(), []int{3, 2, 1}, calls)
+	assert.NoError(e.T(), mock.ExpectationsWereMet())
+
+	calls = nil
+	mock.ExpectClose()
+	require.NoError(e.T(), e.engine.Shutdown(context.Background()))
+	assert.Empty(e.T(), calls)
+}
+
+// sentryFlushTransportMock records the flush timeout and fails the flush if timeout is not positive.
+type sentryFlushTransportMock struct {
+	sentryMockTransport
+	timeout time.Duration
+}
+
+func (s *sentryFlushTransportMock) Flush(timeout time.Duration) bool {
+	s.timeout = timeout
+	return timeout > 0
+}
+
+func (e *EngineTest) Test_Shutdown_SentryFlushExpiredContext() {
+	mock := e.prepareShutdownEngine()
+	mock.ExpectClose()
+
+	client, err := sentry.NewClient(sentry.ClientOptions{Dsn: TestSentryDSN})
+	require.NoError(e.T(), err)
+	transport := &sentryFlushTransportMock{}
+	client.Transport = transport
+	hub := sentry.CurrentHub()
+	previous := hub.Client()
+	hub.BindClient(client)
+	defer hub.BindClient(previous)
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	require.NoError(e.T(), e.engine.Shutdown(ctx))
+	assert.Equal(e.T(), MinSentryFlushTimeout, transport.timeout)
+	assert.NoError(e.T(), mock.ExpectationsWereMet())
+}
+
+func (e *EngineTest) Test_Shutdown_Errors() {
+	mock := e.prepareShutdownEngine()
+	closeErr := errors.New("close error")
+	mock.ExpectClose().WillReturnError(closeErr)
+
+	firstErr := errors.New("first")
+	secondErr := errors.New("second")
+	var lastCalled bool
+	e.engine.
+		OnShutdown(func(ctx context.Context) error {
+			lastCalled = true
+			return firstErr
+		}).
+		OnShutdown(func(ctx context.Context) error {
+			return secondErr
+		})
+
+	err := e.engine.Shutdown(context.Background())
+	require.Error(e.T(), err)
+	assert.True(e.T(), lastCalled)
+	assert.ErrorIs(e.T(), err, firstErr)
+	assert.ErrorIs(e.T(), err, secondErr)
+	assert.ErrorIs(e.T(), err, closeErr)
+	assert.NoError(e.T(), mock.ExpectationsWereMet())
+}
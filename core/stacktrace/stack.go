@@ -14,6 +14,13 @@ import (
 
 const unknown = "unknown"
 
+// DefaultSkippedPackages contains framework packages which frames are rarely relevant in the error reports.
+// Use it with StackTrace.Filter to make the application frames more prominent.
+var DefaultSkippedPackages = []string{
+	"github.com/gin-gonic/gin",
+	"net/http",
+}
+
 var (
 	dunno     = []byte("???")
 	centerDot = []byte("·")
@@ -61,6 +68,15 @@ func (f Frame) name() string {
 	return fn.Name()
 }
 
+// pkg returns the import path of the package which contains the function for this Frame's pc.
+func (f Frame) pkg() string {
+	name := f.name()
+	if name == unknown {
+		return ""
+	}
+	return packageName(name)
+}
+
 // Format formats the frame according to the fmt.Formatter interface.
 //
 //	%s    source file
@@ -135,6 +151,36 @@ func (st StackTrace) Format(s fmt.State, verb rune) {
 	}
 }
 
+// Filter returns a copy of the StackTrace without the frames which belong to the packages with provided prefixes.
+// The original StackTrace is left intact, so the full stack is still available for the logs.
+func (st StackTrace) Filter(prefixes ...string) StackTrace {
+	if len(prefixes) == 0 {
+		return st
+	}
+
+	filtered := make(StackTrace, 0, len(st))
+	for _, f := range st {
+		if !IsPackageSkipped(f.pkg(), prefixes) {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
+}
+
+// IsPackageSkipped returns true if package import path matches one of the provided prefixes.
+// Prefix matches only the whole path segments: "net/http" matches "net/http/httputil", but not "net/httpx".
+func IsPackageSkipped(pkg string, prefixes []string) bool {
+	if pkg == "" {
+		return false
+	}
+	for _, prefix := range prefixes {
+		if pkg == prefix || strings.HasPrefix(pkg, strings.TrimSuffix(prefix, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
 // formatSlice will format this StackTrace into the given buffer as a slice of
 // Frame, only valid when called with '%s' or '%v'.
 func (st StackTrace) formatSlice(s fmt.State, verb rune) {
@@ -241,6 +287,18 @@ func source(lines [][]byte, n int) []byte {
 	return bytes.TrimSpace(lines[n])
 }
 
+// packageName returns the package import path from a function's name reported by func.Name().
+func packageName(name string) string {
+	pathEnd := strings.LastIndex(name, "/")
+	if pathEnd < 0 {
+		pathEnd = 0
+	}
+	if i := strings.Index(name[pathEnd:], "."); i != -1 {
+		return name[:pathEnd+i]
+	}
+	return ""
+}
+
 // funcname removes the path prefix component of a function's name reported by func.Name().
 func funcname(name string) string {
 	i := strings.LastIndex(name, "/")
@@ -58,6 +58,47 @@ func Test_source(t *testing.T) {
 	assert.Equal(t, []byte("test"), source([][]byte{[]byte("test")}, 1))
 }
 
+func Test_packageName(t *testing.T) {
+	assert.Equal(t, "github.com/gin-gonic/gin", packageName("github.com/gin-gonic/gin.(*Context).Next"))
+	assert.Equal(t, "net/http", packageName("net/http.HandlerFunc.ServeHTTP"))
+	assert.Equal(t, "testing", packageName("testing.tRunner"))
+	assert.Equal(t, "", packageName("main"))
+}
+
+func TestIsPackageSkipped(t *testing.T) {
+	assert.True(t, IsPackageSkipped("net/http", DefaultSkippedPackages))
+	assert.True(t, IsPackageSkipped("net/http/httputil", DefaultSkippedPackages))
+	assert.True(t, IsPackageSkipped("github.com/gin-gonic/gin", DefaultSkippedPackages))
+	assert.False(t, IsPackageSkipped("net/httpx", DefaultSkippedPackages))
+	assert.False(t, IsPackageSkipped("github.com/retailcrm/mg-transport-core/v2/core", DefaultSkippedPackages))
+	assert.False(t, IsPackageSkipped("", DefaultSkippedPackages))
+}
+
+func (t *StackTraceTest) TestFilter() {
+	st := callers(2).StackTrace()
+	filtered := st.Filter("testing")
+
+	t.Require().NotEmpty(filtered)
+	t.Assert().Less(len(filtered), len(st))
+	for _, f := range filtered {
+		t.Assert().NotEqual("testing", f.pkg())
+	}
+	t.Assert().Contains(filtered[0].name(), "TestFilter")
+	t.Assert().True(func() bool {
+		for _, f := range st {
+			if f.pkg() == "testing" {
+				return true
+			}
+		}
+		return false
+	}(), "original stacktrace must be left intact")
+}
+
+func (t *StackTraceTest) TestFilter_NoPrefixes() {
+	st := callers(2).StackTrace()
+	t.Assert().Equal(st, st.Filter())
+}
+
 func Test_funcname(t *testing.T) {
 	assert.Equal(t, "c", funcname("a/b.c"))
 }
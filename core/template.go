@@ -1,10 +1,16 @@
 package core
 
 import (
+	"errors"
+	"fmt"
 	"html/template"
 	"io/fs"
+	"path/filepath"
+	"sort"
+	"sync"
 
 	"github.com/gin-contrib/multitemplate"
+	"github.com/gin-gonic/gin/render"
 )
 
 // Renderer wraps multitemplate.Renderer in order to make it easier to use.
@@ -13,6 +19,8 @@ type Renderer struct {
 	TemplatesFS  fs.FS
 	FuncMap      template.FuncMap
 	alreadyAdded map[string]*template.Template
+	// registered contains templates which will be parsed by Preload.
+	registered map[string][]string
 }
 
 // NewRenderer is a Renderer constructor.
@@ -36,6 +44,7 @@ func newRendererWithMultitemplate(funcMap template.FuncMap, renderer multitempla
 		Renderer:     renderer,
 		FuncMap:      funcMap,
 		alreadyAdded: map[string]*template.Template{},
+		registered:   map[string][]string{},
 	}
 }
 
@@ -52,6 +61,74 @@ func (r *Renderer) Push(name string, files ...string) *template.Template {
 	return r.storeTemplate(name, r.AddFromFilesFuncs(name, r.FuncMap, files...))
 }
 
+// Register adds template which will be parsed by Preload. Unlike Push, it doesn't parse the files immediately
+// and doesn't panic, so all templates can be checked at once with Preload.
+func (r *Renderer) Register(name string, files ...string) *Renderer {
+	if r.registered == nil {
+		r.registered = map[string][]string{}
+	}
+
+	r.registered[name] = files
+	return r
+}
+
+// Preload parses all templates added by Register and adds them to the renderer, so they're compiled only once
+// at the startup. Parse errors for all invalid templates are returned, valid templates are added anyway.
+// Usage:
+//
+//	renderer := engine.CreateRendererFS(templatesFS, func(r *core.Renderer) {
+//		r.Register("home", "templates/layout.html", "templates/home.html")
+//	}, template.FuncMap{})
+//	if err := renderer.Preload(); err != nil {
+//		panic(err)
+//	}
+func (r *Renderer) Preload() error {
+	names := make([]string, 0, len(r.registered))
+	for name := range r.registered {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		tpl, err := r.parseTemplate(name, r.registered[name]...)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cannot parse template %s: %w", name, err))
+			continue
+		}
+
+		r.Add(name, tpl)
+		r.storeTemplate(name, tpl)
+		delete(r.registered, name)
+	}
+
+	return errors.Join(errs...)
+}
+
+// parseTemplate parses template the same way as Push does, but returns error instead of panic.
+func (r *Renderer) parseTemplate(name string, files ...string) (*template.Template, error) {
+	if len(files) == 0 {
+		return nil, errors.New("no template files provided")
+	}
+
+	if r.TemplatesFS == nil {
+		return template.New(filepath.Base(files[0])).Funcs(r.FuncMap).ParseFiles(files...)
+	}
+
+	tpl := template.New(name).Funcs(r.FuncMap)
+	for _, file := range files {
+		data, err := fs.ReadFile(r.TemplatesFS, file)
+		if err != nil {
+			return nil, err
+		}
+		if tpl, err = tpl.Parse(string(data)); err != nil {
+			return nil, err
+		}
+	}
+
+	return tpl, nil
+}
+
 // addFromFS adds embedded template.
 func (r *Renderer) addFromFS(name string, funcMap template.FuncMap, files ...string) *template.Template {
 	filesData := make([]string, len(files))
@@ -95,3 +172,22 @@ func (r *Renderer) getTemplate(name string) *template.Template {
 
 	return nil
 }
+
+// swappableHTMLRender delegates rendering to the renderer which can be replaced at runtime.
+type swappableHTMLRender struct {
+	renderer render.HTMLRender
+	mu       sync.RWMutex
+}
+
+// Instance returns render.Render from the current renderer.
+func (s *swappableHTMLRender) Instance(name string, data any) render.Render {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.renderer.Instance(name, data)
+}
+
+func (s *swappableHTMLRender) set(renderer render.HTMLRender) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.renderer = renderer
+}
@@ -1,11 +1,13 @@
 package core
 
 import (
+	"bytes"
 	"fmt"
 	"html/template"
 	"os"
 	"path"
 	"testing"
+	"testing/fstest"
 
 	"github.com/gin-contrib/multitemplate"
 	"github.com/stretchr/testify/assert"
@@ -105,6 +107,48 @@ func (t *TemplateTest) Test_PushNewInstanceDynamic() {
 	assert.Equal(t.T(), 3, len(tpl.Templates()))
 }
 
+func (t *TemplateTest) Test_Preload() {
+	renderer := t.initDynamic()
+	renderer.Register("index", fmt.Sprintf(testTemplatesFile, 1), fmt.Sprintf(testTemplatesFile, 2))
+
+	require.NoError(t.T(), renderer.Preload())
+	tpl := renderer.getTemplate("index")
+	require.NotNil(t.T(), tpl)
+
+	var buf bytes.Buffer
+	require.NoError(t.T(), tpl.Execute(&buf, nil))
+	assert.Equal(t.T(), "data test ok", buf.String())
+}
+
+func TestTemplate_PreloadFS(t *testing.T) {
+	renderer := NewStaticRenderer(template.FuncMap{"trans": func(s string) string { return s }})
+	renderer.TemplatesFS = fstest.MapFS{
+		"layout.html":  {Data: []byte(`<b>{{template "body" .}}</b>`)},
+		"valid.html":   {Data: []byte(`{{define "body"}}{{trans .}}{{end}}`)},
+		"broken.html":  {Data: []byte(`{{define "body"}}{{trans .}`)},
+		"unknown.html": {Data: []byte(`{{define "body"}}{{unknown .}}{{end}}`)},
+	}
+	renderer.
+		Register("valid", "layout.html", "valid.html").
+		Register("broken", "layout.html", "broken.html").
+		Register("unknown", "layout.html", "unknown.html").
+		Register("missing", "layout.html", "missing.html")
+
+	err := renderer.Preload()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot parse template broken")
+	assert.Contains(t, err.Error(), "cannot parse template unknown")
+	assert.Contains(t, err.Error(), "cannot parse template missing")
+	assert.NotContains(t, err.Error(), "cannot parse template valid")
+
+	tpl := renderer.getTemplate("valid")
+	require.NotNil(t, tpl)
+	var buf bytes.Buffer
+	require.NoError(t, tpl.Execute(&buf, "text"))
+	assert.Equal(t, "<b>text</b>", buf.String())
+	assert.Nil(t, renderer.getTemplate("broken"))
+}
+
 func TestTemplate_NewRenderer(t *testing.T) {
 	r := NewRenderer(template.FuncMap{})
 	assert.NotNil(t, r)
@@ -0,0 +1,67 @@
+package util
+
+import (
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ValidationMessageIDPrefix is prepended to the validation tag to get the message ID, e.g. "validation_required".
+const ValidationMessageIDPrefix = "validation_"
+
+// FieldError contains localized validation error for the single field.
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// TemplateLocalizer is a part of core.LocalizerInterface which is used to localize binding errors.
+type TemplateLocalizer interface {
+	LocalizeTemplateMessage(string, map[string]interface{}) (string, error)
+}
+
+// FormatBindingError converts error returned by gin binding (ShouldBindJSON, BindJSONWithRaw, etc.) to the list of
+// localized per-field errors. Message ID for every field consists of ValidationMessageIDPrefix and validation tag.
+// Translations can use "Field" and "Param" template parameters, for example:
+//
+//	validation_required: "Field {{.Field}} is required"
+//	validation_min: "Field {{.Field}} must be at least {{.Param}}"
+//
+// Original validator message is used if translation is not found or localizer is nil. Errors other than
+// validator.ValidationErrors (e.g. malformed JSON) are returned as a single FieldError without field name.
+func FormatBindingError(err error, loc TemplateLocalizer) []FieldError {
+	if err == nil {
+		return nil
+	}
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return []FieldError{{Message: err.Error()}}
+	}
+
+	result := make([]FieldError, 0, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		result = append(result, FieldError{
+			Field:   fieldErr.Field(),
+			Message: localizeFieldError(fieldErr, loc),
+		})
+	}
+
+	return result
+}
+
+func localizeFieldError(fieldErr validator.FieldError, loc TemplateLocalizer) string {
+	if loc == nil {
+		return fieldErr.Error()
+	}
+
+	msg, err := loc.LocalizeTemplateMessage(ValidationMessageIDPrefix+fieldErr.Tag(), map[string]interface{}{
+		"Field": fieldErr.Field(),
+		"Param": fieldErr.Param(),
+	})
+	if err != nil {
+		return fieldErr.Error()
+	}
+
+	return msg
+}
@@ -0,0 +1,73 @@
+package util
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type bindingTestRequest struct {
+	ClientID string `json:"clientId" binding:"required"`
+	Name     string `json:"name" binding:"required,min=3"`
+	Email    string `json:"email" binding:"omitempty,email"`
+}
+
+type testTemplateLocalizer map[string]string
+
+func (l testTemplateLocalizer) LocalizeTemplateMessage(id string, data map[string]interface{}) (string, error) {
+	msg, ok := l[id]
+	if !ok {
+		return "", errors.New("message not found")
+	}
+
+	return strings.NewReplacer("{Field}", data["Field"].(string), "{Param}", data["Param"].(string)).Replace(msg), nil
+}
+
+func bindTestRequest(t *testing.T, body string) error {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+
+	var req bindingTestRequest
+	_, err := BindJSONWithRaw(c, &req)
+	require.Error(t, err)
+	return err
+}
+
+func TestFormatBindingError_Localized(t *testing.T) {
+	loc := testTemplateLocalizer{
+		"validation_required": "поле {Field} обязательно",
+		"validation_min":      "поле {Field} должно содержать минимум {Param} символа",
+	}
+
+	errs := FormatBindingError(bindTestRequest(t, `{"name": "ab"}`), loc)
+	assert.Equal(t, []FieldError{
+		{Field: "ClientID", Message: "поле ClientID обязательно"},
+		{Field: "Name", Message: "поле Name должно содержать минимум 3 символа"},
+	}, errs)
+}
+
+func TestFormatBindingError_Fallback(t *testing.T) {
+	err := bindTestRequest(t, `{"clientId": "id", "name": "name", "email": "invalid"}`)
+
+	errs := FormatBindingError(err, testTemplateLocalizer{})
+	require.Len(t, errs, 1)
+	assert.Equal(t, "Email", errs[0].Field)
+	assert.Contains(t, errs[0].Message, "'email' tag")
+
+	assert.Equal(t, errs, FormatBindingError(err, nil))
+}
+
+func TestFormatBindingError_NotValidationError(t *testing.T) {
+	errs := FormatBindingError(bindTestRequest(t, `{`), nil)
+	require.Len(t, errs, 1)
+	assert.Empty(t, errs[0].Field)
+	assert.NotEmpty(t, errs[0].Message)
+
+	assert.Nil(t, FormatBindingError(nil, nil))
+}
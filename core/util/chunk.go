@@ -0,0 +1,28 @@
+package util
+
+// Chunk splits items into chunks of the provided size. The last chunk contains the rest of the items.
+// All items are returned as a single chunk if size is zero or negative, nil is returned for empty items.
+// Chunks share the underlying array with items, but appending to a chunk won't overwrite the next one.
+//
+// Usage:
+//
+//	for _, chunk := range util.Chunk(orders, 50) {
+//		if _, _, err := client.OrdersUpload(chunk); err != nil {
+//			return err
+//		}
+//	}
+func Chunk[T any](items []T, size int) [][]T {
+	if len(items) == 0 {
+		return nil
+	}
+	if size <= 0 || size >= len(items) {
+		return [][]T{items[:len(items):len(items)]}
+	}
+
+	chunks := make([][]T, 0, (len(items)+size-1)/size)
+	for start := 0; start < len(items); start += size {
+		end := min(start+size, len(items))
+		chunks = append(chunks, items[start:end:end])
+	}
+	return chunks
+}
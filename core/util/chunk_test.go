@@ -0,0 +1,30 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChunk(t *testing.T) {
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5, 6}}, Chunk([]int{1, 2, 3, 4, 5, 6}, 2))
+	assert.Equal(t, [][]int{{1, 2, 3}, {4, 5}}, Chunk([]int{1, 2, 3, 4, 5}, 3))
+	assert.Equal(t, [][]int{{1, 2}}, Chunk([]int{1, 2}, 10))
+	assert.Equal(t, [][]string{{"a"}, {"b"}}, Chunk([]string{"a", "b"}, 1))
+}
+
+func TestChunk_Empty(t *testing.T) {
+	assert.Nil(t, Chunk([]int{}, 2))
+	assert.Nil(t, Chunk[int](nil, 2))
+}
+
+func TestChunk_InvalidSize(t *testing.T) {
+	assert.Equal(t, [][]int{{1, 2, 3}}, Chunk([]int{1, 2, 3}, 0))
+	assert.Equal(t, [][]int{{1, 2, 3}}, Chunk([]int{1, 2, 3}, -1))
+}
+
+func TestChunk_Append(t *testing.T) {
+	chunks := Chunk([]int{1, 2, 3, 4}, 2)
+	_ = append(chunks[0], 10)
+	assert.Equal(t, []int{3, 4}, chunks[1])
+}
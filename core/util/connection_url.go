@@ -0,0 +1,37 @@
+package util
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+)
+
+// BuildConnectionURL appends connection ID to the base URL path and adds provided query parameters.
+// Existing path and query parameters of the base URL are preserved, parameters from params replace them.
+// Path segments and parameters are escaped, so they can contain any characters.
+// Usage:
+//
+//	link, err := util.BuildConnectionURL("https://transport.example.com/settings", conn.ID, map[string]string{
+//		"lang": "ru",
+//	})
+//	// link == "https://transport.example.com/settings/1?lang=ru"
+func BuildConnectionURL(base string, connID int, params map[string]string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", errors.New("base URL must be absolute")
+	}
+
+	u = u.JoinPath(strconv.Itoa(connID))
+	if len(params) > 0 {
+		query := u.Query()
+		for key, value := range params {
+			query.Set(key, value)
+		}
+		u.RawQuery = query.Encode()
+	}
+
+	return u.String(), nil
+}
@@ -0,0 +1,46 @@
+package util
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildConnectionURL(t *testing.T) {
+	link, err := BuildConnectionURL("https://transport.example.com/settings/", 12, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "https://transport.example.com/settings/12", link)
+
+	link, err = BuildConnectionURL("https://transport.example.com", 1, map[string]string{"lang": "ru"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://transport.example.com/1?lang=ru", link)
+}
+
+func TestBuildConnectionURL_Escaping(t *testing.T) {
+	params := map[string]string{
+		"account": "https://demo.retailcrm.ru/?a=1&b=2",
+		"name":    "Test #1 / 100%",
+		"q&x":     "a=b",
+	}
+	link, err := BuildConnectionURL("https://transport.example.com/my settings?lang=en", 5, params)
+	require.NoError(t, err)
+	assert.Equal(t, "https://transport.example.com/my%20settings/5?account=https%3A%2F%2Fdemo.retailcrm.ru%2F%3Fa%3D1%26b%3D2"+
+		"&lang=en&name=Test+%231+%2F+100%25&q%26x=a%3Db", link)
+
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	assert.Equal(t, "/my settings/5", parsed.Path)
+	for key, value := range params {
+		assert.Equal(t, value, parsed.Query().Get(key))
+	}
+}
+
+func TestBuildConnectionURL_Invalid(t *testing.T) {
+	_, err := BuildConnectionURL("://invalid", 1, nil)
+	assert.Error(t, err)
+
+	_, err = BuildConnectionURL("/settings", 1, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,82 @@
+package util
+
+import (
+	"sync"
+	"time"
+)
+
+// Debouncer coalesces rapid events with the same key. Function passed to Trigger is called only after
+// the quiet period (no new triggers for the same key during the window). Only the last function is called.
+// Different keys are processed independently. Functions are called in the separate goroutines.
+//
+// Usage:
+//
+//	debouncer := util.NewDebouncer[int](time.Second)
+//	// Webhooks for the same connection will be processed once per quiet period.
+//	debouncer.Trigger(conn.ID, func() {
+//		syncConnection(conn)
+//	})
+type Debouncer[K comparable] struct {
+	pending map[K]*debounceCall
+	window  time.Duration
+	mu      sync.Mutex
+}
+
+type debounceCall struct {
+	timer *time.Timer
+}
+
+// NewDebouncer returns Debouncer with provided quiet period.
+func NewDebouncer[K comparable](window time.Duration) *Debouncer[K] {
+	return &Debouncer[K]{
+		pending: make(map[K]*debounceCall),
+		window:  window,
+	}
+}
+
+// Trigger schedules fn for the key. Previously scheduled function for the same key is discarded and
+// the quiet period is restarted.
+func (d *Debouncer[K]) Trigger(key K, fn func()) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if call, ok := d.pending[key]; ok {
+		call.timer.Stop()
+	}
+
+	call := &debounceCall{}
+	call.timer = time.AfterFunc(d.window, func() {
+		d.mu.Lock()
+		if d.pending[key] != call {
+			d.mu.Unlock()
+			return
+		}
+		delete(d.pending, key)
+		d.mu.Unlock()
+
+		fn()
+	})
+	d.pending[key] = call
+}
+
+// Cancel discards scheduled function for the key. It returns false if there was nothing to cancel.
+func (d *Debouncer[K]) Cancel(key K) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	call, ok := d.pending[key]
+	if !ok {
+		return false
+	}
+
+	call.timer.Stop()
+	delete(d.pending, key)
+	return true
+}
+
+// Pending returns number of the keys with scheduled functions.
+func (d *Debouncer[K]) Pending() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.pending)
+}
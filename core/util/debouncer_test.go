@@ -0,0 +1,69 @@
+package util
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebouncer_CollapsesTriggers(t *testing.T) {
+	var calls, last atomic.Int32
+	d := NewDebouncer[int](30 * time.Millisecond)
+
+	for i := 1; i <= 5; i++ {
+		i := i
+		d.Trigger(1, func() {
+			calls.Add(1)
+			last.Store(int32(i))
+		})
+		time.Sleep(5 * time.Millisecond)
+	}
+	assert.Equal(t, 1, d.Pending())
+
+	assert.Eventually(t, func() bool {
+		return calls.Load() == 1
+	}, time.Second, 5*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, int32(5), last.Load())
+	assert.Equal(t, 0, d.Pending())
+}
+
+func TestDebouncer_IndependentKeys(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		calls = map[string]int{}
+	)
+	d := NewDebouncer[string](20 * time.Millisecond)
+
+	for _, key := range []string{"a", "b", "a", "c", "b"} {
+		key := key
+		d.Trigger(key, func() {
+			mu.Lock()
+			defer mu.Unlock()
+			calls[key]++
+		})
+	}
+
+	assert.Eventually(t, func() bool {
+		return d.Pending() == 0
+	}, time.Second, 5*time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, map[string]int{"a": 1, "b": 1, "c": 1}, calls)
+}
+
+func TestDebouncer_Cancel(t *testing.T) {
+	var calls atomic.Int32
+	d := NewDebouncer[int](20 * time.Millisecond)
+
+	d.Trigger(1, func() { calls.Add(1) })
+	assert.True(t, d.Cancel(1))
+	assert.False(t, d.Cancel(1))
+
+	time.Sleep(40 * time.Millisecond)
+	assert.Equal(t, int32(0), calls.Load())
+}
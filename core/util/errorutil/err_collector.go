@@ -1,6 +1,7 @@
 package errorutil
 
 import (
+	"errors"
 	"fmt"
 	"runtime"
 	"strings"
@@ -70,6 +71,31 @@ func Collect(errs ...error) error {
 	return NewCollector().Do(errs...).AsError()
 }
 
+// CollectWithContext prefixes every non-nil error with provided prefix and joins them using errors.Join.
+// Unlike Collect, the result can be inspected using errors.Is and errors.As. Returns nil if there are no errors.
+//
+// Example:
+//
+//	err := errorutil.CollectWithContext("cannot shutdown", file.Close(), log.Sync())
+//	if errors.Is(err, os.ErrClosed) {
+//	    // ...
+//	}
+func CollectWithContext(prefix string, errs ...error) error {
+	wrapped := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if prefix == "" {
+			wrapped = append(wrapped, err)
+			continue
+		}
+		wrapped = append(wrapped, fmt.Errorf("%s: %w", prefix, err))
+	}
+
+	return errors.Join(wrapped...)
+}
+
 // Do some operation that returns the error. Supports multiple operations at once.
 func (e *Collector) Do(errs ...error) *Collector {
 	pc, file, line, _ := runtime.Caller(1)
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
@@ -29,6 +30,31 @@ func TestCollect_Error(t *testing.T) {
 	require.Error(t, Collect(errors.New("first"), errors.New("second")))
 }
 
+func TestCollectWithContext_NoError(t *testing.T) {
+	require.NoError(t, CollectWithContext("prefix"))
+	require.NoError(t, CollectWithContext("prefix", nil, nil))
+}
+
+func TestCollectWithContext_Error(t *testing.T) {
+	first := errors.New("first")
+	second := errors.New("second")
+	err := CollectWithContext("close", first, nil, second)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, first)
+	assert.ErrorIs(t, err, second)
+	assert.Equal(t, "close: first\nclose: second", err.Error())
+}
+
+func TestCollectWithContext_EmptyPrefix(t *testing.T) {
+	first := errors.New("first")
+	err := CollectWithContext("", first)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, first)
+	assert.Equal(t, "first", err.Error())
+}
+
 func (t *ErrCollectorTest) SetupTest() {
 	t.c = NewCollector()
 }
@@ -22,6 +22,14 @@ func GetErrorResponse(statusCode int, err string) (int, interface{}) {
 	}
 }
 
+// Error returns ErrorResponse with arbitrary status code. It's the same as GetErrorResponse.
+// Usage (with gin):
+//
+//	context.JSON(Error(http.StatusConflict, "already exists"))
+func Error(status int, msg string) (int, interface{}) {
+	return GetErrorResponse(status, msg)
+}
+
 // BadRequest returns ErrorResponse with code 400
 // Usage (with gin):
 //
@@ -53,3 +61,27 @@ func Forbidden(err string) (int, interface{}) {
 func InternalServerError(err string) (int, interface{}) {
 	return GetErrorResponse(http.StatusInternalServerError, err)
 }
+
+// Conflict returns ErrorResponse with code 409
+// Usage (with gin):
+//
+//	context.JSON(Conflict("already exists"))
+func Conflict(err string) (int, interface{}) {
+	return GetErrorResponse(http.StatusConflict, err)
+}
+
+// UnprocessableEntity returns ErrorResponse with code 422
+// Usage (with gin):
+//
+//	context.JSON(UnprocessableEntity("invalid state"))
+func UnprocessableEntity(err string) (int, interface{}) {
+	return GetErrorResponse(http.StatusUnprocessableEntity, err)
+}
+
+// TooManyRequests returns ErrorResponse with code 429
+// Usage (with gin):
+//
+//	context.JSON(TooManyRequests("rate limit exceeded"))
+func TooManyRequests(err string) (int, interface{}) {
+	return GetErrorResponse(http.StatusTooManyRequests, err)
+}
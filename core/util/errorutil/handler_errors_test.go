@@ -27,3 +27,23 @@ func TestError_InternalServerError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, code)
 	assert.Equal(t, "error string", resp.(Response).Error)
 }
+
+func TestError_Error(t *testing.T) {
+	code, resp := Error(http.StatusPaymentRequired, "error string")
+
+	assert.Equal(t, http.StatusPaymentRequired, code)
+	assert.Equal(t, "error string", resp.(Response).Error)
+}
+
+func TestError_CustomStatuses(t *testing.T) {
+	for status, fn := range map[int]func(string) (int, interface{}){
+		http.StatusConflict:            Conflict,
+		http.StatusUnprocessableEntity: UnprocessableEntity,
+		http.StatusTooManyRequests:     TooManyRequests,
+	} {
+		code, resp := fn("error string")
+
+		assert.Equal(t, status, code)
+		assert.Equal(t, "error string", resp.(Response).Error)
+	}
+}
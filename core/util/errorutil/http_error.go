@@ -0,0 +1,42 @@
+package errorutil
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// HTTPError is an error which contains HTTP status code. Sentry recovery middleware responds with its status and
+// message if handler panics with HTTPError. Such panics are sent to Sentry only if status is 5xx.
+// Usage:
+//
+//	panic(errorutil.NewHTTPError(http.StatusBadRequest, "invalid payload"))
+type HTTPError struct {
+	Status  int
+	Message string
+}
+
+// NewHTTPError returns new HTTPError with provided status and message.
+func NewHTTPError(status int, message string) *HTTPError {
+	return &HTTPError{Status: status, Message: message}
+}
+
+// Error returns error message with the status code.
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.Status, http.StatusText(e.Status), e.Message)
+}
+
+// IsServerError returns true if error status is 5xx.
+func (e *HTTPError) IsServerError() bool {
+	return e.Status >= http.StatusInternalServerError
+}
+
+// IsClientError returns true if err wraps HTTPError with 4xx status. Such errors are caused by the upstream
+// (e.g. invalid request from the CRM) rather than by the transport itself.
+func IsClientError(err error) bool {
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		return false
+	}
+	return httpErr.Status >= http.StatusBadRequest && !httpErr.IsServerError()
+}
@@ -0,0 +1,25 @@
+package errorutil
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPError(t *testing.T) {
+	err := NewHTTPError(http.StatusBadRequest, "invalid payload")
+	assert.Equal(t, "400 Bad Request: invalid payload", err.Error())
+	assert.False(t, err.IsServerError())
+	assert.True(t, NewHTTPError(http.StatusBadGateway, "upstream error").IsServerError())
+}
+
+func TestIsClientError(t *testing.T) {
+	assert.True(t, IsClientError(NewHTTPError(http.StatusNotFound, "not found")))
+	assert.True(t, IsClientError(fmt.Errorf("cannot send message: %w", NewHTTPError(http.StatusBadRequest, "invalid"))))
+	assert.False(t, IsClientError(NewHTTPError(http.StatusBadGateway, "upstream error")))
+	assert.False(t, IsClientError(errors.New("unknown error")))
+	assert.False(t, IsClientError(nil))
+}
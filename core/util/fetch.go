@@ -0,0 +1,87 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// DefaultAvatarMaxSize is the maximum size of the user avatar which can be uploaded by Utils.UploadUserAvatar.
+const DefaultAvatarMaxSize = 10 << 20
+
+var (
+	// ErrRemoteFileTooLarge is returned if the remote file exceeds the size limit.
+	ErrRemoteFileTooLarge = errors.New("remote file is too large")
+	// ErrRemoteContentType is returned if the remote file has a content type which is not allowed.
+	ErrRemoteContentType = errors.New("remote file content type is not allowed")
+)
+
+// AvatarContentTypes is the list of the content types which are accepted by Utils.UploadUserAvatar.
+var AvatarContentTypes = []string{"image/*"}
+
+// FetchRemote downloads file from the provided URL. It returns file body and its media type.
+// Content type of the response must match one of the allowed types, wildcards like "image/*" are supported.
+// Any content type is accepted if allowedTypes is empty. Responses with Content-Length greater than maxBytes
+// are rejected immediately, otherwise reading from the returned body will fail after maxBytes with
+// *http.MaxBytesError. The caller must close the returned body.
+func FetchRemote(
+	ctx context.Context, url string, maxBytes int64, allowedTypes []string) (io.ReadCloser, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		_ = resp.Body.Close()
+		return nil, "", fmt.Errorf("get: %v code: %v", url, resp.StatusCode)
+	}
+
+	if maxBytes > 0 && resp.ContentLength > maxBytes {
+		_ = resp.Body.Close()
+		return nil, "", fmt.Errorf("%w: %d bytes, limit is %d bytes", ErrRemoteFileTooLarge, resp.ContentLength, maxBytes)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil && len(allowedTypes) > 0 {
+		_ = resp.Body.Close()
+		return nil, "", fmt.Errorf("%w: %s", ErrRemoteContentType, resp.Header.Get("Content-Type"))
+	}
+	if !isContentTypeAllowed(mediaType, allowedTypes) {
+		_ = resp.Body.Close()
+		return nil, "", fmt.Errorf("%w: %s", ErrRemoteContentType, mediaType)
+	}
+
+	body := resp.Body
+	if maxBytes > 0 {
+		body = http.MaxBytesReader(nil, resp.Body, maxBytes)
+	}
+
+	return body, mediaType, nil
+}
+
+func isContentTypeAllowed(mediaType string, allowedTypes []string) bool {
+	if len(allowedTypes) == 0 {
+		return true
+	}
+
+	for _, allowed := range allowedTypes {
+		allowed = strings.ToLower(allowed)
+		if allowed == mediaType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+
+	return false
+}
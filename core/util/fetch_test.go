@@ -0,0 +1,104 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchRemote_OK(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://example.com").
+		Get("/image.png").
+		Reply(http.StatusOK).
+		SetHeader("Content-Type", "image/png").
+		BodyString("image")
+
+	body, contentType, err := FetchRemote(context.Background(), "https://example.com/image.png", 10, []string{"image/*"})
+	require.NoError(t, err)
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, "image", string(data))
+	assert.Equal(t, "image/png", contentType)
+}
+
+func TestFetchRemote_ExactType(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://example.com").
+		Get("/file.pdf").
+		Reply(http.StatusOK).
+		SetHeader("Content-Type", "application/pdf; charset=binary").
+		BodyString("pdf")
+
+	body, contentType, err := FetchRemote(context.Background(), "https://example.com/file.pdf", 0,
+		[]string{"image/*", "application/pdf"})
+	require.NoError(t, err)
+	_ = body.Close()
+	assert.Equal(t, "application/pdf", contentType)
+}
+
+func TestFetchRemote_WrongType(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://example.com").
+		Get("/image.png").
+		Reply(http.StatusOK).
+		SetHeader("Content-Type", "text/html").
+		BodyString("<html></html>")
+
+	body, _, err := FetchRemote(context.Background(), "https://example.com/image.png", 1024, AvatarContentTypes)
+	assert.Nil(t, body)
+	assert.ErrorIs(t, err, ErrRemoteContentType)
+}
+
+func TestFetchRemote_TooLargeContentLength(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://example.com").
+		Get("/image.png").
+		Reply(http.StatusOK).
+		SetHeader("Content-Type", "image/png").
+		SetHeader("Content-Length", "100").
+		BodyString(strings.Repeat("a", 100))
+
+	body, _, err := FetchRemote(context.Background(), "https://example.com/image.png", 10, AvatarContentTypes)
+	assert.Nil(t, body)
+	assert.ErrorIs(t, err, ErrRemoteFileTooLarge)
+}
+
+func TestFetchRemote_TooLargeBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte(strings.Repeat("a", 50)))
+		w.(http.Flusher).Flush() // Body is sent without Content-Length.
+		_, _ = w.Write([]byte(strings.Repeat("a", 50)))
+	}))
+	defer srv.Close()
+
+	body, _, err := FetchRemote(context.Background(), srv.URL, 10, AvatarContentTypes)
+	require.NoError(t, err)
+	defer body.Close()
+
+	_, err = io.ReadAll(body)
+	var maxBytesErr *http.MaxBytesError
+	assert.True(t, errors.As(err, &maxBytesErr))
+}
+
+func TestFetchRemote_BadStatus(t *testing.T) {
+	defer gock.Off()
+	gock.New("https://example.com").
+		Get("/image.png").
+		Reply(http.StatusNotFound)
+
+	body, _, err := FetchRemote(context.Background(), "https://example.com/image.png", 10, nil)
+	assert.Nil(t, body)
+	assert.EqualError(t, err, "get: https://example.com/image.png code: 404")
+}
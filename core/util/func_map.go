@@ -0,0 +1,50 @@
+package util
+
+import (
+	"html/template"
+	"sort"
+	"strings"
+)
+
+// FuncMapCollisionError is returned by MergeFuncMaps if the same function name is defined in several maps.
+type FuncMapCollisionError struct {
+	// Names contains sorted names of the functions which were defined more than once.
+	Names []string
+}
+
+// Error returns error message with the collided names.
+func (e *FuncMapCollisionError) Error() string {
+	return "template functions are defined more than once: " + strings.Join(e.Names, ", ")
+}
+
+// MergeFuncMaps merges provided maps into the new one. If the same name is present in several maps, the function
+// from the last map wins and *FuncMapCollisionError with all collided names is returned along with the merged map.
+// Usage:
+//
+//	funcs, err := util.MergeFuncMaps(base, custom)
+//	if err != nil {
+//		return err // or ignore it if overriding is intended
+//	}
+func MergeFuncMaps(maps ...template.FuncMap) (template.FuncMap, error) {
+	merged := template.FuncMap{}
+	collided := map[string]struct{}{}
+	for _, funcs := range maps {
+		for name, fn := range funcs {
+			if _, ok := merged[name]; ok {
+				collided[name] = struct{}{}
+			}
+			merged[name] = fn
+		}
+	}
+
+	if len(collided) == 0 {
+		return merged, nil
+	}
+
+	names := make([]string, 0, len(collided))
+	for name := range collided {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return merged, &FuncMapCollisionError{Names: names}
+}
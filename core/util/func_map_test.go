@@ -0,0 +1,38 @@
+package util
+
+import (
+	"errors"
+	"html/template"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeFuncMaps(t *testing.T) {
+	merged, err := MergeFuncMaps(
+		template.FuncMap{"a": func() string { return "a" }},
+		template.FuncMap{"b": func() string { return "b" }},
+		nil,
+	)
+
+	require.NoError(t, err)
+	assert.Len(t, merged, 2)
+	assert.Equal(t, "a", merged["a"].(func() string)())
+	assert.Equal(t, "b", merged["b"].(func() string)())
+}
+
+func TestMergeFuncMaps_Collision(t *testing.T) {
+	merged, err := MergeFuncMaps(
+		template.FuncMap{"a": func() string { return "first" }, "b": func() string { return "b" }},
+		template.FuncMap{"a": func() string { return "second" }},
+		template.FuncMap{"a": func() string { return "third" }, "b": func() string { return "override" }},
+	)
+
+	var collision *FuncMapCollisionError
+	require.True(t, errors.As(err, &collision))
+	assert.Equal(t, []string{"a", "b"}, collision.Names)
+	assert.EqualError(t, err, "template functions are defined more than once: a, b")
+	assert.Equal(t, "third", merged["a"].(func() string)())
+	assert.Equal(t, "override", merged["b"].(func() string)())
+}
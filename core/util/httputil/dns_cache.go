@@ -0,0 +1,255 @@
+package httputil
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// DefaultDNSNegativeCacheTTL limits the time for which failed DNS lookups are cached.
+const DefaultDNSNegativeCacheTTL = 5 * time.Second
+
+// defaultFallbackDelay is used if dialer FallbackDelay is zero. It's the same as in the net package.
+const defaultFallbackDelay = 300 * time.Millisecond
+
+// DNSResolver resolves host to its IP addresses. *net.Resolver implements this interface.
+type DNSResolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// dnsCache caches resolved addresses for the TTL. Concurrent lookups of the same host are merged into one.
+// Failed lookups are cached too, but for no longer than DefaultDNSNegativeCacheTTL.
+type dnsCache struct {
+	resolver      DNSResolver
+	entries       map[string]*dnsCacheEntry
+	ttl           time.Duration
+	lookupTimeout time.Duration
+	mu            sync.Mutex
+}
+
+type dnsCacheEntry struct {
+	expires time.Time
+	err     error
+	ready   chan struct{}
+	addrs   []net.IPAddr
+}
+
+// newDNSCache creates DNS cache. net.DefaultResolver is used if resolver is nil.
+func newDNSCache(resolver DNSResolver, ttl, lookupTimeout time.Duration) *dnsCache {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	return &dnsCache{
+		resolver:      resolver,
+		entries:       make(map[string]*dnsCacheEntry),
+		ttl:           ttl,
+		lookupTimeout: lookupTimeout,
+	}
+}
+
+// lookup returns cached addresses of the host or resolves them if cache entry is missing or expired.
+func (c *dnsCache) lookup(ctx context.Context, host string) ([]net.IPAddr, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[host]
+	if !ok || c.isExpired(entry) {
+		entry = &dnsCacheEntry{ready: make(chan struct{})}
+		c.entries[host] = entry
+		c.mu.Unlock()
+		c.resolve(ctx, host, entry)
+	} else {
+		c.mu.Unlock()
+	}
+
+	// Completed lookup result is returned even if context is already canceled.
+	select {
+	case <-entry.ready:
+		return entry.addrs, entry.err
+	default:
+	}
+
+	select {
+	case <-entry.ready:
+		return entry.addrs, entry.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// isExpired returns true if lookup for the entry has finished and its TTL has passed. Must be called under lock.
+func (c *dnsCache) isExpired(entry *dnsCacheEntry) bool {
+	select {
+	case <-entry.ready:
+		return time.Now().After(entry.expires)
+	default:
+		return false
+	}
+}
+
+// resolve host and store the result in the entry. Lookup is not bound to the caller context cancellation
+// because its result is shared with the concurrent callers.
+func (c *dnsCache) resolve(ctx context.Context, host string, entry *dnsCacheEntry) {
+	lookupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.lookupTimeout)
+	defer cancel()
+
+	entry.addrs, entry.err = c.resolver.LookupIPAddr(lookupCtx, host)
+	if entry.err == nil && len(entry.addrs) == 0 {
+		entry.err = &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	ttl := c.ttl
+	if entry.err != nil && ttl > DefaultDNSNegativeCacheTTL {
+		ttl = DefaultDNSNegativeCacheTTL
+	}
+	entry.expires = time.Now().Add(ttl)
+	close(entry.ready)
+}
+
+// dial connects to the address using cached addresses of its host. IP addresses are dialed as is.
+// Addresses are dialed like net.Dialer does: if both IPv4 and IPv6 addresses are available, connection
+// to the second address family is attempted after the dialer FallbackDelay (Happy Eyeballs, RFC 6555).
+func (c *dnsCache) dial(ctx context.Context, dialer *net.Dialer, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || net.ParseIP(host) != nil {
+		return dialer.DialContext(ctx, network, addr)
+	}
+
+	addrs, err := c.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	var suitable []net.IPAddr
+	for _, ip := range addrs {
+		if isNetworkIP(network, ip.IP) {
+			suitable = append(suitable, ip)
+		}
+	}
+	if len(suitable) == 0 {
+		return nil, &net.AddrError{Err: "no suitable address found", Addr: host}
+	}
+
+	primaries, fallbacks := suitable, []net.IPAddr(nil)
+	if dialer.FallbackDelay >= 0 {
+		primaries, fallbacks = partitionIPAddrs(suitable)
+	}
+
+	return dialParallel(ctx, dialer, network, port, primaries, fallbacks)
+}
+
+// dialResult is a result of the dialSerial call which is made by dialParallel.
+type dialResult struct {
+	conn    net.Conn
+	err     error
+	primary bool
+}
+
+// dialParallel races connections to the primary and fallback addresses. Fallback addresses are dialed
+// after the dialer FallbackDelay (300ms by default) or immediately after the primary addresses have failed.
+func dialParallel(
+	ctx context.Context, dialer *net.Dialer, network, port string, primaries, fallbacks []net.IPAddr,
+) (net.Conn, error) {
+	if len(fallbacks) == 0 {
+		return dialSerial(ctx, dialer, network, port, primaries)
+	}
+
+	returned := make(chan struct{})
+	defer close(returned)
+
+	results := make(chan dialResult)
+	startRacer := func(ctx context.Context, primary bool) {
+		addrs := primaries
+		if !primary {
+			addrs = fallbacks
+		}
+		conn, err := dialSerial(ctx, dialer, network, port, addrs)
+		select {
+		case results <- dialResult{conn: conn, err: err, primary: primary}:
+		case <-returned:
+			if conn != nil {
+				_ = conn.Close()
+			}
+		}
+	}
+
+	primaryCtx, primaryCancel := context.WithCancel(ctx)
+	defer primaryCancel()
+	fallbackCtx, fallbackCancel := context.WithCancel(ctx)
+	defer fallbackCancel()
+	go startRacer(primaryCtx, true)
+
+	fallbackDelay := dialer.FallbackDelay
+	if fallbackDelay == 0 {
+		fallbackDelay = defaultFallbackDelay
+	}
+	fallbackTimer := time.NewTimer(fallbackDelay)
+	defer fallbackTimer.Stop()
+
+	var primaryErr, fallbackErr error
+	for {
+		select {
+		case <-fallbackTimer.C:
+			go startRacer(fallbackCtx, false)
+		case res := <-results:
+			if res.err == nil {
+				return res.conn, nil
+			}
+			if res.primary {
+				primaryErr = res.err
+			} else {
+				fallbackErr = res.err
+			}
+			if primaryErr != nil && fallbackErr != nil {
+				return nil, errors.Join(primaryErr, fallbackErr)
+			}
+			if res.primary && fallbackTimer.Stop() {
+				// Primary addresses have failed, fallback should be started without waiting for the timer.
+				fallbackTimer.Reset(0)
+			}
+		}
+	}
+}
+
+// dialSerial dials addresses in order until the connection succeeds.
+func dialSerial(ctx context.Context, dialer *net.Dialer, network, port string, addrs []net.IPAddr) (net.Conn, error) {
+	errs := make([]error, 0, len(addrs))
+	for _, ip := range addrs {
+		conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// partitionIPAddrs divides addresses into two lists. The first list contains addresses of the same family
+// as the first address, the second list contains the rest of the addresses. Order is preserved.
+func partitionIPAddrs(addrs []net.IPAddr) (primaries, fallbacks []net.IPAddr) {
+	isIPv4 := addrs[0].IP.To4() != nil
+	for _, addr := range addrs {
+		if (addr.IP.To4() != nil) == isIPv4 {
+			primaries = append(primaries, addr)
+		} else {
+			fallbacks = append(fallbacks, addr)
+		}
+	}
+	return primaries, fallbacks
+}
+
+// isNetworkIP returns false if IP cannot be used with tcp4 or tcp6 network.
+func isNetworkIP(network string, ip net.IP) bool {
+	switch network {
+	case "tcp4", "udp4":
+		return ip.To4() != nil
+	case "tcp6", "udp6":
+		return ip.To4() == nil
+	default:
+		return true
+	}
+}
@@ -0,0 +1,250 @@
+package httputil
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type dnsResolverMock struct {
+	hosts   map[string]string
+	delay   time.Duration
+	lookups atomic.Int32
+}
+
+func (r *dnsResolverMock) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	r.lookups.Add(1)
+	time.Sleep(r.delay)
+	if ip, ok := r.hosts[host]; ok {
+		return []net.IPAddr{{IP: net.ParseIP(ip)}}, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func newDNSTestServer(t *testing.T) (server *httptest.Server, port string) {
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.Host)
+	}))
+	t.Cleanup(server.Close)
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+	return server, port
+}
+
+func TestDNSCache_Dial(t *testing.T) {
+	_, port := newDNSTestServer(t)
+	resolver := &dnsResolverMock{hosts: map[string]string{"crm.example.com": "127.0.0.1"}}
+	cache := newDNSCache(resolver, 50*time.Millisecond, time.Second)
+	dialer := &net.Dialer{Timeout: time.Second}
+
+	for i := 0; i < 3; i++ {
+		conn, err := cache.dial(context.Background(), dialer, "tcp", net.JoinHostPort("crm.example.com", port))
+		require.NoError(t, err)
+		require.NoError(t, conn.Close())
+	}
+	assert.Equal(t, int32(1), resolver.lookups.Load())
+
+	time.Sleep(60 * time.Millisecond)
+	conn, err := cache.dial(context.Background(), dialer, "tcp", net.JoinHostPort("crm.example.com", port))
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+	assert.Equal(t, int32(2), resolver.lookups.Load())
+
+	conn, err = cache.dial(context.Background(), dialer, "tcp", net.JoinHostPort("127.0.0.1", port))
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+	assert.Equal(t, int32(2), resolver.lookups.Load())
+}
+
+func TestDNSCache_NegativeCaching(t *testing.T) {
+	resolver := &dnsResolverMock{}
+	cache := newDNSCache(resolver, time.Hour, time.Second)
+
+	for i := 0; i < 2; i++ {
+		_, err := cache.lookup(context.Background(), "missing.example.com")
+		var dnsErr *net.DNSError
+		require.True(t, errors.As(err, &dnsErr))
+		assert.True(t, dnsErr.IsNotFound)
+	}
+	assert.Equal(t, int32(1), resolver.lookups.Load())
+
+	cache.entries["missing.example.com"].expires = time.Now().Add(-time.Second)
+	_, err := cache.lookup(context.Background(), "missing.example.com")
+	require.Error(t, err)
+	assert.Equal(t, int32(2), resolver.lookups.Load())
+	assert.WithinDuration(t, time.Now().Add(DefaultDNSNegativeCacheTTL),
+		cache.entries["missing.example.com"].expires, time.Second)
+}
+
+func TestDNSCache_ConcurrentLookup(t *testing.T) {
+	resolver := &dnsResolverMock{
+		hosts: map[string]string{"crm.example.com": "127.0.0.1"},
+		delay: 20 * time.Millisecond,
+	}
+	cache := newDNSCache(resolver, time.Hour, time.Second)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			addrs, err := cache.lookup(context.Background(), "crm.example.com")
+			assert.NoError(t, err)
+			assert.Len(t, addrs, 1)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), resolver.lookups.Load())
+}
+
+func TestDNSCache_CanceledContext(t *testing.T) {
+	resolver := &dnsResolverMock{
+		hosts: map[string]string{"crm.example.com": "127.0.0.1"},
+		delay: 20 * time.Millisecond,
+	}
+	cache := newDNSCache(resolver, time.Hour, time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := cache.lookup(ctx, "crm.example.com")
+	require.NoError(t, err)
+
+	addrs, err := cache.lookup(context.Background(), "crm.example.com")
+	require.NoError(t, err)
+	assert.Len(t, addrs, 1)
+	assert.Equal(t, int32(1), resolver.lookups.Load())
+}
+
+func TestDNSCache_CanceledContextCached(t *testing.T) {
+	resolver := &dnsResolverMock{hosts: map[string]string{"crm.example.com": "127.0.0.1"}}
+	cache := newDNSCache(resolver, time.Hour, time.Second)
+
+	_, err := cache.lookup(context.Background(), "crm.example.com")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 100; i++ {
+		addrs, err := cache.lookup(ctx, "crm.example.com")
+		require.NoError(t, err)
+		assert.Equal(t, []net.IPAddr{{IP: net.ParseIP("127.0.0.1")}}, addrs)
+	}
+	assert.Equal(t, int32(1), resolver.lookups.Load())
+}
+
+type dnsResolverFunc func(ctx context.Context, host string) ([]net.IPAddr, error)
+
+func (f dnsResolverFunc) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	return f(ctx, host)
+}
+
+func TestDNSCache_DialFallback(t *testing.T) {
+	_, port := newDNSTestServer(t)
+	cache := newDNSCache(dnsResolverFunc(func(context.Context, string) ([]net.IPAddr, error) {
+		return []net.IPAddr{{IP: net.ParseIP("::1")}, {IP: net.ParseIP("127.0.0.1")}}, nil
+	}), time.Minute, time.Second)
+	newDialer := func(fallbackDelay time.Duration) *net.Dialer {
+		return &net.Dialer{
+			Timeout:       200 * time.Millisecond,
+			FallbackDelay: fallbackDelay,
+			// IPv6 address is unresponsive.
+			ControlContext: func(ctx context.Context, _, address string, _ syscall.RawConn) error {
+				if strings.HasPrefix(address, "[::1]") {
+					<-ctx.Done()
+					return ctx.Err()
+				}
+				return nil
+			},
+		}
+	}
+
+	for name, test := range map[string]struct {
+		fallbackDelay time.Duration
+		minElapsed    time.Duration
+		maxElapsed    time.Duration
+	}{
+		"happyEyeballs": {fallbackDelay: 20 * time.Millisecond, minElapsed: 20 * time.Millisecond, maxElapsed: 150 * time.Millisecond},
+		"serial":        {fallbackDelay: -1, minElapsed: 200 * time.Millisecond, maxElapsed: time.Second},
+	} {
+		t.Run(name, func(t *testing.T) {
+			started := time.Now()
+			conn, err := cache.dial(context.Background(), newDialer(test.fallbackDelay), "tcp",
+				net.JoinHostPort("crm.example.com", port))
+			elapsed := time.Since(started)
+			require.NoError(t, err)
+			require.NoError(t, conn.Close())
+
+			assert.Equal(t, "127.0.0.1", conn.RemoteAddr().(*net.TCPAddr).IP.String())
+			assert.GreaterOrEqual(t, elapsed, test.minElapsed)
+			assert.Less(t, elapsed, test.maxElapsed)
+		})
+	}
+}
+
+func TestDNSCache_DialFallback_AllFailed(t *testing.T) {
+	cache := newDNSCache(dnsResolverFunc(func(context.Context, string) ([]net.IPAddr, error) {
+		return []net.IPAddr{{IP: net.ParseIP("::1")}, {IP: net.ParseIP("127.0.0.1")}}, nil
+	}), time.Minute, time.Second)
+	dialer := &net.Dialer{
+		Timeout: time.Second,
+		ControlContext: func(context.Context, string, string, syscall.RawConn) error {
+			return errors.New("connection refused")
+		},
+	}
+
+	_, err := cache.dial(context.Background(), dialer, "tcp", "crm.example.com:80")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "[::1]:80")
+	assert.Contains(t, err.Error(), "127.0.0.1:80")
+}
+
+func TestHTTPClientBuilder_WithDNSCache(t *testing.T) {
+	_, port := newDNSTestServer(t)
+	resolver := &dnsResolverMock{hosts: map[string]string{
+		"crm.example.com":  "127.0.0.1",
+		"mock.example.com": "127.0.0.1",
+	}}
+	client, err := NewHTTPClientBuilder().
+		WithDNSCache(time.Minute).
+		SetDNSResolver(resolver).
+		SetProxy(nil).
+		Build()
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get("http://crm.example.com:" + port + "/")
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, "crm.example.com:"+port, string(body))
+		client.CloseIdleConnections()
+	}
+	assert.Equal(t, int32(1), resolver.lookups.Load())
+
+	client, err = NewHTTPClientBuilder().
+		WithDNSCache(time.Minute).
+		SetDNSResolver(resolver).
+		SetMockAddress("mock.example.com:" + port).
+		AddMockedDomain("crm.example.com").
+		Build()
+	require.NoError(t, err)
+
+	resp, err := client.Get("http://crm.example.com/")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(2), resolver.lookups.Load())
+}
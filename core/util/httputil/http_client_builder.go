@@ -9,6 +9,7 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"path"
 	"time"
 
 	"go.uber.org/zap"
@@ -25,6 +26,27 @@ const (
 	defaultMaxIdleConns          = 100
 )
 
+// DialPreference controls which IP versions are used by the HTTP client to connect to the hosts.
+type DialPreference uint8
+
+const (
+	// DialPreferenceAuto uses both IPv4 and IPv6. IPv4 connection is attempted after the FallbackDelay
+	// if IPv6 connection is not established yet (Happy Eyeballs).
+	DialPreferenceAuto DialPreference = iota
+	// DialPreferenceIPv4Only uses only IPv4 addresses. Useful for the networks with broken IPv6.
+	DialPreferenceIPv4Only
+	// DialPreferenceIPv6Only uses only IPv6 addresses.
+	DialPreferenceIPv6Only
+)
+
+// tlsVersions maps TLS versions from the config to the tls.Version* constants.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
 // DefaultClient stores original http.DefaultClient.
 var DefaultClient = http.DefaultClient
 
@@ -57,18 +79,26 @@ var DefaultTransport = http.DefaultTransport
 //		fmt.Print(err)
 //	}
 type HTTPClientBuilder struct {
-	logger        logger.Logger
-	httpClient    *http.Client
-	httpTransport *http.Transport
-	dialer        *net.Dialer
-	mockAddress   string
-	mockHost      string
-	mockPort      string
-	mockedDomains []string
-	timeout       time.Duration
-	tlsVersion    uint16
-	logging       bool
-	built         bool
+	logger         logger.Logger
+	httpClient     *http.Client
+	httpTransport  *http.Transport
+	dialer         *net.Dialer
+	dnsResolver    DNSResolver
+	dnsCache       *dnsCache
+	mockAddress    string
+	minTLSVersion  string
+	mockHost       string
+	mockPort       string
+	mockedDomains  []string
+	mockedPaths    []string
+	timeout        time.Duration
+	dnsCacheTTL    time.Duration
+	fallbackDelay  time.Duration
+	tlsVersion     uint16
+	dialPreference DialPreference
+	disableHTTP2   bool
+	logging        bool
+	built          bool
 }
 
 // NewHTTPClientBuilder returns HTTPClientBuilder with default values.
@@ -92,6 +122,7 @@ func NewHTTPClientBuilder() *HTTPClientBuilder {
 		timeout:       defaultDialerTimeout,
 		mockAddress:   "",
 		mockedDomains: []string{},
+		mockedPaths:   []string{},
 		logging:       false,
 	}
 }
@@ -131,6 +162,20 @@ func (b *HTTPClientBuilder) SetMockedDomains(domains []string) *HTTPClientBuilde
 	return b
 }
 
+// AddMockedPath adds new mocked path pattern. Only requests to the mocked domains with matching paths
+// will be redirected to the mock address if at least one path pattern is present. Pattern syntax is the same
+// as for path.Match, for example: "/api/v5/orders" or "/api/v5/orders/*".
+func (b *HTTPClientBuilder) AddMockedPath(pattern string) *HTTPClientBuilder {
+	b.mockedPaths = append(b.mockedPaths, pattern)
+	return b
+}
+
+// SetMockedPaths sets mocked path patterns from slice. See AddMockedPath for details.
+func (b *HTTPClientBuilder) SetMockedPaths(patterns []string) *HTTPClientBuilder {
+	b.mockedPaths = patterns
+	return b
+}
+
 // SetSSLVerification enables or disables SSL certificates verification in client.
 func (b *HTTPClientBuilder) SetSSLVerification(enabled bool) *HTTPClientBuilder {
 	if b.httpTransport.TLSClientConfig == nil {
@@ -151,6 +196,21 @@ func (b *HTTPClientBuilder) UseTLS10() *HTTPClientBuilder {
 	return b
 }
 
+// SetMinTLSVersion sets minimal supported TLS version: "1.0", "1.1", "1.2" or "1.3". Version is validated
+// in the Build, unknown version will result in error. It takes precedence over UseTLS10.
+func (b *HTTPClientBuilder) SetMinTLSVersion(version string) *HTTPClientBuilder {
+	b.minTLSVersion = version
+	return b
+}
+
+// SetForceHTTP2 enables or disables HTTP/2. HTTP/2 is enabled by default. Client will use only HTTP/1.1
+// if it's disabled, this is useful for the legacy proxies which break on HTTP/2.
+func (b *HTTPClientBuilder) SetForceHTTP2(enabled bool) *HTTPClientBuilder {
+	b.disableHTTP2 = !enabled
+	b.httpTransport.ForceAttemptHTTP2 = enabled
+	return b
+}
+
 // SetCertPool sets provided TLS certificates pool into the client.
 func (b *HTTPClientBuilder) SetCertPool(pool *x509.CertPool) *HTTPClientBuilder {
 	if b.httpTransport.TLSClientConfig == nil {
@@ -168,6 +228,32 @@ func (b *HTTPClientBuilder) SetLogging(flag bool) *HTTPClientBuilder {
 	return b
 }
 
+// WithDNSCache enables caching of the resolved host addresses for the provided TTL. Failed lookups are cached
+// for no longer than DefaultDNSNegativeCacheTTL. Mocked domains are replaced with the mock address before lookup.
+func (b *HTTPClientBuilder) WithDNSCache(ttl time.Duration) *HTTPClientBuilder {
+	b.dnsCacheTTL = ttl
+	return b
+}
+
+// SetDialPreference sets which IP versions will be used to connect to the hosts. DialPreferenceAuto is used by default.
+func (b *HTTPClientBuilder) SetDialPreference(pref DialPreference) *HTTPClientBuilder {
+	b.dialPreference = pref
+	return b
+}
+
+// SetFallbackDelay sets the delay before IPv4 connection is attempted if IPv6 connection is not established yet.
+// Zero value means default delay (300ms), negative value disables the fallback.
+func (b *HTTPClientBuilder) SetFallbackDelay(delay time.Duration) *HTTPClientBuilder {
+	b.fallbackDelay = delay
+	return b
+}
+
+// SetDNSResolver sets resolver which is used by the DNS cache. net.DefaultResolver is used by default.
+func (b *HTTPClientBuilder) SetDNSResolver(resolver DNSResolver) *HTTPClientBuilder {
+	b.dnsResolver = resolver
+	return b
+}
+
 func (b *HTTPClientBuilder) SetProxy(proxy func(*http.Request) (*url.URL, error)) *HTTPClientBuilder {
 	b.httpTransport.Proxy = proxy
 	return b
@@ -182,12 +268,21 @@ func (b *HTTPClientBuilder) FromConfig(config *config.HTTPClientConfig) *HTTPCli
 	if config.MockAddress != "" {
 		b.SetMockAddress(config.MockAddress)
 		b.SetMockedDomains(config.MockedDomains)
+		b.SetMockedPaths(config.MockedPaths)
 	}
 
 	if config.Timeout > 0 {
 		b.SetTimeout(config.Timeout)
 	}
 
+	if config.MinTLSVersion != "" {
+		b.SetMinTLSVersion(config.MinTLSVersion)
+	}
+
+	if config.DisableHTTP2 {
+		b.SetForceHTTP2(false)
+	}
+
 	b.SetSSLVerification(config.IsSSLVerificationEnabled())
 
 	return b
@@ -198,16 +293,92 @@ func (b *HTTPClientBuilder) baseTLSConfig() *tls.Config {
 	return &tls.Config{MinVersion: b.tlsVersion} // nolint:gosec
 }
 
+// buildTLSVersion applies minimal TLS version provided via SetMinTLSVersion.
+func (b *HTTPClientBuilder) buildTLSVersion() error {
+	if b.minTLSVersion == "" {
+		return nil
+	}
+
+	version, ok := tlsVersions[b.minTLSVersion]
+	if !ok {
+		return fmt.Errorf("unknown TLS version: %s", b.minTLSVersion)
+	}
+
+	b.tlsVersion = version
+	if b.httpTransport.TLSClientConfig == nil {
+		b.httpTransport.TLSClientConfig = b.baseTLSConfig()
+	}
+	b.httpTransport.TLSClientConfig.MinVersion = version
+
+	return nil
+}
+
+// buildHTTP2 disables HTTP/2 in the transport if necessary. Non-nil empty TLSNextProto prevents HTTP/2 upgrade,
+// "h2" is removed from the ALPN protocols.
+func (b *HTTPClientBuilder) buildHTTP2() *HTTPClientBuilder {
+	if !b.disableHTTP2 {
+		return b
+	}
+
+	b.httpTransport.ForceAttemptHTTP2 = false
+	b.httpTransport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	if b.httpTransport.TLSClientConfig != nil {
+		b.httpTransport.TLSClientConfig.NextProtos = nil
+	}
+
+	return b
+}
+
 // buildDialer initializes dialer with provided timeout.
 func (b *HTTPClientBuilder) buildDialer() *HTTPClientBuilder {
 	b.dialer = &net.Dialer{
-		Timeout:   b.timeout,
-		KeepAlive: b.timeout,
+		Timeout:       b.timeout,
+		KeepAlive:     b.timeout,
+		FallbackDelay: b.fallbackDelay,
+	}
+	if b.dialPreference != DialPreferenceAuto || b.fallbackDelay != 0 {
+		b.httpTransport.DialContext = b.dial
 	}
 
 	return b
 }
 
+// buildDNSCache initializes DNS cache if it's enabled. Dialer must be built first.
+func (b *HTTPClientBuilder) buildDNSCache() *HTTPClientBuilder {
+	if b.dnsCacheTTL > 0 {
+		b.dnsCache = newDNSCache(b.dnsResolver, b.dnsCacheTTL, b.timeout)
+		b.httpTransport.DialContext = b.dial
+	}
+
+	return b
+}
+
+// dial connects to the address using DNS cache if it's enabled. Network is restricted by the dial preference.
+func (b *HTTPClientBuilder) dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	network = b.dialNetwork(network)
+	if b.dnsCache != nil {
+		return b.dnsCache.dial(ctx, b.dialer, network, addr)
+	}
+
+	return b.dialer.DialContext(ctx, network, addr)
+}
+
+// dialNetwork returns network restricted to the IP version from the dial preference.
+func (b *HTTPClientBuilder) dialNetwork(network string) string {
+	if network != "tcp" && network != "udp" {
+		return network
+	}
+
+	switch b.dialPreference {
+	case DialPreferenceIPv4Only:
+		return network + "4"
+	case DialPreferenceIPv6Only:
+		return network + "6"
+	default:
+		return network
+	}
+}
+
 // parseAddress parses address and returns error in case of error (port is necessary).
 func (b *HTTPClientBuilder) parseAddress() error {
 	if b.mockAddress == "" {
@@ -238,6 +409,16 @@ func (b *HTTPClientBuilder) buildMocks() error {
 			b.log(fmt.Sprintf(" - %s\n", domain))
 		}
 
+		if len(b.mockedPaths) > 0 {
+			b.log("Mocked paths: ")
+
+			for _, pattern := range b.mockedPaths {
+				b.log(fmt.Sprintf(" - %s\n", pattern))
+			}
+
+			return nil
+		}
+
 		b.httpTransport.Proxy = nil
 		b.httpTransport.DialContext = func(ctx context.Context, network, addr string) (conn net.Conn, e error) {
 			var (
@@ -246,7 +427,7 @@ func (b *HTTPClientBuilder) buildMocks() error {
 				err  error
 			)
 			if host, port, err = net.SplitHostPort(addr); err != nil {
-				return b.dialer.DialContext(ctx, network, addr)
+				return b.dial(ctx, network, addr)
 			}
 
 			for _, mock := range b.mockedDomains {
@@ -263,13 +444,76 @@ func (b *HTTPClientBuilder) buildMocks() error {
 				}
 			}
 
-			return b.dialer.DialContext(ctx, network, addr)
+			return b.dial(ctx, network, addr)
 		}
 	}
 
 	return nil
 }
 
+// isMockedDomain returns true if provided host is present in the mocked domains.
+func (b *HTTPClientBuilder) isMockedDomain(host string) bool {
+	for _, mock := range b.mockedDomains {
+		if mock == host {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isMockedPath returns true if provided path matches any of the mocked path patterns.
+func (b *HTTPClientBuilder) isMockedPath(urlPath string) bool {
+	for _, pattern := range b.mockedPaths {
+		if ok, err := path.Match(pattern, urlPath); err == nil && ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mockRoundTripper redirects requests to the mocked paths of the mocked domains to the mock address.
+// Other requests are sent to the real hosts.
+type mockRoundTripper struct {
+	base    http.RoundTripper
+	builder *HTTPClientBuilder
+}
+
+// RoundTrip implements http.RoundTripper.
+func (m *mockRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !m.builder.isMockedDomain(req.URL.Hostname()) || !m.builder.isMockedPath(req.URL.Path) {
+		return m.base.RoundTrip(req)
+	}
+
+	port := m.builder.mockPort
+	if port == "0" {
+		port = req.URL.Port()
+		if port == "" && req.URL.Scheme == "https" {
+			port = "443"
+		} else if port == "" {
+			port = "80"
+		}
+	}
+
+	mocked := req.Clone(req.Context())
+	mocked.URL.Host = net.JoinHostPort(m.builder.mockHost, port)
+	if mocked.Host == "" {
+		mocked.Host = req.URL.Host
+	}
+
+	m.builder.log(fmt.Sprintf("Mocking \"%s\" with \"%s\"\n", req.URL.String(), mocked.URL.String()))
+
+	return m.base.RoundTrip(mocked)
+}
+
+// CloseIdleConnections closes idle connections of the base transport.
+func (m *mockRoundTripper) CloseIdleConnections() {
+	if closer, ok := m.base.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
 // log prints logs via Engine or via fmt.Println.
 func (b *HTTPClientBuilder) log(msg string, args ...interface{}) {
 	if b.logging {
@@ -285,7 +529,7 @@ func (b *HTTPClientBuilder) log(msg string, args ...interface{}) {
 func (b *HTTPClientBuilder) ReplaceDefault() *HTTPClientBuilder {
 	if b.built {
 		http.DefaultClient = b.httpClient
-		http.DefaultTransport = b.httpTransport
+		http.DefaultTransport = b.httpClient.Transport
 	}
 
 	return b
@@ -301,7 +545,11 @@ func (b *HTTPClientBuilder) RestoreDefault() *HTTPClientBuilder {
 
 // Build builds client, pass true to replace http.DefaultClient with generated one.
 func (b *HTTPClientBuilder) Build(replaceDefault ...bool) (*http.Client, error) {
-	if err := b.buildDialer().parseAddress(); err != nil {
+	if err := b.buildDialer().buildDNSCache().buildHTTP2().parseAddress(); err != nil {
+		return nil, err
+	}
+
+	if err := b.buildTLSVersion(); err != nil {
 		return nil, err
 	}
 
@@ -312,6 +560,10 @@ func (b *HTTPClientBuilder) Build(replaceDefault ...bool) (*http.Client, error)
 	b.built = true
 	b.httpClient.Transport = b.httpTransport
 
+	if b.mockHost != "" && b.mockPort != "" && len(b.mockedDomains) > 0 && len(b.mockedPaths) > 0 {
+		b.httpClient.Transport = &mockRoundTripper{base: b.httpTransport, builder: b}
+	}
+
 	if len(replaceDefault) > 0 && replaceDefault[0] {
 		b.ReplaceDefault()
 	}
@@ -10,6 +10,8 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"strings"
 	"testing"
@@ -73,6 +75,58 @@ func (t *HTTPClientBuilderTest) Test_SetMockedDomains() {
 	assert.Equal(t.T(), domains[0], t.builder.mockedDomains[0])
 }
 
+func (t *HTTPClientBuilderTest) Test_AddMockedPath() {
+	builder := NewHTTPClientBuilder().AddMockedPath("/api/v5/orders")
+
+	assert.Equal(t.T(), []string{"/api/v5/orders"}, builder.mockedPaths)
+}
+
+func (t *HTTPClientBuilderTest) Test_SetMockedPaths() {
+	builder := NewHTTPClientBuilder().SetMockedPaths([]string{"/api/v5/*"})
+
+	assert.Equal(t.T(), []string{"/api/v5/*"}, builder.mockedPaths)
+}
+
+func (t *HTTPClientBuilderTest) Test_ClientPathMocksWorking() {
+	realSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "real")
+	}))
+	defer realSrv.Close()
+
+	mockSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "mock "+r.URL.Path)
+	}))
+	defer mockSrv.Close()
+
+	realURL, err := url.Parse(realSrv.URL)
+	t.Require().NoError(err)
+	mockURL, err := url.Parse(mockSrv.URL)
+	t.Require().NoError(err)
+
+	client, err := NewHTTPClientBuilder().
+		SetMockAddress(mockURL.Host).
+		AddMockedDomain(realURL.Hostname()).
+		AddMockedPath("/api/v5/orders").
+		AddMockedPath("/api/v5/orders/*").
+		Build()
+	t.Require().NoError(err)
+
+	get := func(path string) string {
+		resp, err := client.Get(realSrv.URL + path)
+		t.Require().NoError(err)
+		defer resp.Body.Close()
+
+		data, err := io.ReadAll(resp.Body)
+		t.Require().NoError(err)
+		return string(data)
+	}
+
+	t.Assert().Equal("mock /api/v5/orders", get("/api/v5/orders"))
+	t.Assert().Equal("mock /api/v5/orders/1", get("/api/v5/orders/1?by=id"))
+	t.Assert().Equal("real", get("/api/v5/customers"))
+	t.Assert().Equal("real", get("/api/v5/orders/1/edit"))
+}
+
 func (t *HTTPClientBuilderTest) Test_SetSSLVerification() {
 	t.builder.SetSSLVerification(true)
 	assert.False(t.T(), t.builder.httpTransport.TLSClientConfig.InsecureSkipVerify)
@@ -109,6 +163,7 @@ func (t *HTTPClientBuilderTest) Test_FromConfig() {
 		SSLVerification: boolPtr(true),
 		MockAddress:     "anothermock.local:3004",
 		MockedDomains:   []string{"example.gov"},
+		MockedPaths:     []string{"/api/*"},
 		Timeout:         60,
 	}
 
@@ -116,6 +171,7 @@ func (t *HTTPClientBuilderTest) Test_FromConfig() {
 	assert.Equal(t.T(), !config.IsSSLVerificationEnabled(), t.builder.httpTransport.TLSClientConfig.InsecureSkipVerify)
 	assert.Equal(t.T(), config.MockAddress, t.builder.mockAddress)
 	assert.Equal(t.T(), config.MockedDomains[0], t.builder.mockedDomains[0])
+	assert.Equal(t.T(), config.MockedPaths, t.builder.mockedPaths)
 	assert.Equal(t.T(), config.Timeout*time.Second, t.builder.timeout)
 	assert.Equal(t.T(), config.Timeout*time.Second, t.builder.httpClient.Timeout)
 }
@@ -126,6 +182,53 @@ func (t *HTTPClientBuilderTest) Test_buildDialer() {
 	assert.NotNil(t.T(), t.builder.dialer)
 }
 
+func (t *HTTPClientBuilderTest) Test_SetDialPreference() {
+	builder := NewHTTPClientBuilder().SetDialPreference(DialPreferenceIPv4Only).SetFallbackDelay(time.Second)
+	_, err := builder.Build()
+	t.Require().NoError(err)
+
+	t.Assert().Equal(time.Second, builder.dialer.FallbackDelay)
+	t.Assert().Equal("tcp4", builder.dialNetwork("tcp"))
+	t.Assert().Equal("udp4", builder.dialNetwork("udp"))
+	t.Assert().Equal("tcp6", builder.dialNetwork("tcp6"))
+	t.Assert().Equal("tcp6", builder.SetDialPreference(DialPreferenceIPv6Only).dialNetwork("tcp"))
+	t.Assert().Equal("tcp", builder.SetDialPreference(DialPreferenceAuto).dialNetwork("tcp"))
+}
+
+func (t *HTTPClientBuilderTest) Test_SetDialPreference_Dial() {
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	t.Require().NoError(err)
+	defer listener.Close()
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	t.Require().NoError(err)
+	resolver := &dnsResolverMock{hosts: map[string]string{"crm.example.com": "127.0.0.1"}}
+
+	builder := NewHTTPClientBuilder().
+		SetDialPreference(DialPreferenceIPv4Only).
+		WithDNSCache(time.Minute).
+		SetDNSResolver(resolver)
+	_, err = builder.Build()
+	t.Require().NoError(err)
+	conn, err := builder.httpTransport.DialContext(context.Background(), "tcp", net.JoinHostPort("crm.example.com", port))
+	t.Require().NoError(err)
+	t.Require().NoError(conn.Close())
+
+	builder = NewHTTPClientBuilder().
+		SetDialPreference(DialPreferenceIPv6Only).
+		WithDNSCache(time.Minute).
+		SetDNSResolver(resolver)
+	_, err = builder.Build()
+	t.Require().NoError(err)
+	_, err = builder.httpTransport.DialContext(context.Background(), "tcp", net.JoinHostPort("crm.example.com", port))
+	t.Assert().Error(err)
+
+	builder = NewHTTPClientBuilder().SetDialPreference(DialPreferenceIPv6Only)
+	_, err = builder.Build()
+	t.Require().NoError(err)
+	_, err = builder.httpTransport.DialContext(context.Background(), "tcp", listener.Addr().String())
+	t.Assert().Error(err)
+}
+
 func (t *HTTPClientBuilderTest) Test_parseAddress() {
 	assert.NoError(t.T(), t.builder.parseAddress())
 }
@@ -314,6 +417,25 @@ uf/TQPpjrGW5nxOf94qn6FzV2WSype9BcM5MD7z7rk202Fs7Zqc=
 	assert.Equal(t.T(), "ok", string(data), "invalid body contents")
 }
 
+type idleClosingTransport struct {
+	http.RoundTripper
+	closed bool
+}
+
+func (c *idleClosingTransport) CloseIdleConnections() {
+	c.closed = true
+}
+
+func (t *HTTPClientBuilderTest) Test_mockRoundTripper_CloseIdleConnections() {
+	base := &idleClosingTransport{RoundTripper: http.DefaultTransport}
+	client := &http.Client{Transport: &mockRoundTripper{base: base, builder: t.builder}}
+	client.CloseIdleConnections()
+	t.Assert().True(base.closed)
+
+	client = &http.Client{Transport: &mockRoundTripper{base: http.NewFileTransport(http.Dir(".")), builder: t.builder}}
+	t.Assert().NotPanics(client.CloseIdleConnections)
+}
+
 func (t *HTTPClientBuilderTest) Test_UseTLS10() {
 	client, err := NewHTTPClientBuilder().SetSSLVerification(true).UseTLS10().Build()
 
@@ -325,6 +447,78 @@ func (t *HTTPClientBuilderTest) Test_UseTLS10() {
 	t.Assert().NotNil(client.Transport.(*http.Transport).Proxy)
 }
 
+func (t *HTTPClientBuilderTest) Test_SetMinTLSVersion() {
+	for version, expected := range map[string]uint16{
+		"1.0": tls.VersionTLS10,
+		"1.1": tls.VersionTLS11,
+		"1.2": tls.VersionTLS12,
+		"1.3": tls.VersionTLS13,
+	} {
+		client, err := NewHTTPClientBuilder().
+			FromConfig(&config.HTTPClientConfig{MinTLSVersion: version}).
+			Build()
+
+		t.Require().NoError(err, version)
+		t.Require().NotNil(client.Transport.(*http.Transport).TLSClientConfig)
+		t.Assert().Equal(expected, client.Transport.(*http.Transport).TLSClientConfig.MinVersion, version)
+	}
+
+	client, err := NewHTTPClientBuilder().UseTLS10().SetMinTLSVersion("1.3").Build()
+	t.Require().NoError(err)
+	t.Assert().Equal(uint16(tls.VersionTLS13), client.Transport.(*http.Transport).TLSClientConfig.MinVersion)
+}
+
+func (t *HTTPClientBuilderTest) Test_SetMinTLSVersion_Unknown() {
+	client, err := NewHTTPClientBuilder().SetMinTLSVersion("1.4").Build()
+
+	t.Assert().Nil(client)
+	t.Assert().EqualError(err, "unknown TLS version: 1.4")
+}
+
+func (t *HTTPClientBuilderTest) Test_SetForceHTTP2() {
+	client, err := NewHTTPClientBuilder().Build()
+	t.Require().NoError(err)
+	t.Assert().True(client.Transport.(*http.Transport).ForceAttemptHTTP2)
+	t.Assert().Nil(client.Transport.(*http.Transport).TLSNextProto)
+
+	builder := NewHTTPClientBuilder().SetSSLVerification(true)
+	builder.httpTransport.TLSClientConfig.NextProtos = []string{"h2", "http/1.1"}
+	client, err = builder.SetForceHTTP2(false).Build()
+	t.Require().NoError(err)
+	t.Assert().False(client.Transport.(*http.Transport).ForceAttemptHTTP2)
+	t.Assert().NotNil(client.Transport.(*http.Transport).TLSNextProto)
+	t.Assert().Empty(client.Transport.(*http.Transport).TLSNextProto)
+	t.Assert().Empty(client.Transport.(*http.Transport).TLSClientConfig.NextProtos)
+
+	client, err = NewHTTPClientBuilder().FromConfig(&config.HTTPClientConfig{DisableHTTP2: true}).Build()
+	t.Require().NoError(err)
+	t.Assert().False(client.Transport.(*http.Transport).ForceAttemptHTTP2)
+}
+
+func (t *HTTPClientBuilderTest) Test_SetForceHTTP2_Protocol() {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.Proto)
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+
+	for enabled, proto := range map[bool]string{true: "HTTP/2.0", false: "HTTP/1.1"} {
+		client, err := NewHTTPClientBuilder().SetCertPool(pool).SetForceHTTP2(enabled).Build()
+		t.Require().NoError(err)
+
+		resp, err := client.Get(server.URL)
+		t.Require().NoError(err)
+		body, err := io.ReadAll(resp.Body)
+		t.Require().NoError(err)
+		t.Require().NoError(resp.Body.Close())
+		t.Assert().Equal(proto, string(body))
+	}
+}
+
 // taken from https://stackoverflow.com/questions/23558425/how-do-i-get-the-local-ip-address-in-go
 func getOutboundIP() net.IP {
 	conn, err := net.Dial("udp", "8.8.8.8:80")
@@ -0,0 +1,49 @@
+package util
+
+import "context"
+
+// Paginate walks through the pages starting from the first one and calls handle for every fetched item.
+// Iteration stops when fetchPage reports that there are no more pages, when fetchPage or handle returns
+// an error, or when context is done. The first error is returned as is.
+//
+// Usage with retailcrm.Client:
+//
+//	err := util.Paginate(ctx, func(page int) ([]retailcrm.Order, bool, error) {
+//		resp, _, err := client.Orders(retailcrm.OrdersRequest{Page: page, Limit: 100})
+//		if err != nil {
+//			return nil, false, err
+//		}
+//		return resp.Orders, resp.Pagination.CurrentPage < resp.Pagination.TotalPageCount, nil
+//	}, func(order retailcrm.Order) error {
+//		return processOrder(order)
+//	})
+func Paginate[T any](
+	ctx context.Context,
+	fetchPage func(page int) (items []T, hasMore bool, err error),
+	handle func(T) error,
+) error {
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		items, hasMore, err := fetchPage(page)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			if err := handle(item); err != nil {
+				return err
+			}
+		}
+
+		if !hasMore {
+			return nil
+		}
+	}
+}
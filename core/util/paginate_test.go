@@ -0,0 +1,76 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fakePages(pages [][]int) func(page int) ([]int, bool, error) {
+	return func(page int) ([]int, bool, error) {
+		if page > len(pages) {
+			return nil, false, errors.New("unexpected page")
+		}
+		return pages[page-1], page < len(pages), nil
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	var handled []int
+	err := Paginate(context.Background(), fakePages([][]int{{1, 2}, {3, 4}, {5}}), func(item int) error {
+		handled = append(handled, item)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, handled)
+}
+
+func TestPaginate_FetchError(t *testing.T) {
+	fetchErr := errors.New("fetch error")
+	err := Paginate(context.Background(), func(page int) ([]int, bool, error) {
+		if page == 2 {
+			return nil, false, fetchErr
+		}
+		return []int{page}, true, nil
+	}, func(int) error {
+		return nil
+	})
+
+	assert.ErrorIs(t, err, fetchErr)
+}
+
+func TestPaginate_HandleError(t *testing.T) {
+	handleErr := errors.New("handle error")
+	calls := 0
+	err := Paginate(context.Background(), fakePages([][]int{{1, 2}, {3}}), func(item int) error {
+		calls++
+		if item == 2 {
+			return handleErr
+		}
+		return nil
+	})
+
+	assert.ErrorIs(t, err, handleErr)
+	assert.Equal(t, 2, calls)
+}
+
+func TestPaginate_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var handled []int
+	err := Paginate(ctx, fakePages([][]int{{1, 2}, {3, 4}, {5}}), func(item int) error {
+		handled = append(handled, item)
+		if item == 3 {
+			cancel()
+		}
+		return nil
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []int{1, 2, 3}, handled)
+}
@@ -20,8 +20,10 @@ const (
 	CountryPhoneCodeUS  = "1443"
 	CountryPhoneCodePS  = 970
 	CountryPhoneCodeUZ  = 998
+	CountryPhoneCodeBR  = 55
 	PalestineRegion     = "PS"
 	BangladeshRegion    = "BD"
+	BrazilRegion        = "BR"
 )
 
 var (
@@ -52,6 +54,31 @@ func FormatNumberForWA(number string) (string, error) {
 	return formattedPhoneNumber, nil
 }
 
+// IsValidForWA checks that number is a valid phone number which can be used as a WhatsApp recipient.
+// It returns ErrPhoneTooShort, ErrCannotDetermineCountry or ErrCannotParsePhone if number cannot be parsed.
+// Besides libphonenumber validity check the same country-specific rules as in ParsePhone are applied.
+func IsValidForWA(number string) (bool, error) {
+	parsedPhone, err := ParsePhone(number)
+	if err != nil {
+		return false, err
+	}
+
+	if pn.IsValidNumber(parsedPhone) {
+		return true, nil
+	}
+
+	switch parsedPhone.GetCountryCode() {
+	case CountryPhoneCodeAG:
+		withNine, err := pn.Parse(Add9AGIFNeed(parsedPhone), "")
+		return err == nil && pn.IsValidNumber(withNine), nil
+	case CountryPhoneCodeUZ:
+		// Some mobile operator codes (for example, 88) are missing in libphonenumber metadata.
+		return len(fmt.Sprintf("%d", parsedPhone.GetNationalNumber())) == 9, nil // nolint:mnd
+	}
+
+	return false, nil
+}
+
 // ParsePhone this function parses the number as a string
 // For Mexican numbers `1` is always added to the national number because it is always removed during parsing.
 // Attention when formatted in libphonenumber.INTERNATIONAL 1 will not be after the country code, even though
@@ -91,6 +118,15 @@ func ParsePhone(phoneNumber string) (*pn.PhoneNumber, error) {
 		parsedPhone.NationalNumber = &number
 	}
 
+	if IsBrazilNumber(parsedPhone) {
+		number, err := getBrazilianNationalNumber(parsedPhone)
+		if err != nil {
+			return nil, err
+		}
+
+		parsedPhone.NationalNumber = &number
+	}
+
 	if IsMexicoNumber(parsedPhone) {
 		number, err := getMexicanNationalNumber(parsedPhone)
 		if err != nil {
@@ -111,6 +147,10 @@ func IsMexicoNumber(parsed *pn.PhoneNumber) bool {
 	return parsed.GetCountryCode() == CountryPhoneCodeMX
 }
 
+func IsBrazilNumber(parsed *pn.PhoneNumber) bool {
+	return parsed.GetCountryCode() == CountryPhoneCodeBR
+}
+
 func IsUSNumber(phone string) bool {
 	return slices.Contains(UndefinedUSCodes, phone[:4]) &&
 		phoneiso3166.E164.LookupString(CountryPhoneCodeUS+phone[4:]) == "US"
@@ -180,6 +220,24 @@ func getUzbekistanNationalNumber(phone string, parsedPhone *pn.PhoneNumber) (uin
 	return result, nil
 }
 
+// getBrazilianNationalNumber adds mobile 9 after the area code for old 8-digit mobile numbers.
+// Mobile subscriber numbers start with 6-9, landline numbers (2-5) are left as is.
+func getBrazilianNationalNumber(parsedPhone *pn.PhoneNumber) (uint64, error) {
+	result := parsedPhone.GetNationalNumber()
+	numberWOCountry := fmt.Sprintf("%d", result)
+
+	if len(numberWOCountry) == 10 && numberWOCountry[2] >= '6' { // nolint:mnd
+		number, err := strconv.Atoi(numberWOCountry[:2] + "9" + numberWOCountry[2:])
+		if err != nil {
+			return 0, err
+		}
+
+		result = uint64(number) //nolint:gosec
+	}
+
+	return result, nil
+}
+
 func getMexicanNationalNumber(parsedPhone *pn.PhoneNumber) (uint64, error) {
 	phoneWithDigit := fmt.Sprintf("1%d", parsedPhone.GetNationalNumber())
 
@@ -87,6 +87,30 @@ func TestParsePhone(t *testing.T) {
 		assert.Equal(t, int32(CountryPhoneCodeAG), pn.GetCountryCode())
 	})
 
+	t.Run("brazilian number", func(t *testing.T) {
+		n := "5511987654321"
+		pn, err := ParsePhone(n)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(11987654321), pn.GetNationalNumber())
+		assert.Equal(t, int32(CountryPhoneCodeBR), pn.GetCountryCode())
+
+		n = "551187654321"
+		pn, err = ParsePhone(n)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(11987654321), pn.GetNationalNumber())
+		assert.Equal(t, int32(CountryPhoneCodeBR), pn.GetCountryCode())
+
+		n = "+55 (21) 8765-4321"
+		pn, err = ParsePhone(n)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(21987654321), pn.GetNationalNumber())
+
+		n = "551133334444"
+		pn, err = ParsePhone(n)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1133334444), pn.GetNationalNumber())
+	})
+
 	t.Run("uzbekistan number", func(t *testing.T) {
 		n := "998882207724"
 		pn, err := ParsePhone(n)
@@ -116,6 +140,8 @@ func TestFormatNumberForWA(t *testing.T) {
 		"19455009160":   "+19455009160",
 		"19452381431":   "+19452381431",
 		"12793006305":   "+12793006305",
+		"551187654321":  "+5511987654321",
+		"5511987654321": "+5511987654321",
 	}
 
 	for orig, expected := range numbers {
@@ -124,3 +150,38 @@ func TestFormatNumberForWA(t *testing.T) {
 		require.Equal(t, expected, actual)
 	}
 }
+
+func TestIsValidForWA(t *testing.T) {
+	valid := []string{
+		"79040000000",
+		"89185553535",
+		"491736276098",
+		"4915229457499",
+		"5491131157821",
+		"541131157821",
+		"5219982418333",
+		"529982418333",
+		"14452385043",
+		"970567800663",
+		"998882207724",
+		"998902207724",
+	}
+
+	for _, number := range valid {
+		ok, err := IsValidForWA(number)
+		require.NoError(t, err, number)
+		assert.True(t, ok, number)
+	}
+
+	ok, err := IsValidForWA("71234567890")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = IsValidForWA("1234")
+	assert.ErrorIs(t, err, ErrPhoneTooShort)
+	assert.False(t, ok)
+
+	ok, err = IsValidForWA("00000000")
+	assert.ErrorIs(t, err, ErrCannotDetermineCountry)
+	assert.False(t, ok)
+}
@@ -0,0 +1,25 @@
+package util
+
+import (
+	pn "github.com/ttacon/libphonenumber"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/logger"
+)
+
+func init() {
+	// logger cannot parse phones by itself because util depends on it.
+	logger.DefaultPIIMasker.WithValidator(logger.PIIPhoneRegexp, IsPhoneNumber)
+}
+
+// MaskPII masks phone numbers and emails in the provided string. Masking patterns and replacement
+// can be configured via logger.DefaultPIIMasker which is also used by the logger.MaskedString field.
+// Digit sequences are masked only if they are valid phone numbers, so order IDs or timestamps are preserved.
+func MaskPII(s string) string {
+	return logger.DefaultPIIMasker.Mask(s)
+}
+
+// IsPhoneNumber returns true if value can be parsed by ParsePhone and is a valid phone number.
+func IsPhoneNumber(value string) bool {
+	parsed, err := ParsePhone(value)
+	return err == nil && pn.IsValidNumber(parsed)
+}
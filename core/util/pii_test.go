@@ -0,0 +1,38 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskPII(t *testing.T) {
+	assert.Equal(t,
+		"Hello! My phone is *** and email is ***. Thanks.",
+		MaskPII("Hello! My phone is +1 443-555-0123 and email is test@retailcrm.ru. Thanks."))
+}
+
+func TestMaskPII_NotPhone(t *testing.T) {
+	for _, source := range []string{
+		"Order #1234567890 was created",
+		"Timestamp: 1700000000",
+		"Transaction 20231015123456 is completed",
+		"Code 1234567",
+	} {
+		t.Run(source, func(t *testing.T) {
+			assert.Equal(t, source, MaskPII(source))
+		})
+	}
+}
+
+func TestMaskPII_Phones(t *testing.T) {
+	assert.Equal(t,
+		"Call *** or ***, order 1700000000",
+		MaskPII("Call +7 (999) 123-45-67 or 89991234567, order 1700000000"))
+}
+
+func TestIsPhoneNumber(t *testing.T) {
+	assert.True(t, IsPhoneNumber("+49 1736276098"))
+	assert.False(t, IsPhoneNumber("1234567890"))
+	assert.False(t, IsPhoneNumber("123"))
+}
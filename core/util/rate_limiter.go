@@ -0,0 +1,133 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket rate limiter with separate bucket for every connection.
+// It can be used to respect CRM API rate limits before making outbound requests.
+//
+// Usage:
+//
+//	limiter := util.NewRateLimiter(10, 10)
+//	if err := limiter.Wait(ctx, conn.ID); err != nil {
+//		return err
+//	}
+//	// make request to the CRM.
+type RateLimiter struct {
+	buckets map[int]*tokenBucket
+	now     func() time.Time
+	rate    float64
+	burst   int
+	mu      sync.Mutex
+}
+
+type tokenBucket struct {
+	last   time.Time
+	tokens float64
+}
+
+// NewRateLimiter returns RateLimiter which allows rate events per second with bursts of at most burst events
+// for every connection. Burst less than 1 is treated as 1. It panics if rate is not positive.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if math.IsNaN(rate) || rate <= 0 {
+		panic(fmt.Sprintf("rate limiter rate must be positive, got %v", rate))
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		buckets: make(map[int]*tokenBucket),
+		now:     time.Now,
+		rate:    rate,
+		burst:   burst,
+	}
+}
+
+// Allow reports whether an event for the connection may happen now. Token is consumed if it does.
+func (r *RateLimiter) Allow(connID int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bucket := r.refill(connID)
+	if bucket.tokens < 1 {
+		return false
+	}
+
+	bucket.tokens--
+	return true
+}
+
+// Wait blocks until an event for the connection may happen or context is done.
+// Context error is returned in the latter case, token is not consumed then.
+func (r *RateLimiter) Wait(ctx context.Context, connID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	delay := r.reserve(connID)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		r.cancel(connID)
+		return ctx.Err()
+	}
+}
+
+// reserve consumes a token and returns the time which should pass before it's available.
+func (r *RateLimiter) reserve(connID int) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bucket := r.refill(connID)
+	bucket.tokens--
+	if bucket.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-bucket.tokens / r.rate * float64(time.Second))
+}
+
+// cancel returns previously reserved token to the bucket.
+func (r *RateLimiter) cancel(connID int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if bucket, ok := r.buckets[connID]; ok {
+		bucket.tokens++
+	}
+}
+
+// refill returns connection bucket with tokens accumulated since the last call. Must be called under lock.
+func (r *RateLimiter) refill(connID int) *tokenBucket {
+	now := r.now()
+	bucket, ok := r.buckets[connID]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(r.burst), last: now}
+		r.buckets[connID] = bucket
+		return bucket
+	}
+
+	if elapsed := now.Sub(bucket.last); elapsed > 0 {
+		bucket.tokens += elapsed.Seconds() * r.rate
+		if bucket.tokens > float64(r.burst) {
+			bucket.tokens = float64(r.burst)
+		}
+		bucket.last = now
+	}
+
+	return bucket
+}
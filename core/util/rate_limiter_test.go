@@ -0,0 +1,71 @@
+package util
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	now := time.Now()
+	limiter := NewRateLimiter(1, 2)
+	limiter.now = func() time.Time {
+		return now
+	}
+
+	assert.True(t, limiter.Allow(1))
+	assert.True(t, limiter.Allow(1))
+	assert.False(t, limiter.Allow(1))
+	assert.True(t, limiter.Allow(2))
+
+	now = now.Add(time.Second)
+	assert.True(t, limiter.Allow(1))
+	assert.False(t, limiter.Allow(1))
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	limiter := NewRateLimiter(20, 1)
+	start := time.Now()
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, limiter.Wait(context.Background(), 1))
+	}
+
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+func TestRateLimiter_Wait_ContextCancel(t *testing.T) {
+	limiter := NewRateLimiter(0.1, 1)
+	require.NoError(t, limiter.Wait(context.Background(), 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := limiter.Wait(ctx, 1)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.InDelta(t, 0, limiter.buckets[1].tokens, 0.01)
+}
+
+func TestRateLimiter_Wait_CanceledContext(t *testing.T) {
+	limiter := NewRateLimiter(1, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorIs(t, limiter.Wait(ctx, 1), context.Canceled)
+	assert.True(t, limiter.Allow(1))
+}
+
+func TestNewRateLimiter_InvalidRate(t *testing.T) {
+	for _, rate := range []float64{0, -1, math.NaN()} {
+		assert.Panics(t, func() {
+			NewRateLimiter(rate, 1)
+		}, "rate: %v", rate)
+	}
+}
@@ -0,0 +1,90 @@
+package util
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// DefaultRetryMultiplier is used if RetryConfig.Multiplier is not set.
+const DefaultRetryMultiplier = 2
+
+// RetryConfig contains configuration for the Retry.
+type RetryConfig struct {
+	// Retryable reports whether operation should be retried after provided error. All errors are retryable if nil.
+	Retryable func(error) bool
+	// MaxAttempts is the maximum number of attempts including the first one. Values less than 1 are treated as 1.
+	MaxAttempts int
+	// BaseDelay is a delay after the first failed attempt.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between attempts. Zero means that delay is capped by the maximum time.Duration.
+	MaxDelay time.Duration
+	// Multiplier is used to increase the delay after every failed attempt. DefaultRetryMultiplier is used if zero.
+	Multiplier float64
+}
+
+// Retry calls fn until it succeeds, returns non-retryable error or the attempts are exhausted.
+// Delay between attempts grows exponentially. Attempts are numbered starting from 1.
+// Context error is returned if context is done while waiting for the next attempt,
+// the last fn error is returned otherwise.
+//
+// Usage:
+//
+//	err := util.Retry(ctx, util.RetryConfig{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: time.Minute},
+//		func(attempt int) error {
+//			return uploadFile(ctx, file)
+//		})
+func Retry(ctx context.Context, cfg RetryConfig, fn func(attempt int) error) error {
+	multiplier := cfg.Multiplier
+	if multiplier == 0 {
+		multiplier = DefaultRetryMultiplier
+	}
+
+	delay := cfg.BaseDelay
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		err := fn(attempt)
+		if err == nil {
+			return nil
+		}
+
+		if attempt >= cfg.MaxAttempts || (cfg.Retryable != nil && !cfg.Retryable(err)) {
+			return err
+		}
+
+		if cfg.MaxDelay > 0 && delay > cfg.MaxDelay {
+			delay = cfg.MaxDelay
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+
+		delay = nextRetryDelay(delay, multiplier, cfg.MaxDelay)
+	}
+}
+
+// nextRetryDelay multiplies the delay and clamps the result to the maxDelay (or to the maximum time.Duration
+// if maxDelay is zero) before converting it back, because out of range float to time.Duration conversion overflows.
+func nextRetryDelay(delay time.Duration, multiplier float64, maxDelay time.Duration) time.Duration {
+	limit := time.Duration(math.MaxInt64)
+	if maxDelay > 0 {
+		limit = maxDelay
+	}
+
+	next := float64(delay) * multiplier
+	if next >= float64(limit) {
+		return limit
+	}
+	if next < 0 {
+		return 0
+	}
+	return time.Duration(next)
+}
@@ -0,0 +1,109 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errRetryTest = errors.New("retry test error")
+
+func TestRetry_SuccessAfterRetries(t *testing.T) {
+	var attempts []int
+	err := Retry(context.Background(), RetryConfig{MaxAttempts: 5, BaseDelay: time.Millisecond}, func(attempt int) error {
+		attempts = append(attempts, attempt)
+		if attempt < 3 {
+			return errRetryTest
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, attempts)
+}
+
+func TestRetry_GiveUpAfterMax(t *testing.T) {
+	calls := 0
+	start := time.Now()
+	err := Retry(context.Background(), RetryConfig{
+		MaxAttempts: 4,
+		BaseDelay:   10 * time.Millisecond,
+		MaxDelay:    20 * time.Millisecond,
+	}, func(int) error {
+		calls++
+		return errRetryTest
+	})
+
+	assert.ErrorIs(t, err, errRetryTest)
+	assert.Equal(t, 4, calls)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestRetry_NonRetryable(t *testing.T) {
+	fatal := errors.New("fatal")
+	calls := 0
+	err := Retry(context.Background(), RetryConfig{
+		MaxAttempts: 5,
+		BaseDelay:   time.Millisecond,
+		Retryable: func(err error) bool {
+			return !errors.Is(err, fatal)
+		},
+	}, func(int) error {
+		calls++
+		return fatal
+	})
+
+	assert.ErrorIs(t, err, fatal)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetry_ContextCancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	calls := 0
+	start := time.Now()
+	err := Retry(ctx, RetryConfig{MaxAttempts: 5, BaseDelay: time.Second}, func(int) error {
+		calls++
+		return errRetryTest
+	})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, calls)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestRetry_UncappedDelayOverflow(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	calls := 0
+	err := Retry(ctx, RetryConfig{
+		MaxAttempts: 1000,
+		BaseDelay:   time.Nanosecond,
+		Multiplier:  1e30,
+	}, func(int) error {
+		calls++
+		return errRetryTest
+	})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 2, calls)
+}
+
+func Test_nextRetryDelay(t *testing.T) {
+	delay := time.Nanosecond
+	for i := 0; i < 1000; i++ {
+		next := nextRetryDelay(delay, DefaultRetryMultiplier, 0)
+		assert.GreaterOrEqual(t, next, delay)
+		delay = next
+	}
+	assert.Equal(t, time.Duration(math.MaxInt64), delay)
+
+	assert.Equal(t, time.Minute, nextRetryDelay(time.Minute, 1e30, time.Minute))
+	assert.Equal(t, 2*time.Second, nextRetryDelay(time.Second, DefaultRetryMultiplier, time.Minute))
+}
@@ -0,0 +1,43 @@
+package util
+
+import (
+	"fmt"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/stacktrace"
+)
+
+// PanicError is returned by SafeCall if the function has panicked. Value contains the recovered value.
+type PanicError struct {
+	Value interface{}
+}
+
+// Error returns panic message.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns recovered value if it is an error.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// SafeCall runs fn and returns its error. Panic is recovered and returned as *PanicError with the stack trace
+// of the panic attached (use "%+v" to print it).
+//
+// Usage:
+//
+//	if err := util.SafeCall(worker.Process); err != nil {
+//		log.Error("processing failed", logger.Err(err))
+//	}
+func SafeCall(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = stacktrace.AppendToError(&PanicError{Value: r})
+		}
+	}()
+
+	return fn()
+}
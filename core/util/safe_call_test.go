@@ -0,0 +1,37 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSafeCall_Error(t *testing.T) {
+	assert.NoError(t, SafeCall(func() error { return nil }))
+	assert.Equal(t, io.EOF, SafeCall(func() error { return io.EOF }))
+}
+
+func TestSafeCall_Panic(t *testing.T) {
+	err := SafeCall(func() error {
+		panic("something went wrong")
+	})
+	require.Error(t, err)
+	assert.Equal(t, "panic: something went wrong", err.Error())
+
+	var panicErr *PanicError
+	require.True(t, errors.As(err, &panicErr))
+	assert.Equal(t, "something went wrong", panicErr.Value)
+	assert.Contains(t, fmt.Sprintf("%+v", err), "TestSafeCall_Panic")
+}
+
+func TestSafeCall_PanicWithError(t *testing.T) {
+	err := SafeCall(func() error {
+		panic(io.ErrUnexpectedEOF)
+	})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
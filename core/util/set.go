@@ -0,0 +1,69 @@
+package util
+
+// Set is an unordered collection of unique values.
+// Usage:
+//
+//	granted := util.NewSet(credentials.Scopes...)
+//	if missing := util.NewSet(requiredScopes...).Difference(granted); missing.Len() > 0 {
+//		return errorutil.NewInsufficientScopesErr(missing.Values())
+//	}
+type Set[T comparable] map[T]struct{}
+
+// NewSet returns Set with provided values.
+func NewSet[T comparable](values ...T) Set[T] {
+	s := make(Set[T], len(values))
+	s.Add(values...)
+	return s
+}
+
+// Add values to the set.
+func (s Set[T]) Add(values ...T) {
+	for _, value := range values {
+		s[value] = struct{}{}
+	}
+}
+
+// Contains returns true if value is present in the set.
+func (s Set[T]) Contains(value T) bool {
+	_, ok := s[value]
+	return ok
+}
+
+// Len returns amount of values in the set.
+func (s Set[T]) Len() int {
+	return len(s)
+}
+
+// Values returns set values in an unspecified order.
+func (s Set[T]) Values() []T {
+	values := make([]T, 0, len(s))
+	for value := range s {
+		values = append(values, value)
+	}
+	return values
+}
+
+// Difference returns new set with values which are present in s but not in other.
+func (s Set[T]) Difference(other Set[T]) Set[T] {
+	result := make(Set[T])
+	for value := range s {
+		if !other.Contains(value) {
+			result[value] = struct{}{}
+		}
+	}
+	return result
+}
+
+// Intersection returns new set with values which are present both in s and other.
+func (s Set[T]) Intersection(other Set[T]) Set[T] {
+	if len(other) < len(s) {
+		s, other = other, s
+	}
+	result := make(Set[T])
+	for value := range s {
+		if other.Contains(value) {
+			result[value] = struct{}{}
+		}
+	}
+	return result
+}
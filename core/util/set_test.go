@@ -0,0 +1,42 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSet_AddContains(t *testing.T) {
+	s := NewSet("one", "two", "one")
+	assert.Equal(t, 2, s.Len())
+	assert.True(t, s.Contains("one"))
+	assert.False(t, s.Contains("three"))
+
+	s.Add("three")
+	assert.True(t, s.Contains("three"))
+	assert.ElementsMatch(t, []string{"one", "two", "three"}, s.Values())
+
+	var empty Set[int]
+	assert.False(t, empty.Contains(1))
+	assert.Equal(t, 0, empty.Len())
+}
+
+func TestSet_Difference(t *testing.T) {
+	a := NewSet(1, 2, 3)
+	b := NewSet(2, 4)
+
+	assert.Equal(t, NewSet(1, 3), a.Difference(b))
+	assert.Equal(t, NewSet(4), b.Difference(a))
+	assert.Equal(t, NewSet[int](), a.Difference(a))
+	assert.Equal(t, a, a.Difference(nil))
+}
+
+func TestSet_Intersection(t *testing.T) {
+	a := NewSet(1, 2, 3)
+	b := NewSet(2, 3, 4, 5)
+
+	assert.Equal(t, NewSet(2, 3), a.Intersection(b))
+	assert.Equal(t, NewSet(2, 3), b.Intersection(a))
+	assert.Equal(t, NewSet[int](), a.Intersection(NewSet(6)))
+	assert.Equal(t, NewSet[int](), a.Intersection(nil))
+}
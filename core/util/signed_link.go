@@ -0,0 +1,99 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	// SignedLinkExpiresAtClaim contains expiration time of the signed link token (unix time).
+	SignedLinkExpiresAtClaim = "exp"
+	// SignedLinkIssuedAtClaim contains issue time of the signed link token (unix time).
+	SignedLinkIssuedAtClaim = "iat"
+)
+
+var (
+	// ErrInvalidSignedLink is returned if signed link token is malformed or its signature is invalid.
+	ErrInvalidSignedLink = errors.New("invalid signed link token")
+	// ErrSignedLinkExpired is returned if signed link token is expired.
+	ErrSignedLinkExpired = errors.New("signed link token is expired")
+)
+
+// signedLinkHeader is a pre-encoded JWT header for the HS256 tokens.
+var signedLinkHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// SignedLink returns HS256 JWT with provided claims which will expire after ttl. It can be used to generate
+// time-limited links to the settings pages. "exp" and "iat" claims are set automatically.
+// Usage:
+//
+//	token, err := util.SignedLink(secret, map[string]any{"clientId": conn.ClientID}, time.Hour)
+//	link := "https://example.com/settings/" + token
+func SignedLink(secret string, claims map[string]any, ttl time.Duration) (string, error) {
+	if secret == "" {
+		return "", errors.New("secret must not be empty")
+	}
+
+	now := time.Now()
+	payload := make(map[string]any, len(claims)+2) // nolint:mnd
+	for k, v := range claims {
+		payload[k] = v
+	}
+	payload[SignedLinkIssuedAtClaim] = now.Unix()
+	payload[SignedLinkExpiresAtClaim] = now.Add(ttl).Unix()
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := signedLinkHeader + "." + base64.RawURLEncoding.EncodeToString(data)
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signLinkToken(secret, unsigned)), nil
+}
+
+// VerifySignedLink checks token signature and expiration and returns its claims.
+// ErrInvalidSignedLink is returned for malformed or tampered tokens, ErrSignedLinkExpired for expired ones.
+func VerifySignedLink(secret, token string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != signedLinkHeader { // nolint:mnd
+		return nil, ErrInvalidSignedLink
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidSignedLink
+	}
+	if !hmac.Equal(signature, signLinkToken(secret, parts[0]+"."+parts[1])) {
+		return nil, ErrInvalidSignedLink
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidSignedLink
+	}
+
+	var claims map[string]any
+	if err := json.Unmarshal(data, &claims); err != nil {
+		return nil, ErrInvalidSignedLink
+	}
+
+	exp, ok := claims[SignedLinkExpiresAtClaim].(float64)
+	if !ok {
+		return nil, ErrInvalidSignedLink
+	}
+	if time.Now().Unix() >= int64(exp) {
+		return nil, ErrSignedLinkExpired
+	}
+
+	return claims, nil
+}
+
+func signLinkToken(secret, unsigned string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,68 @@
+package util
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLinkSecret = "secret"
+
+func TestSignedLink_Valid(t *testing.T) {
+	token, err := SignedLink(testLinkSecret, map[string]any{"clientId": "client", "connection": 1}, time.Hour)
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(token, "."), 3)
+
+	claims, err := VerifySignedLink(testLinkSecret, token)
+	require.NoError(t, err)
+	assert.Equal(t, "client", claims["clientId"])
+	assert.Equal(t, float64(1), claims["connection"])
+	assert.Contains(t, claims, SignedLinkIssuedAtClaim)
+	assert.Contains(t, claims, SignedLinkExpiresAtClaim)
+}
+
+func TestSignedLink_EmptySecret(t *testing.T) {
+	_, err := SignedLink("", nil, time.Hour)
+	assert.Error(t, err)
+}
+
+func TestSignedLink_DoesNotModifyClaims(t *testing.T) {
+	claims := map[string]any{"clientId": "client"}
+	_, err := SignedLink(testLinkSecret, claims, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"clientId": "client"}, claims)
+}
+
+func TestVerifySignedLink_Expired(t *testing.T) {
+	token, err := SignedLink(testLinkSecret, map[string]any{"clientId": "client"}, -time.Minute)
+	require.NoError(t, err)
+
+	_, err = VerifySignedLink(testLinkSecret, token)
+	assert.ErrorIs(t, err, ErrSignedLinkExpired)
+}
+
+func TestVerifySignedLink_Tampered(t *testing.T) {
+	token, err := SignedLink(testLinkSecret, map[string]any{"clientId": "client"}, time.Hour)
+	require.NoError(t, err)
+
+	other, err := SignedLink(testLinkSecret, map[string]any{"clientId": "other"}, time.Hour)
+	require.NoError(t, err)
+
+	parts := strings.Split(token, ".")
+	parts[1] = strings.Split(other, ".")[1]
+	_, err = VerifySignedLink(testLinkSecret, strings.Join(parts, "."))
+	assert.ErrorIs(t, err, ErrInvalidSignedLink)
+
+	_, err = VerifySignedLink("another secret", token)
+	assert.ErrorIs(t, err, ErrInvalidSignedLink)
+}
+
+func TestVerifySignedLink_Malformed(t *testing.T) {
+	for _, token := range []string{"", "token", "a.b.c", signedLinkHeader + ".!.!"} {
+		_, err := VerifySignedLink(testLinkSecret, token)
+		assert.ErrorIs(t, err, ErrInvalidSignedLink, token)
+	}
+}
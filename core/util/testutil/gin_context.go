@@ -0,0 +1,81 @@
+package testutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// ConnectionContextKey is the default key which is used by WithConnection.
+	ConnectionContextKey = "connection"
+	// AccountContextKey is the default key which is used by WithAccount.
+	AccountContextKey = "account"
+	// LocalizerContextKey is the default key which is used by WithLocalizer. It's the same as core.LocalizerContextKey.
+	LocalizerContextKey = "localizer"
+)
+
+// GinContextOption configures the gin.Context created by NewGinContext.
+type GinContextOption func(c *gin.Context)
+
+// WithConnection puts connection into the context. Custom key can be provided if the handler doesn't use
+// the ConnectionContextKey.
+func WithConnection(conn interface{}, key ...string) GinContextOption {
+	return WithContextValue(contextKey(ConnectionContextKey, key), conn)
+}
+
+// WithAccount puts account into the context. Custom key can be provided if the handler doesn't use
+// the AccountContextKey.
+func WithAccount(acc interface{}, key ...string) GinContextOption {
+	return WithContextValue(contextKey(AccountContextKey, key), acc)
+}
+
+// WithLocalizer puts localizer (usually *core.Localizer) into the context. Custom key must be provided
+// if localizer uses non-default ContextKey.
+func WithLocalizer(localizer interface{}, key ...string) GinContextOption {
+	return WithContextValue(contextKey(LocalizerContextKey, key), localizer)
+}
+
+// WithContextValue puts arbitrary value into the context.
+func WithContextValue(key string, val interface{}) GinContextOption {
+	return func(c *gin.Context) {
+		c.Set(key, val)
+	}
+}
+
+// WithRequest sets the request for the context. Empty GET request to "/" is used by default.
+func WithRequest(req *http.Request) GinContextOption {
+	return func(c *gin.Context) {
+		c.Request = req
+	}
+}
+
+// NewGinContext returns gin.Context for the handler tests along with the recorder which will contain the response.
+// Usage:
+//
+//	c, rr := testutil.NewGinContext(
+//		testutil.WithConnection(&models.Connection{ClientID: "client"}),
+//		testutil.WithLocalizer(localizer),
+//		testutil.WithRequest(httptest.NewRequest(http.MethodPost, "/webhook", body)),
+//	)
+//	handler(c)
+//	assert.Equal(t, http.StatusOK, rr.Code)
+func NewGinContext(opts ...GinContextOption) (*gin.Context, *httptest.ResponseRecorder) {
+	rr := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rr)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, rr
+}
+
+func contextKey(defaultKey string, key []string) string {
+	if len(key) > 0 && key[0] != "" {
+		return key[0]
+	}
+	return defaultKey
+}
@@ -0,0 +1,63 @@
+package testutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testGinLocalizer struct {
+	messages map[string]string
+}
+
+func (l *testGinLocalizer) GetLocalizedMessage(id string) string {
+	return l.messages[id]
+}
+
+func testGinHandler(c *gin.Context) {
+	conn := c.MustGet(ConnectionContextKey).(string)
+	acc := c.MustGet("acc").(string)
+	loc := c.MustGet(LocalizerContextKey).(*testGinLocalizer)
+
+	body, err := c.GetRawData()
+	if err != nil {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	c.String(http.StatusOK, "%s %s/%s: %s", loc.GetLocalizedMessage("hello"), conn, acc, body)
+}
+
+func TestNewGinContext(t *testing.T) {
+	c, rr := NewGinContext(
+		WithConnection("conn"),
+		WithAccount("acc", "acc"),
+		WithLocalizer(&testGinLocalizer{messages: map[string]string{"hello": "Hello"}}),
+		WithRequest(httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader("body"))),
+	)
+
+	testGinHandler(c)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "Hello conn/acc: body", rr.Body.String())
+	assert.Equal(t, "/webhook", c.Request.URL.Path)
+}
+
+func TestNewGinContext_Defaults(t *testing.T) {
+	c, rr := NewGinContext(WithContextValue("key", "value"))
+
+	require.NotNil(t, c.Request)
+	assert.Equal(t, http.MethodGet, c.Request.Method)
+	assert.Equal(t, "value", c.GetString("key"))
+	_, exists := c.Get(ConnectionContextKey)
+	assert.False(t, exists)
+
+	c.Status(http.StatusNoContent)
+	c.Writer.WriteHeaderNow()
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+}
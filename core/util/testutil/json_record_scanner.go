@@ -8,6 +8,13 @@ import (
 	"github.com/guregu/null/v5"
 )
 
+// maxLogRecordSize is the maximum size of the single log record which can be read by the JSONRecordScanner.
+const maxLogRecordSize = 1 << 20
+
+// LogRecord is a single log entry written by the logger with JSON format (see logger.NewJSONWithContextEncoder).
+// Special attributes (handler, connection, account, streamId) are placed at the top level of the record,
+// all other fields are grouped in the Context. Nested groups (zap.Dict, zap.Object) are represented
+// as the nested maps, use ContextValue to access them.
 type LogRecord struct {
 	LevelName  string                 `json:"level_name"`
 	DateTime   null.Time              `json:"datetime"`
@@ -19,22 +26,64 @@ type LogRecord struct {
 	Context    map[string]interface{} `json:"context,omitempty"`
 }
 
+// ContextValue returns value from the record context by its path. Every path element except the last one
+// must point to the nested group. Numbers are returned as float64.
+// Usage:
+//
+//	// log.Info("request", zap.Dict("request", zap.String("url", "https://example.com")))
+//	url, ok := record.ContextValue("request", "url")
+func (r LogRecord) ContextValue(path ...string) (interface{}, bool) {
+	if len(path) == 0 {
+		return nil, false
+	}
+
+	group := r.Context
+	for _, key := range path[:len(path)-1] {
+		nested, ok := group[key].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		group = nested
+	}
+
+	val, ok := group[path[len(path)-1]]
+	return val, ok
+}
+
+// JSONRecordScanner reads LogRecord's from the provided reader. Every line must contain a single record.
+// It can be used with the BufferedLogger to assert the log output in tests.
+// Usage:
+//
+//	log := testutil.NewBufferedLogger()
+//	// Some code that works with logger.
+//	records, err := testutil.NewJSONRecordScanner(log).ScanAll()
+//	require.NoError(t, err)
+//	assert.Equal(t, "expected message", records[0].Message)
 type JSONRecordScanner struct {
 	r *bufio.Scanner
 	e LogRecord
 }
 
+// NewJSONRecordScanner returns new JSONRecordScanner for the provided reader (for example, BufferedLogger).
 func NewJSONRecordScanner(entryProvider io.Reader) *JSONRecordScanner {
-	return &JSONRecordScanner{r: bufio.NewScanner(entryProvider)}
+	r := bufio.NewScanner(entryProvider)
+	r.Buffer(nil, maxLogRecordSize)
+	return &JSONRecordScanner{r: r}
 }
 
+// Scan reads the next record which will be available via Entry. It returns io.EOF if there are no more records.
 func (s *JSONRecordScanner) Scan() error {
 	if s.r.Scan() {
+		s.e = LogRecord{}
 		return json.Unmarshal(s.r.Bytes(), &s.e)
 	}
+	if err := s.r.Err(); err != nil {
+		return err
+	}
 	return io.EOF
 }
 
+// ScanAll reads all remaining records. Records which were read before the error are returned along with the error.
 func (s *JSONRecordScanner) ScanAll() ([]LogRecord, error) {
 	var entries []LogRecord
 	for s.r.Scan() {
@@ -44,9 +93,10 @@ func (s *JSONRecordScanner) ScanAll() ([]LogRecord, error) {
 		}
 		entries = append(entries, entry)
 	}
-	return entries, nil
+	return entries, s.r.Err()
 }
 
+// Entry returns the last record read by Scan.
 func (s *JSONRecordScanner) Entry() LogRecord {
 	return s.e
 }
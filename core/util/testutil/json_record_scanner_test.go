@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
 )
 
 type JSONRecordScannerTest struct {
@@ -106,3 +107,67 @@ func (t *JSONRecordScannerTest) TestScanAll_JSONRecord() {
 	t.Assert().Len(records, 1)
 	t.assertPredefined(records[0])
 }
+
+func (t *JSONRecordScannerTest) TestScan_ResetsEntry() {
+	rs := t.new([]string{`{"handler": "handlers.first", "context": {"key": "value"}}`, `{"message": "second"}`})
+	t.Require().NoError(rs.Scan())
+	t.Require().NoError(rs.Scan())
+	t.Assert().Equal("second", rs.Entry().Message)
+	t.Assert().Empty(rs.Entry().Handler)
+	t.Assert().Empty(rs.Entry().Context)
+}
+
+func (t *JSONRecordScannerTest) TestScanAll_BufferedLogger() {
+	log := NewBufferedLoggerSilent()
+	log.ForHandler("handlers.test").
+		ForConnection("https://example.com").
+		ForAccount("@account").
+		Error("test message",
+			zap.Int("statusCode", 500),
+			zap.Bool("retry", true),
+			zap.Float64("ratio", 0.5),
+			zap.Dict("request", zap.String("url", "https://example.com"), zap.Dict("headers",
+				zap.String("X-Test", "value"))))
+	log.Info("second message")
+
+	records, err := NewJSONRecordScanner(log).ScanAll()
+	t.Require().NoError(err)
+	t.Require().Len(records, 2)
+
+	record := records[0]
+	t.Assert().Equal("ERROR", record.LevelName)
+	t.Assert().True(record.DateTime.Valid)
+	t.Assert().Equal("test message", record.Message)
+	t.Assert().Equal("handlers.test", record.Handler)
+	t.Assert().Equal("https://example.com", record.Connection)
+	t.Assert().Equal("@account", record.Account)
+	t.Assert().NotContains(record.Context, "handler")
+
+	val, ok := record.ContextValue("statusCode")
+	t.Assert().True(ok)
+	t.Assert().Equal(float64(500), val)
+
+	val, _ = record.ContextValue("retry")
+	t.Assert().Equal(true, val)
+	val, _ = record.ContextValue("ratio")
+	t.Assert().Equal(0.5, val)
+
+	val, ok = record.ContextValue("request", "url")
+	t.Assert().True(ok)
+	t.Assert().Equal("https://example.com", val)
+
+	val, ok = record.ContextValue("request", "headers", "X-Test")
+	t.Assert().True(ok)
+	t.Assert().Equal("value", val)
+
+	_, ok = record.ContextValue("request", "url", "nested")
+	t.Assert().False(ok)
+	_, ok = record.ContextValue("missing")
+	t.Assert().False(ok)
+	_, ok = record.ContextValue()
+	t.Assert().False(ok)
+
+	t.Assert().Equal("INFO", records[1].LevelName)
+	t.Assert().Equal("second message", records[1].Message)
+	t.Assert().Empty(records[1].Handler)
+}
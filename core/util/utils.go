@@ -2,17 +2,22 @@ package util
 
 import (
 	"bytes"
+	"context"
 	// nolint:gosec
 	"crypto/sha1"
 	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
+	"unsafe"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
@@ -29,9 +34,23 @@ import (
 	"github.com/retailcrm/mg-transport-core/v2/core/util/errorutil"
 )
 
+// mgUploadFileByURLPath is the MG Transport API method for uploading files by URL.
+const mgUploadFileByURLPath = "/api/transport/v1/files/upload_by_url"
+
+// zeroWidthSpace is U+200B ZERO WIDTH SPACE character.
+const zeroWidthSpace = "\u200b"
+
 var (
 	markdownSymbols = []string{"*", "_", "`", "["}
 	slashRegex      = regexp.MustCompile(`/+$`)
+
+	telegramReplacer = newEscapeReplacer(markdownSymbols)
+	// WhatsApp doesn't support backslash escapes. Formatting is applied only if the symbol is directly adjacent
+	// to the text, so zero-width space is inserted after every symbol to keep it literal.
+	whatsAppReplacer = strings.NewReplacer(
+		"*", "*"+zeroWidthSpace, "_", "_"+zeroWidthSpace, "~", "~"+zeroWidthSpace, "`", "`"+zeroWidthSpace)
+	// Slack doesn't support escaping of the formatting symbols, only control characters must be encoded.
+	slackReplacer = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
 )
 
 var (
@@ -45,6 +64,37 @@ var (
 	}
 )
 
+// DefaultCurrencyPrecision is a number of decimals which will be used for currencies without known minor units.
+const DefaultCurrencyPrecision = 2
+
+// currencyPrecisions contains minor unit counts for currencies which don't use 2 decimals.
+var currencyPrecisions = map[string]int{
+	"bif": 0,
+	"clp": 0,
+	"djf": 0,
+	"gnf": 0,
+	"isk": 0,
+	"jpy": 0,
+	"kmf": 0,
+	"krw": 0,
+	"pyg": 0,
+	"rwf": 0,
+	"ugx": 0,
+	"vnd": 0,
+	"vuv": 0,
+	"xaf": 0,
+	"xof": 0,
+	"xpf": 0,
+	"bhd": 3,
+	"iqd": 3,
+	"jod": 3,
+	"kwd": 3,
+	"lyd": 3,
+	"omr": 3,
+	"tnd": 3,
+	"btc": 8,
+}
+
 var defaultCurrencies = map[string]string{
 	"rub": "₽",
 	"uah": "₴",
@@ -130,55 +180,80 @@ func (u *Utils) GenerateToken() string {
 	return fmt.Sprintf("%x", sha256.Sum256([]byte(fmt.Sprintf("%d%d", time.Now().UnixNano(), c))))
 }
 
+// CredentialInfo contains API key access information returned by the /api/credentials method.
+type CredentialInfo struct {
+	// SiteAccess is the API key site access mode.
+	SiteAccess string
+	// SitesAvailable contains codes of the sites which are available for the API key.
+	SitesAvailable []string
+}
+
+// HasSite returns true if site with provided code is available for the API key.
+func (c CredentialInfo) HasSite(code string) bool {
+	for _, site := range c.SitesAvailable {
+		if site == code {
+			return true
+		}
+	}
+	return false
+}
+
 // GetAPIClient will initialize RetailCRM api client from url and key.
 // Scopes will be used to determine if client is valid. If there are no scopes - credentials will be used instead.
 func (u *Utils) GetAPIClient(
 	url, key string, scopes []string, credentials ...[]string) (*retailcrm.Client, int, error) {
+	client, _, status, err := u.GetAPIClientWithInfo(url, key, scopes, credentials...)
+	return client, status, err
+}
+
+// GetAPIClientWithInfo works like GetAPIClient, but also returns API key site access information.
+func (u *Utils) GetAPIClientWithInfo(
+	url, key string, scopes []string, credentials ...[]string) (*retailcrm.Client, CredentialInfo, int, error) {
 	client := retailcrm.New(url, key).
 		WithLogger(logger.APIClientAdapter(u.Logger))
 	client.Debug = u.IsDebug
 
 	cr, status, err := client.APICredentials()
 	if err != nil {
-		return nil, status, err
+		return nil, CredentialInfo{}, status, err
 	}
 
 	if res := u.checkScopes(cr.Scopes, scopes); len(res) != 0 {
 		if len(credentials) == 0 || len(cr.Scopes) > 0 {
 			u.Logger.Error(url, logger.HTTPStatusCode(status), logger.Body(res))
-			return nil, http.StatusBadRequest, errorutil.NewInsufficientScopesErr(res)
+			return nil, CredentialInfo{}, http.StatusBadRequest, errorutil.NewInsufficientScopesErr(res)
 		}
 
 		if res := u.checkScopes(cr.Credentials, credentials[0]); len(res) != 0 {
 			u.Logger.Error(url, logger.HTTPStatusCode(status), logger.Body(res))
-			return nil, http.StatusBadRequest, errorutil.NewInsufficientScopesErr(res)
+			return nil, CredentialInfo{}, http.StatusBadRequest, errorutil.NewInsufficientScopesErr(res)
 		}
 	}
 
-	return client, 0, nil
+	return client, CredentialInfo{SiteAccess: cr.SiteAccess, SitesAvailable: cr.SitesAvailable}, 0, nil
 }
 
+// checkScopes returns required scopes which are not present in scopes. Order of the required scopes is preserved.
 func (u *Utils) checkScopes(scopes []string, scopesRequired []string) []string {
-	rs := make([]string, len(scopesRequired))
-	copy(rs, scopesRequired)
-
-	for _, vs := range scopes {
-		for kn, vn := range rs {
-			if vn == vs {
-				if len(rs) == 1 {
-					rs = rs[:0]
-					break
-				}
-				rs = append(rs[:kn], rs[kn+1:]...)
-			}
+	granted := NewSet(scopes...)
+	missing := make([]string, 0, len(scopesRequired))
+	for _, scope := range scopesRequired {
+		if !granted.Contains(scope) {
+			missing = append(missing, scope)
 		}
 	}
 
-	return rs
+	return missing
 }
 
 // UploadUserAvatar will upload avatar for user.
 func (u *Utils) UploadUserAvatar(url string) (picURLs3 string, err error) {
+	body, _, err := FetchRemote(context.Background(), url, DefaultAvatarMaxSize, AvatarContentTypes)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
 	s3Config := &aws.Config{
 		Credentials: credentials.NewStaticCredentials(
 			u.AWS.AccessKeyID,
@@ -194,21 +269,10 @@ func (u *Utils) UploadUserAvatar(url string) (picURLs3 string, err error) {
 	s := session.Must(session.NewSession(s3Config))
 	uploader := s3manager.NewUploader(s)
 
-	// nolint:gosec
-	resp, err := http.Get(url)
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= http.StatusBadRequest {
-		return "", fmt.Errorf("get: %v code: %v", url, resp.StatusCode)
-	}
-
 	result, err := uploader.Upload(&s3manager.UploadInput{
 		Bucket:      aws.String(u.AWS.Bucket),
 		Key:         aws.String(fmt.Sprintf("%v/%v.jpg", u.AWS.FolderName, u.GenerateToken())),
-		Body:        resp.Body,
+		Body:        body,
 		ContentType: aws.String(u.AWS.ContentType),
 		ACL:         aws.String("public-read"),
 	})
@@ -228,13 +292,15 @@ func (u *Utils) RemoveTrailingSlash(crmURL string) string {
 
 // GetMGItemData will upload file to MG by URL and return information about attachable item.
 func GetMGItemData(client *v1.MgClient, url string, caption string) (v1.Item, int, error) {
+	return GetMGItemDataContext(context.Background(), client, url, caption)
+}
+
+// GetMGItemDataContext is the same as GetMGItemData, but upload will be aborted if provided context is done.
+// MG client doesn't support contexts, so the request is sent via the MG client *http.Client using its URL and token.
+func GetMGItemDataContext(ctx context.Context, client *v1.MgClient, url, caption string) (v1.Item, int, error) {
 	item := v1.Item{}
 
-	data, st, err := client.UploadFileByURL(
-		v1.UploadFileByUrlRequest{
-			Url: url,
-		},
-	)
+	data, st, err := uploadFileByURL(ctx, client, v1.UploadFileByUrlRequest{Url: url})
 	if err != nil {
 		return item, st, err
 	}
@@ -245,7 +311,59 @@ func GetMGItemData(client *v1.MgClient, url string, caption string) (v1.Item, in
 	return item, st, err
 }
 
+// uploadFileByURL mirrors v1.MgClient.UploadFileByURL with the context support.
+func uploadFileByURL(
+	ctx context.Context, client *v1.MgClient, request v1.UploadFileByUrlRequest) (v1.UploadFileResponse, int, error) {
+	var resp v1.UploadFileResponse
+	outgoing, _ := json.Marshal(&request)
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, client.URL+mgUploadFileByURLPath, bytes.NewBuffer(outgoing))
+	if err != nil {
+		return resp, 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Transport-Token", client.Token)
+
+	client.WaitForRateLimit()
+	httpResp, err := mgHTTPClient(client).Do(req)
+	if err != nil {
+		return resp, 0, v1.NewCriticalHTTPError(err)
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	if httpResp.StatusCode >= http.StatusInternalServerError {
+		return resp, httpResp.StatusCode, v1.NewServerError(httpResp)
+	}
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return resp, 0, err
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return resp, httpResp.StatusCode, err
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return resp, httpResp.StatusCode, v1.NewAPIClientError(body)
+	}
+
+	return resp, httpResp.StatusCode, nil
+}
+
+// mgHTTPClient returns *http.Client of the MG client. MG client doesn't expose it, so it's read via reflection.
+// http.DefaultClient is returned if MG client has no *http.Client.
+func mgHTTPClient(client *v1.MgClient) *http.Client {
+	field := reflect.ValueOf(client).Elem().FieldByName("httpClient")
+	if !field.IsValid() || field.Type() != reflect.TypeOf(&http.Client{}) || field.IsNil() {
+		return http.DefaultClient
+	}
+
+	return (*http.Client)(unsafe.Pointer(field.Pointer())) // nolint:gosec
+}
+
 // GetEntitySHA1 will serialize any value to JSON and return SHA1 hash of this JSON.
+// SHA1 is not collision-resistant, so it must not be used for the security purposes. It's kept for BC,
+// use GetEntitySHA256 in the new code.
 func GetEntitySHA1(v interface{}) (hash string, err error) {
 	res, _ := json.Marshal(v)
 
@@ -257,13 +375,101 @@ func GetEntitySHA1(v interface{}) (hash string, err error) {
 	return
 }
 
-// ReplaceMarkdownSymbols will remove markdown symbols from text.
+// GetEntitySHA256 will serialize any value to JSON and return SHA256 hash of this JSON.
+func GetEntitySHA256(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
+
+// ErrNotStruct is returned by GetEntityHashFields if provided value is not a struct or a pointer to struct.
+var ErrNotStruct = errors.New("value is not a struct")
+
+// GetEntityHashFields returns SHA1 hash of the selected struct fields. Fields can be referenced by their Go names
+// or by their JSON names. Other fields (timestamps, etc.) don't affect the hash, so it can be used as a stable
+// deduplication key. Unknown field name results in error.
+// Usage:
+//
+//	key, err := util.GetEntityHashFields(message, []string{"ChatID", "Text"})
+func GetEntityHashFields(v interface{}, fields []string) (string, error) {
+	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return "", ErrNotStruct
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return "", ErrNotStruct
+	}
+
+	selected := make(map[string]interface{}, len(fields))
+	for _, name := range fields {
+		field, goName, ok := structFieldByName(val, name)
+		if !ok {
+			return "", fmt.Errorf("unknown field `%s` in %s", name, val.Type())
+		}
+		selected[goName] = field.Interface()
+	}
+
+	// Map keys are sorted by json.Marshal, so the order of fields doesn't matter.
+	data, err := json.Marshal(selected)
+	if err != nil {
+		return "", err
+	}
+
+	// nolint:gosec
+	return fmt.Sprintf("%x", sha1.Sum(data)), nil
+}
+
+// structFieldByName returns exported struct field and its Go name by its Go or JSON name.
+func structFieldByName(val reflect.Value, name string) (reflect.Value, string, bool) {
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if field.Name == name || (jsonName != "" && jsonName != "-" && jsonName == name) {
+			return val.Field(i), field.Name, true
+		}
+	}
+
+	return reflect.Value{}, "", false
+}
+
+// ReplaceMarkdownSymbols will remove markdown symbols from text. It's an alias for EscapeTelegram.
 func ReplaceMarkdownSymbols(s string) string {
-	for _, v := range markdownSymbols {
-		s = strings.ReplaceAll(s, v, "\\"+v)
+	return EscapeTelegram(s)
+}
+
+// EscapeTelegram escapes Telegram markdown symbols: * _ ` [.
+func EscapeTelegram(s string) string {
+	return telegramReplacer.Replace(s)
+}
+
+// EscapeWhatsApp escapes WhatsApp formatting symbols: * _ ~ `. Every symbol is followed by the zero-width space,
+// so WhatsApp renders it as is: "*bold*" is shown as "*bold*" rather than in bold.
+func EscapeWhatsApp(s string) string {
+	return whatsAppReplacer.Replace(s)
+}
+
+// EscapeSlack escapes Slack mrkdwn control characters: & < >. They're replaced with HTML entities.
+func EscapeSlack(s string) string {
+	return slackReplacer.Replace(s)
+}
+
+func newEscapeReplacer(symbols []string) *strings.Replacer {
+	pairs := make([]string, 0, len(symbols)*2) // nolint:mnd
+	for _, symbol := range symbols {
+		pairs = append(pairs, symbol, "\\"+symbol)
 	}
 
-	return s
+	return strings.NewReplacer(pairs...)
 }
 
 // DefaultCurrencies will return default currencies list for all bots.
@@ -285,14 +491,54 @@ func FormatCurrencyValue(value float32) string {
 	return fmt.Sprintf("%.2f", value)
 }
 
-// BindJSONWithRaw will perform usual ShouldBindJSON and will return the original body data.
-func BindJSONWithRaw(c *gin.Context, obj any) ([]byte, error) {
+// GetCurrencyPrecision returns number of decimals (minor units) for the currency by it's ISO 4217 code.
+// It returns DefaultCurrencyPrecision if currency is unknown.
+func GetCurrencyPrecision(code string) int {
+	if precision, ok := currencyPrecisions[strings.ToLower(code)]; ok {
+		return precision
+	}
+
+	return DefaultCurrencyPrecision
+}
+
+// FormatCurrencyValuePrecision formats value using number of decimals of the provided currency.
+// Value is float64 because float32 cannot hold amounts with 8 decimals (like BTC) precisely.
+func FormatCurrencyValuePrecision(value float64, code string) string {
+	return strconv.FormatFloat(value, 'f', GetCurrencyPrecision(code), 64)
+}
+
+// RawBody returns request body. Body is read only once and cached in the context (the same key is used by
+// gin.Context.ShouldBindBodyWith), request body is always restored, so it can be read again by the handler.
+func RawBody(c *gin.Context) ([]byte, error) {
+	if cached, ok := c.Get(gin.BodyBytesKey); ok {
+		if data, ok := cached.([]byte); ok {
+			c.Request.Body = io.NopCloser(bytes.NewReader(data))
+			return data, nil
+		}
+	}
+	if c.Request == nil || c.Request.Body == nil {
+		return []byte{}, nil
+	}
+
 	closer := c.Request.Body
 	defer func() { _ = closer.Close() }()
 	data, err := io.ReadAll(closer)
 	if err != nil {
 		return []byte{}, err
 	}
+	c.Set(gin.BodyBytesKey, data)
+	c.Request.Body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// BindJSONWithRaw will perform usual ShouldBindJSON and will return the original body data.
+// Body is read via RawBody, so it won't be read again by the other RawBody consumers.
+func BindJSONWithRaw(c *gin.Context, obj any) ([]byte, error) {
+	data, err := RawBody(c)
+	if err != nil {
+		return []byte{}, err
+	}
+	err = c.ShouldBindJSON(obj)
 	c.Request.Body = io.NopCloser(bytes.NewReader(data))
-	return data, c.ShouldBindJSON(obj)
+	return data, err
 }
@@ -1,13 +1,17 @@
 package util
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/h2non/gock"
 
 	retailcrm "github.com/retailcrm/api-client-go/v2"
@@ -165,6 +169,57 @@ func (u *UtilsTest) Test_GetAPIClient_SuccessCredentials() {
 	assert.Equal(u.T(), 0, status)
 }
 
+func (u *UtilsTest) Test_GetAPIClientWithInfo() {
+	resp := retailcrm.CredentialResponse{
+		Success:        true,
+		Scopes:         DefaultScopes,
+		SiteAccess:     "access_selective",
+		SitesAvailable: []string{"site-1", "site-2"},
+	}
+
+	data, _ := json.Marshal(resp)
+
+	defer gock.Off()
+	gock.New(testCRMURL).
+		Get("/credentials").
+		Reply(http.StatusOK).
+		BodyString(string(data))
+
+	client, info, status, err := u.utils.GetAPIClientWithInfo(testCRMURL, "key", DefaultScopes)
+	require.NoError(u.T(), err)
+	assert.NotNil(u.T(), client)
+	assert.Equal(u.T(), 0, status)
+	assert.Equal(u.T(), CredentialInfo{
+		SiteAccess:     "access_selective",
+		SitesAvailable: []string{"site-1", "site-2"},
+	}, info)
+	assert.True(u.T(), info.HasSite("site-2"))
+	assert.False(u.T(), info.HasSite("site-3"))
+}
+
+func (u *UtilsTest) Test_GetAPIClientWithInfo_FailAPIScopes() {
+	resp := retailcrm.CredentialResponse{
+		Success:        true,
+		Scopes:         []string{},
+		SiteAccess:     "access_full",
+		SitesAvailable: []string{"site"},
+	}
+
+	data, _ := json.Marshal(resp)
+
+	defer gock.Off()
+	gock.New(testCRMURL).
+		Get("/credentials").
+		Reply(http.StatusOK).
+		BodyString(string(data))
+
+	client, info, status, err := u.utils.GetAPIClientWithInfo(testCRMURL, "key", DefaultScopes)
+	assert.ErrorIs(u.T(), err, errorutil.ErrInsufficientScopes)
+	assert.Nil(u.T(), client)
+	assert.Equal(u.T(), http.StatusBadRequest, status)
+	assert.Empty(u.T(), info)
+}
+
 func (u *UtilsTest) Test_GetAPIClient_Success() {
 	resp := retailcrm.CredentialResponse{
 		Success:        true,
@@ -207,6 +262,19 @@ func (u *UtilsTest) Test_UploadUserAvatar_FailBadRequest() {
 	assert.Error(u.T(), err)
 }
 
+func (u *UtilsTest) Test_UploadUserAvatar_FailContentType() {
+	defer gock.Off()
+	gock.New("https://example.com").
+		Get("/image.jpg").
+		Reply(200).
+		SetHeader("Content-Type", "text/html").
+		BodyString(`<html></html>`)
+
+	uri, err := u.utils.UploadUserAvatar("https://example.com/image.jpg")
+	assert.Empty(u.T(), uri)
+	assert.ErrorIs(u.T(), err, ErrRemoteContentType)
+}
+
 func (u *UtilsTest) Test_RemoveTrailingSlash() {
 	assert.Equal(u.T(), testCRMURL, u.utils.RemoveTrailingSlash(testCRMURL+"/"))
 	assert.Equal(u.T(), testCRMURL, u.utils.RemoveTrailingSlash(testCRMURL))
@@ -225,6 +293,11 @@ func (u *UtilsTest) TestUtils_CheckScopes() {
 	diff = u.utils.checkScopes(scopes, required)
 	assert.Equal(u.T(), 2, len(diff))
 	assert.Equal(u.T(), []string{"one", "two"}, diff)
+
+	scopes = []string{"two", "three"}
+
+	diff = u.utils.checkScopes(scopes, []string{"three", "one", "four", "two"})
+	assert.Equal(u.T(), []string{"one", "four"}, diff)
 }
 
 func TestUtils_GetMGItemData_FailRuntime_GetImage(t *testing.T) {
@@ -295,6 +368,79 @@ func TestUtils_GetMGItemData_Success(t *testing.T) {
 	assert.Equal(t, "caption", response.Caption)
 }
 
+func TestUtils_GetMGItemDataContext_Canceled(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testMGURL).
+		Post("/files/upload_by_url").
+		Reply(http.StatusOK).
+		Delay(time.Second).
+		JSON(v1.UploadFileResponse{ID: "file id"})
+
+	transport := &countingTransport{base: http.DefaultTransport}
+	client := v1.NewWithClient(testMGURL, "token", &http.Client{Transport: transport})
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	started := time.Now()
+	_, status, err := GetMGItemDataContext(ctx, client, "https://example.com/item.jpg", "")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, status)
+	assert.Less(t, time.Since(started), time.Second)
+	assert.Equal(t, 1, transport.requests)
+	assert.ErrorIs(t, transport.err, context.Canceled, "request must be aborted by the transport")
+}
+
+func TestUtils_GetMGItemDataContext_Success(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testMGURL).
+		Post("/files/upload_by_url").
+		MatchHeader("X-Transport-Token", "token").
+		BodyString(`{"url":"https://example.com/item.jpg"}`).
+		Reply(http.StatusOK).
+		JSON(v1.UploadFileResponse{ID: "file id"})
+
+	item, status, err := GetMGItemDataContext(
+		context.Background(), mgClient(), "https://example.com/item.jpg", "caption")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "file id", item.ID)
+	assert.Equal(t, "caption", item.Caption)
+}
+
+type countingTransport struct {
+	base     http.RoundTripper
+	err      error
+	requests int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+	resp, err := t.base.RoundTrip(req)
+	t.err = err
+	return resp, err
+}
+
+func TestUtils_GetMGItemDataContext_ClientTransport(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testMGURL).
+		Post("/files/upload_by_url").
+		Reply(http.StatusOK).
+		JSON(v1.UploadFileResponse{ID: "file id"})
+
+	transport := &countingTransport{base: http.DefaultTransport}
+	client := v1.NewWithClient(testMGURL, "token", &http.Client{Transport: transport})
+
+	item, status, err := GetMGItemDataContext(context.Background(), client, "https://example.com/item.jpg", "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "file id", item.ID)
+	assert.Equal(t, 1, transport.requests)
+}
+
 func TestUtils_GetEntitySHA1(t *testing.T) {
 	entity := struct {
 		Field string
@@ -307,6 +453,62 @@ func TestUtils_GetEntitySHA1(t *testing.T) {
 	assert.Equal(t, "751b56fb98c9fd803140e8287b4236675554a668", hash)
 }
 
+func TestUtils_GetEntitySHA256(t *testing.T) {
+	entity := struct {
+		Field string
+	}{
+		Field: "value",
+	}
+
+	hash, err := GetEntitySHA256(entity)
+	require.NoError(t, err)
+	assert.Len(t, hash, 64)
+
+	again, err := GetEntitySHA256(entity)
+	require.NoError(t, err)
+	assert.Equal(t, hash, again)
+
+	sha1Hash, err := GetEntitySHA1(entity)
+	require.NoError(t, err)
+	assert.NotEqual(t, sha1Hash, hash)
+
+	_, err = GetEntitySHA256(make(chan int))
+	assert.Error(t, err)
+}
+
+func TestUtils_GetEntityHashFields(t *testing.T) {
+	type message struct {
+		SentAt time.Time `json:"sentAt"`
+		ChatID int       `json:"chatId"`
+		Text   string    `json:"text"`
+	}
+	first := message{ChatID: 1, Text: "hello", SentAt: time.Now()}
+	second := message{ChatID: 1, Text: "hello", SentAt: time.Now().Add(time.Hour)}
+
+	firstHash, err := GetEntityHashFields(first, []string{"ChatID", "text"})
+	require.NoError(t, err)
+	secondHash, err := GetEntityHashFields(&second, []string{"Text", "chatId"})
+	require.NoError(t, err)
+	assert.Equal(t, firstHash, secondHash)
+	assert.Len(t, firstHash, 40)
+
+	second.Text = "bye"
+	secondHash, err = GetEntityHashFields(second, []string{"ChatID", "text"})
+	require.NoError(t, err)
+	assert.NotEqual(t, firstHash, secondHash)
+}
+
+func TestUtils_GetEntityHashFields_Errors(t *testing.T) {
+	_, err := GetEntityHashFields("string", []string{"Field"})
+	assert.ErrorIs(t, err, ErrNotStruct)
+
+	_, err = GetEntityHashFields((*struct{})(nil), []string{"Field"})
+	assert.ErrorIs(t, err, ErrNotStruct)
+
+	_, err = GetEntityHashFields(struct{ Field string }{}, []string{"Missing"})
+	assert.EqualError(t, err, "unknown field `Missing` in struct { Field string }")
+}
+
 func TestUtils_GetCurrencySymbol(t *testing.T) {
 	for code := range DefaultCurrencies() {
 		if strings.ToUpper(code) == defaultCurrencies[code] {
@@ -326,6 +528,42 @@ func TestUtils_ReplaceMarkdownSymbols(t *testing.T) {
 	assert.Equal(t, expected, ReplaceMarkdownSymbols(test))
 }
 
+func TestUtils_EscapeTelegram(t *testing.T) {
+	assert.Equal(t, "\\*bold\\* \\_italic\\_ \\`code\\` \\[link](url) ~strike~ <a> &",
+		EscapeTelegram("*bold* _italic_ `code` [link](url) ~strike~ <a> &"))
+}
+
+func TestUtils_EscapeWhatsApp(t *testing.T) {
+	assert.Equal(t, "*\u200bbold*\u200b _\u200bitalic_\u200b ~\u200bstrike~\u200b "+
+		"`\u200b`\u200b`\u200bmono`\u200b`\u200b`\u200b [link] <a> &",
+		EscapeWhatsApp("*bold* _italic_ ~strike~ ```mono``` [link] <a> &"))
+}
+
+// WhatsApp applies formatting only if the symbol is directly followed by the text (opening symbol) and
+// "```" is a continuous sequence. Escaped text must not contain any formatting symbol followed by anything
+// but the zero-width space, and it must be the same as the original text when zero-width spaces are removed.
+func TestUtils_EscapeWhatsApp_RenderingRule(t *testing.T) {
+	for _, text := range []string{
+		"*bold*", "_italic_", "~strike~", "```mono```", "`code`", "**", "a*b_c~d`e", "*_~`", "plain text",
+	} {
+		escaped := EscapeWhatsApp(text)
+		assert.Equal(t, text, strings.ReplaceAll(escaped, "\u200b", ""), text)
+
+		runes := []rune(escaped)
+		for i, r := range runes {
+			if strings.ContainsRune("*_~`", r) {
+				require.Less(t, i+1, len(runes), text)
+				assert.Equal(t, '\u200b', runes[i+1], text)
+			}
+		}
+	}
+}
+
+func TestUtils_EscapeSlack(t *testing.T) {
+	assert.Equal(t, "*bold* _italic_ ~strike~ `code` [link] &lt;@U123&gt; &amp;amp;",
+		EscapeSlack("*bold* _italic_ ~strike~ `code` [link] <@U123> &amp;"))
+}
+
 func TestUtils_FormatCurrencyValue(t *testing.T) {
 	assert.Equal(t, "-1.00", FormatCurrencyValue(-1))
 	assert.Equal(t, "100.00", FormatCurrencyValue(100))
@@ -334,6 +572,64 @@ func TestUtils_FormatCurrencyValue(t *testing.T) {
 	assert.Equal(t, "1000500.00", FormatCurrencyValue(1000500))
 }
 
+func TestUtils_FormatCurrencyValuePrecision(t *testing.T) {
+	assert.Equal(t, "1235", FormatCurrencyValuePrecision(1234.56, "JPY"))
+	assert.Equal(t, "100", FormatCurrencyValuePrecision(100, "krw"))
+	assert.Equal(t, "1234.56", FormatCurrencyValuePrecision(1234.56, "RUB"))
+	assert.Equal(t, "123.46", FormatCurrencyValuePrecision(123.456789, "rub"))
+	assert.Equal(t, "1.500", FormatCurrencyValuePrecision(1.5, "KWD"))
+	assert.Equal(t, "10.00", FormatCurrencyValuePrecision(10, "unknown"))
+	assert.Equal(t, "12345.12345678", FormatCurrencyValuePrecision(12345.12345678, "BTC"))
+	assert.Equal(t, "0.00000001", FormatCurrencyValuePrecision(0.00000001, "btc"))
+}
+
+type countingReader struct {
+	io.Reader
+	read int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.read += n
+	return n, err
+}
+
+func TestUtils_RawBody(t *testing.T) {
+	body := &countingReader{Reader: strings.NewReader(`{"name":"test"}`)}
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/", io.NopCloser(body))
+
+	data, err := RawBody(c)
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"test"}`, string(data))
+
+	var req struct {
+		Name string `json:"name"`
+	}
+	raw, err := BindJSONWithRaw(c, &req)
+	require.NoError(t, err)
+	assert.Equal(t, data, raw)
+	assert.Equal(t, "test", req.Name)
+
+	data, err = RawBody(c)
+	require.NoError(t, err)
+	assert.Equal(t, raw, data)
+
+	restored, err := io.ReadAll(c.Request.Body)
+	require.NoError(t, err)
+	assert.Equal(t, raw, restored)
+	assert.Equal(t, len(raw), body.read)
+}
+
+func TestUtils_RawBody_NoBody(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = &http.Request{Method: http.MethodGet}
+
+	data, err := RawBody(c)
+	require.NoError(t, err)
+	assert.Empty(t, data)
+}
+
 func TestUtils_Suite(t *testing.T) {
 	suite.Run(t, new(UtilsTest))
 }
@@ -0,0 +1,139 @@
+package core
+
+import (
+	"errors"
+	"math"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/blacked/go-zabbix"
+	metrics "github.com/retailcrm/zabbix-metrics-collector"
+	"go.uber.org/zap"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/logger"
+)
+
+// ZabbixMaxBackoffMultiplier limits the delay between failed attempts to send metrics to Zabbix.
+// Maximum delay is equal to the interval multiplied by this value.
+const ZabbixMaxBackoffMultiplier = 16
+
+// zabbixIdleInterval is the send interval (in seconds) of the wrapped transport. Metrics are sent by zabbixTransport,
+// so the wrapped transport loop must never send them by itself.
+const zabbixIdleInterval = uint64(math.MaxInt64 / int64(time.Second))
+
+// ZabbixHealthChecker is a metrics.Transport which can report whether last metrics submission was successful.
+type ZabbixHealthChecker interface {
+	Healthy() bool
+}
+
+// zabbixTransport wraps the transport from the zabbix-metrics-collector. Wrapped transport runs and stops
+// the collectors and sends the metrics, zabbixTransport only schedules sending with backoff in case of errors
+// (so it doesn't spin when Zabbix is down), logs errors using structured logger and tracks the last result.
+type zabbixTransport struct {
+	transport metrics.Transport
+	send      func() error
+	logger    logger.Logger
+	done      chan struct{}
+	interval  time.Duration
+	maxDelay  time.Duration
+	mu        sync.Mutex
+	sending   sync.Mutex
+	healthy   atomic.Bool
+}
+
+// newZabbixTransport creates metrics transport for Zabbix server.
+func newZabbixTransport(
+	collectors []metrics.Collector, sender *zabbix.Sender, metricsHost string, interval time.Duration, log logger.Logger,
+) *zabbixTransport {
+	transport := metrics.NewZabbix(
+		collectors, sender, metricsHost, zabbixIdleInterval, logger.ZabbixCollectorAdapter(log))
+	t := &zabbixTransport{
+		transport: transport,
+		send:      transport.(interface{ Send() error }).Send,
+		logger:    log,
+		interval:  interval,
+		maxDelay:  interval * ZabbixMaxBackoffMultiplier,
+	}
+	t.healthy.Store(true)
+	runtime.SetFinalizer(t, metrics.StoppableFinalizer)
+	return t
+}
+
+// WithCollector adds collector to the list of collectors that will be used for metrics collection.
+func (t *zabbixTransport) WithCollector(col metrics.Collector) metrics.Transport {
+	t.sending.Lock()
+	defer t.sending.Unlock()
+	t.transport.WithCollector(col)
+	return t
+}
+
+// Run starts the wrapped transport (it runs runnable collectors) and sends metrics in the background
+// until Stop is called. Delay between sends is doubled after every failure until it reaches the maximum,
+// and reset after success.
+func (t *zabbixTransport) Run() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.done != nil {
+		return
+	}
+
+	t.done = make(chan struct{})
+	t.transport.Run()
+	go t.sendLoop(t.done)
+}
+
+func (t *zabbixTransport) sendLoop(done chan struct{}) {
+	delay := t.interval
+	for {
+		timer := time.NewTimer(delay)
+		select {
+		case <-done:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := t.Send(); err != nil {
+			delay *= 2
+			if delay > t.maxDelay {
+				delay = t.maxDelay
+			}
+			t.healthy.Store(false)
+			t.logger.Error("cannot send metrics to Zabbix", logger.Err(err), zap.Duration("retryIn", delay))
+			continue
+		}
+
+		delay = t.interval
+		t.healthy.Store(true)
+	}
+}
+
+// Send metrics to Zabbix using the wrapped transport.
+func (t *zabbixTransport) Send() error {
+	t.sending.Lock()
+	defer t.sending.Unlock()
+	return t.send()
+}
+
+// Stop Zabbix transport and stoppable collectors.
+func (t *zabbixTransport) Stop() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.done == nil {
+		return metrics.ErrTransportInactive
+	}
+
+	close(t.done)
+	t.done = nil
+	if err := t.transport.Stop(); err != nil && !errors.Is(err, metrics.ErrTransportInactive) {
+		return err
+	}
+	return nil
+}
+
+// Healthy returns false if the last attempt to send metrics has failed.
+func (t *zabbixTransport) Healthy() bool {
+	return t.healthy.Load()
+}
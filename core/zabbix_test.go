@@ -0,0 +1,212 @@
+package core
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blacked/go-zabbix"
+	metrics "github.com/retailcrm/zabbix-metrics-collector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/retailcrm/mg-transport-core/v2/core/util/testutil"
+)
+
+// zabbixServerMock accepts Zabbix sender protocol packets.
+type zabbixServerMock struct {
+	listener net.Listener
+	packets  []*zabbix.Packet
+	mu       sync.Mutex
+}
+
+func newZabbixServerMock(t *testing.T) *zabbixServerMock {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &zabbixServerMock{listener: listener}
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			server.handle(conn)
+		}
+	}()
+	return server
+}
+
+func (s *zabbixServerMock) handle(conn net.Conn) {
+	defer conn.Close()
+	header := make([]byte, 13) // "ZBXD\x01" and 8 bytes of data length.
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return
+	}
+	data := make([]byte, binary.LittleEndian.Uint64(header[5:]))
+	if _, err := io.ReadFull(conn, data); err != nil {
+		return
+	}
+
+	var packet zabbix.Packet
+	if err := json.Unmarshal(data, &packet); err == nil {
+		s.mu.Lock()
+		s.packets = append(s.packets, &packet)
+		s.mu.Unlock()
+	}
+	_, _ = conn.Write([]byte(`{"response":"success"}`))
+}
+
+func (s *zabbixServerMock) Sender() *zabbix.Sender {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return zabbix.NewSender(addr.IP.String(), addr.Port)
+}
+
+func (s *zabbixServerMock) Packets() []*zabbix.Packet {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*zabbix.Packet{}, s.packets...)
+}
+
+// zabbixSendMock replaces metrics submission of the transport.
+type zabbixSendMock struct {
+	calls    []time.Time
+	failures int
+	mu       sync.Mutex
+}
+
+func (s *zabbixSendMock) Send() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, time.Now())
+	if len(s.calls) <= s.failures {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (s *zabbixSendMock) Calls() []time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]time.Time{}, s.calls...)
+}
+
+type zabbixCollectorMock []metrics.Metric
+
+func (c zabbixCollectorMock) Metrics() []metrics.Metric {
+	return c
+}
+
+type zabbixRunnableCollectorMock struct {
+	zabbixCollectorMock
+	running atomic.Bool
+}
+
+func (c *zabbixRunnableCollectorMock) Run() {
+	c.running.Store(true)
+}
+
+func (c *zabbixRunnableCollectorMock) Stop() error {
+	c.running.Store(false)
+	return nil
+}
+
+func TestZabbixTransport_Run(t *testing.T) {
+	server := newZabbixServerMock(t)
+	collector := &zabbixRunnableCollectorMock{
+		zabbixCollectorMock: zabbixCollectorMock{{Name: "metric", Value: "1"}},
+	}
+	transport := newZabbixTransport(nil, server.Sender(), "host", 10*time.Millisecond,
+		testutil.NewBufferedLoggerSilent())
+	transport.WithCollector(collector)
+
+	// Run doesn't block like the wrapped transport Run.
+	transport.Run()
+	require.Eventually(t, func() bool {
+		return len(server.Packets()) >= 2
+	}, time.Second, time.Millisecond)
+	assert.True(t, collector.running.Load())
+	assert.True(t, transport.Healthy())
+	require.NoError(t, transport.Stop())
+	require.Eventually(t, func() bool {
+		return !collector.running.Load()
+	}, time.Second, time.Millisecond)
+
+	packet := server.Packets()[0]
+	require.Len(t, packet.Data, 1)
+	assert.Equal(t, "host", packet.Data[0].Host)
+	assert.Equal(t, "metric", packet.Data[0].Key)
+	assert.Equal(t, "1", packet.Data[0].Value)
+}
+
+func TestZabbixTransport_Backoff(t *testing.T) {
+	log := testutil.NewBufferedLoggerSilent()
+	sender := &zabbixSendMock{failures: 3}
+	transport := newZabbixTransport(nil, nil, "host", 10*time.Millisecond, log)
+	transport.send = sender.Send
+
+	transport.Run()
+	require.Eventually(t, func() bool {
+		return len(sender.Calls()) == 3
+	}, time.Second, time.Millisecond)
+	assert.False(t, transport.Healthy())
+
+	require.Eventually(t, func() bool {
+		return len(sender.Calls()) >= 5
+	}, time.Second, time.Millisecond)
+	require.NoError(t, transport.Stop())
+	assert.True(t, transport.Healthy())
+
+	calls := sender.Calls()
+	assert.GreaterOrEqual(t, calls[2].Sub(calls[1]), 40*time.Millisecond)
+	assert.GreaterOrEqual(t, calls[3].Sub(calls[2]), 80*time.Millisecond)
+	assert.Less(t, calls[4].Sub(calls[3]), 80*time.Millisecond)
+
+	items, err := testutil.NewJSONRecordScanner(log).ScanAll()
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+	for _, item := range items {
+		assert.Equal(t, "cannot send metrics to Zabbix", item.Message)
+		assert.Equal(t, "connection refused", item.Context["error"])
+	}
+}
+
+func TestZabbixTransport_MaxDelay(t *testing.T) {
+	sender := &zabbixSendMock{failures: 100}
+	transport := newZabbixTransport(nil, nil, "host", 5*time.Millisecond, testutil.NewBufferedLoggerSilent())
+	transport.send = sender.Send
+	transport.maxDelay = 20 * time.Millisecond
+
+	transport.Run()
+	require.Eventually(t, func() bool {
+		return len(sender.Calls()) >= 6
+	}, time.Second, time.Millisecond)
+	require.NoError(t, transport.Stop())
+
+	calls := sender.Calls()
+	assert.Less(t, calls[5].Sub(calls[4]), 100*time.Millisecond)
+}
+
+func TestZabbixTransport_StopInactive(t *testing.T) {
+	transport := newZabbixTransport(nil, nil, "host", time.Second, testutil.NewBufferedLoggerSilent())
+
+	assert.ErrorIs(t, transport.Stop(), metrics.ErrTransportInactive)
+	assert.True(t, transport.Healthy())
+}
+
+func TestEngine_ZabbixHealthy(t *testing.T) {
+	e := &Engine{}
+	assert.False(t, e.ZabbixHealthy())
+
+	e.Zabbix = newZabbixTransport(nil, nil, "host", time.Second, testutil.NewBufferedLoggerSilent())
+	assert.True(t, e.ZabbixHealthy())
+}